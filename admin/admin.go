@@ -0,0 +1,127 @@
+// Package admin provides an HTTP/JSON API exposing a manager's state
+// and controls, including a Server-Sent Events stream of its events.
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/target"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+type Handler struct {
+	mgr *manager.Manager
+	mux *http.ServeMux
+
+	heartbeat time.Duration
+	tasks     TaskRunner
+}
+
+type Option func(*Handler)
+
+// Set the interval of keep-alive comments on event streams, default: 15 seconds
+func WithHeartbeat(t time.Duration) Option {
+	return func(h *Handler) {
+		h.heartbeat = t
+	}
+}
+
+// Set the runner of tasks posted to a workload or fanned out to a
+// target, default: the manager. Without one the task endpoints respond
+// with 501 Not Implemented
+func WithTaskRunner(r TaskRunner) Option {
+	return func(h *Handler) {
+		h.tasks = r
+	}
+}
+
+func New(mgr *manager.Manager, opts ...Option) *Handler {
+	h := &Handler{
+		mgr:       mgr,
+		mux:       http.NewServeMux(),
+		heartbeat: 15 * time.Second,
+		tasks:     mgr,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("GET /workers", h.listWorkers)
+	h.mux.HandleFunc("GET /workers/{id}", h.getWorker)
+	h.mux.HandleFunc("POST /workers/{id}/cordon", h.cordon)
+	h.mux.HandleFunc("POST /workers/{id}/uncordon", h.uncordon)
+	h.mux.HandleFunc("POST /workers/{id}/drain", h.drain)
+
+	h.mux.HandleFunc("GET /workloads", h.listWorkloads)
+	h.mux.HandleFunc("POST /workloads", h.addWorkload)
+	h.mux.HandleFunc("GET /workloads/{id}", h.getWorkload)
+	h.mux.HandleFunc("DELETE /workloads/{id}", h.deleteWorkload)
+	h.mux.HandleFunc("POST /workloads/{id}/requeue", h.requeue)
+	h.mux.HandleFunc("POST /workloads/{id}/tasks", h.runTask)
+	h.mux.HandleFunc("POST /tasks", h.runTaskOn)
+
+	h.mux.HandleFunc("POST /distribute", h.distribute)
+	h.mux.HandleFunc("POST /rebalance", h.rebalance)
+	h.mux.HandleFunc("GET /plan", h.plan)
+
+	h.mux.HandleFunc("GET /events", h.events)
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+type Error struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// Writes an error with a status code matching the manager error
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, manager.ErrWorkerNotFound),
+		errors.Is(err, manager.ErrWorkloadNotFound),
+		errors.Is(err, worker.ErrWorkloadNotFound),
+		errors.Is(err, target.ErrNoMatch):
+		status = http.StatusNotFound
+	case errors.Is(err, manager.ErrWorkloadExists),
+		errors.Is(err, manager.ErrWorkerExists),
+		errors.Is(err, manager.ErrNotQuarantined),
+		errors.Is(err, manager.ErrInvalidTransition),
+		errors.Is(err, manager.ErrNotPlaced):
+		status = http.StatusConflict
+	case errors.Is(err, manager.ErrWorkerDown):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, manager.ErrUnreachable),
+		errors.Is(err, manager.ErrUnloadFailed):
+		status = http.StatusBadGateway
+	case errors.Is(err, manager.ErrWorkerBusy):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, manager.ErrTaskTimeout):
+		status = http.StatusGatewayTimeout
+	case errors.Is(err, manager.ErrTaskUnsupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, errBadRequest),
+		errors.Is(err, target.ErrInvalidTarget):
+		status = http.StatusBadRequest
+	}
+
+	writeJSON(w, status, Error{Error: err.Error()})
+}
+
+var errBadRequest = errors.New("bad request")
@@ -0,0 +1,293 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+type mockWorker struct {
+	id        string
+	unloadErr error
+}
+
+func (w *mockWorker) GetID() string {
+	return w.id
+}
+
+func (w *mockWorker) Load(context.Context, manager.LoadRequest) error {
+	return nil
+}
+
+func (w *mockWorker) Unload(context.Context, manager.UnloadRequest) error {
+	return w.unloadErr
+}
+
+type nopSignaller struct{}
+
+func (nopSignaller) Event(manager.Event) {}
+func (nopSignaller) Error(error)         {}
+
+func newServer(t *testing.T, opts ...Option) (*manager.Manager, *httptest.Server) {
+	t.Helper()
+
+	mgr, err := manager.New(
+		context.Background(),
+		manager.WithSignaller(nopSignaller{}),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	t.Cleanup(func() { _ = mgr.Stop() })
+
+	srv := httptest.NewServer(New(mgr, opts...))
+	t.Cleanup(srv.Close)
+
+	return mgr, srv
+}
+
+func do(t *testing.T, method, url, body string, v any) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to %s %s: %v", method, url, err)
+	}
+	defer res.Body.Close()
+
+	if v != nil {
+		if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+
+	return res.StatusCode
+}
+
+// Waits for the manager to have placed a workload on a worker
+func waitRunning(t *testing.T, url, id string) Workload {
+	t.Helper()
+
+	var wl Workload
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		do(t, http.MethodGet, url+"/workloads/"+id, "", &wl)
+		if wl.Status == manager.StatusRunning {
+			return wl
+		}
+	}
+
+	t.Fatalf("expected '%s' to be running, but got: %s", id, wl.Status)
+	return wl
+}
+
+func TestWorkloads(t *testing.T) {
+	mgr, srv := newServer(t)
+
+	w := &mockWorker{id: "worker0"}
+	if err := mgr.AddWorker(context.TODO(), w); err != nil {
+		t.Fatalf("failed to add worker: %v", err)
+	}
+
+	var created Workload
+	if exp, recv := http.StatusCreated, do(t, http.MethodPost, srv.URL+"/workloads", `{"id":"workload0"}`, &created); exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := "workload0", created.ID; exp != recv {
+		t.Errorf("expected workload '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := http.StatusConflict, do(t, http.MethodPost, srv.URL+"/workloads", `{"id":"workload0"}`, nil); exp != recv {
+		t.Errorf("expected status %d for a duplicate workload, but got: %d", exp, recv)
+	}
+
+	if exp, recv := http.StatusBadRequest, do(t, http.MethodPost, srv.URL+"/workloads", `{}`, nil); exp != recv {
+		t.Errorf("expected status %d for a missing id, but got: %d", exp, recv)
+	}
+
+	if exp, recv := http.StatusNoContent, do(t, http.MethodPost, srv.URL+"/distribute", "", nil); exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	wl := waitRunning(t, srv.URL, "workload0")
+	if exp, recv := "worker0", wl.Worker; exp != recv {
+		t.Errorf("expected workload on '%s', but got: '%s'", exp, recv)
+	}
+
+	if len(wl.History) == 0 {
+		t.Errorf("expected workload history, but got none")
+	}
+
+	var list []Workload
+	if exp, recv := http.StatusOK, do(t, http.MethodGet, srv.URL+"/workloads?status=running", "", &list); exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := 1, len(list); exp != recv {
+		t.Errorf("expected %d running workload(s), but got: %d", exp, recv)
+	}
+
+	// a workload the worker fails to unload isn't deleted
+	w.unloadErr = errors.New("connection refused")
+	if exp, recv := http.StatusBadGateway, do(t, http.MethodDelete, srv.URL+"/workloads/workload0", "", nil); exp != recv {
+		t.Errorf("expected status %d, but got: %d", exp, recv)
+	}
+
+	w.unloadErr = nil
+	if exp, recv := http.StatusNoContent, do(t, http.MethodDelete, srv.URL+"/workloads/workload0", "", nil); exp != recv {
+		t.Errorf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := http.StatusNotFound, do(t, http.MethodGet, srv.URL+"/workloads/workload0", "", nil); exp != recv {
+		t.Errorf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := http.StatusNotFound, do(t, http.MethodPost, srv.URL+"/workloads/workload0/requeue", "", nil); exp != recv {
+		t.Errorf("expected status %d, but got: %d", exp, recv)
+	}
+}
+
+func TestAddWorkloadConcurrent(t *testing.T) {
+	_, srv := newServer(t)
+
+	// only one of many concurrent requests for a workload wins
+	var (
+		wg   sync.WaitGroup
+		wins atomic.Int32
+	)
+	for range 10 {
+		wg.Go(func() {
+			if do(t, http.MethodPost, srv.URL+"/workloads", `{"id":"workload0"}`, nil) == http.StatusCreated {
+				wins.Add(1)
+			}
+		})
+	}
+	wg.Wait()
+
+	if exp, recv := int32(1), wins.Load(); exp != recv {
+		t.Errorf("expected %d concurrent request(s) to create the workload, but got: %d", exp, recv)
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	mgr, srv := newServer(t)
+
+	for _, id := range []string{"worker1", "worker0"} {
+		if err := mgr.AddWorker(context.TODO(), &mockWorker{id: id}); err != nil {
+			t.Fatalf("failed to add worker: %v", err)
+		}
+	}
+
+	do(t, http.MethodPost, srv.URL+"/workloads", `{"id":"workload0"}`, nil)
+	do(t, http.MethodPost, srv.URL+"/distribute", "", nil)
+	wl := waitRunning(t, srv.URL, "workload0")
+
+	var list []Worker
+	if exp, recv := http.StatusOK, do(t, http.MethodGet, srv.URL+"/workers", "", &list); exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if len(list) != 2 || list[0].ID != "worker0" || list[1].ID != "worker1" {
+		t.Fatalf("expected workers sorted by id, but got: %+v", list)
+	}
+
+	var wrk Worker
+	if exp, recv := http.StatusOK, do(t, http.MethodPost, srv.URL+"/workers/"+wl.Worker+"/cordon", "", &wrk); exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if !wrk.Cordoned {
+		t.Errorf("expected '%s' to be cordoned", wl.Worker)
+	}
+
+	if exp, recv := http.StatusOK, do(t, http.MethodPost, srv.URL+"/workers/"+wl.Worker+"/drain", "", &wrk); exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := 0, len(wrk.Workloads); exp != recv {
+		t.Errorf("expected %d workload(s) on a drained worker, but got: %d", exp, recv)
+	}
+
+	if exp, recv := http.StatusOK, do(t, http.MethodPost, srv.URL+"/workers/"+wl.Worker+"/uncordon", "", &wrk); exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	if wrk.Cordoned {
+		t.Errorf("expected '%s' to be uncordoned", wl.Worker)
+	}
+
+	if exp, recv := http.StatusNotFound, do(t, http.MethodGet, srv.URL+"/workers/worker9", "", nil); exp != recv {
+		t.Errorf("expected status %d, but got: %d", exp, recv)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	_, srv := newServer(t, WithHeartbeat(10*time.Millisecond))
+
+	if exp, recv := http.StatusBadRequest, do(t, http.MethodGet, srv.URL+"/events?type=bogus", "", nil); exp != recv {
+		t.Errorf("expected status %d for an unknown type, but got: %d", exp, recv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events?type=workload.added", nil)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to open event stream: %v", err)
+	}
+	defer res.Body.Close()
+
+	if exp, recv := "text/event-stream", res.Header.Get("Content-Type"); exp != recv {
+		t.Errorf("expected content type '%s', but got: '%s'", exp, recv)
+	}
+
+	do(t, http.MethodPost, srv.URL+"/workloads", `{"id":"workload0"}`, nil)
+
+	var typ, data string
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			typ = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+
+		if line == "" && data != "" {
+			break
+		}
+	}
+
+	if exp, recv := "workload.added", typ; exp != recv {
+		t.Errorf("expected event '%s', but got: '%s'", exp, recv)
+	}
+
+	var e manager.Event
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+
+	if exp, recv := "workload0", e.ResourceID; exp != recv {
+		t.Errorf("expected event for '%s', but got: '%s'", exp, recv)
+	}
+}
@@ -0,0 +1,252 @@
+package admin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// Error returned by the client when the API responds with a failure
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Client of the admin API
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+type ClientOption func(*Client)
+
+// Set the HTTP client used for requests, default: http.DefaultClient
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(cl *Client) {
+		cl.http = c
+	}
+}
+
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		url:  strings.TrimSuffix(baseURL, "/"),
+		http: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, v any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, r)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return statusError(res)
+	}
+
+	if v == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func statusError(res *http.Response) error {
+	e := &StatusError{StatusCode: res.StatusCode}
+
+	var body Error
+	if err := json.NewDecoder(res.Body).Decode(&body); err == nil {
+		e.Message = body.Error
+	}
+
+	return e
+}
+
+func (c *Client) Workers(ctx context.Context) ([]Worker, error) {
+	var res []Worker
+	return res, c.do(ctx, http.MethodGet, "/workers", nil, &res)
+}
+
+func (c *Client) Worker(ctx context.Context, id string) (Worker, error) {
+	var res Worker
+	return res, c.do(ctx, http.MethodGet, "/workers/"+url.PathEscape(id), nil, &res)
+}
+
+func (c *Client) Cordon(ctx context.Context, id string) (Worker, error) {
+	var res Worker
+	return res, c.do(ctx, http.MethodPost, "/workers/"+url.PathEscape(id)+"/cordon", nil, &res)
+}
+
+func (c *Client) Uncordon(ctx context.Context, id string) (Worker, error) {
+	var res Worker
+	return res, c.do(ctx, http.MethodPost, "/workers/"+url.PathEscape(id)+"/uncordon", nil, &res)
+}
+
+func (c *Client) Drain(ctx context.Context, id string) (Worker, error) {
+	var res Worker
+	return res, c.do(ctx, http.MethodPost, "/workers/"+url.PathEscape(id)+"/drain", nil, &res)
+}
+
+// Lists workloads, optionally filtered by status and worker
+func (c *Client) Workloads(ctx context.Context, status, worker string) ([]Workload, error) {
+	q := url.Values{}
+	if status != "" {
+		q.Set("status", status)
+	}
+
+	if worker != "" {
+		q.Set("worker", worker)
+	}
+
+	path := "/workloads"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	var res []Workload
+	return res, c.do(ctx, http.MethodGet, path, nil, &res)
+}
+
+func (c *Client) Workload(ctx context.Context, id string) (Workload, error) {
+	var res Workload
+	return res, c.do(ctx, http.MethodGet, "/workloads/"+url.PathEscape(id), nil, &res)
+}
+
+func (c *Client) AddWorkload(ctx context.Context, id string) (Workload, error) {
+	var res Workload
+	return res, c.do(ctx, http.MethodPost, "/workloads", AddWorkloadRequest{ID: id}, &res)
+}
+
+func (c *Client) DeleteWorkload(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/workloads/"+url.PathEscape(id), nil, nil)
+}
+
+func (c *Client) Requeue(ctx context.Context, id string) (Workload, error) {
+	var res Workload
+	return res, c.do(ctx, http.MethodPost, "/workloads/"+url.PathEscape(id)+"/requeue", nil, &res)
+}
+
+func (c *Client) RunTask(ctx context.Context, id string, task *worker.Task) (worker.Result, error) {
+	var res worker.Result
+	return res, c.do(ctx, http.MethodPost, "/workloads/"+url.PathEscape(id)+"/tasks", task, &res)
+}
+
+func (c *Client) RunTaskOn(ctx context.Context, req FanOutRequest) (worker.FanOutResult, error) {
+	var res worker.FanOutResult
+	return res, c.do(ctx, http.MethodPost, "/tasks", req, &res)
+}
+
+func (c *Client) Distribute(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/distribute", nil, nil)
+}
+
+func (c *Client) Rebalance(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/rebalance", nil, nil)
+}
+
+func (c *Client) Plan(ctx context.Context) (manager.Plan, error) {
+	var res manager.Plan
+	return res, c.do(ctx, http.MethodGet, "/plan", nil, &res)
+}
+
+// Streams events matching the filter. The channel is closed when
+// the context is cancelled or the stream ends.
+func (c *Client) Events(ctx context.Context, filter manager.EventFilter) (<-chan manager.Event, error) {
+	q := url.Values{}
+	for _, t := range filter.Types {
+		q.Add("type", t.String())
+	}
+
+	for _, id := range filter.WorkerIDs {
+		q.Add("worker", id)
+	}
+
+	for _, id := range filter.ResourceIDs {
+		q.Add("resource", id)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/events?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, statusError(res)
+	}
+
+	ch := make(chan manager.Event)
+	go func() {
+		defer close(ch)
+		defer res.Body.Close()
+
+		scanner := bufio.NewScanner(res.Body)
+		scanner.Buffer(nil, 1<<20)
+
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+
+			var e manager.Event
+			if err := json.Unmarshal([]byte(data), &e); err != nil {
+				continue
+			}
+
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,200 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+type echoRunner struct{}
+
+func (echoRunner) RunTask(_ context.Context, id string, task *worker.Task) (worker.Result, error) {
+	if id != "workload0" {
+		return worker.Result{}, worker.ErrWorkloadNotFound
+	}
+
+	return worker.Result{Command: task.Command, Success: true, Return: task.Args}, nil
+}
+
+func TestClient(t *testing.T) {
+	mgr, srv := newServer(t)
+	c := NewClient(srv.URL + "/")
+
+	if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker0"}); err != nil {
+		t.Fatalf("failed to add worker: %v", err)
+	}
+
+	if _, err := c.AddWorkload(context.TODO(), "workload0"); err != nil {
+		t.Fatalf("unexpected error when adding workload: %v", err)
+	}
+
+	var status *StatusError
+	if _, err := c.AddWorkload(context.TODO(), "workload0"); !errors.As(err, &status) || status.StatusCode != http.StatusConflict {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusConflict, err)
+	}
+
+	plan, err := c.Plan(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error when planning: %v", err)
+	}
+
+	if exp, recv := "worker0", plan.Loads["workload0"]; exp != recv {
+		t.Errorf("expected 'workload0' planned on '%s', but got: '%s'", exp, recv)
+	}
+
+	if err := c.Distribute(context.TODO()); err != nil {
+		t.Fatalf("unexpected error when distributing: %v", err)
+	}
+
+	wls, err := c.Workloads(context.TODO(), "", "worker0")
+	if err != nil {
+		t.Fatalf("unexpected error when listing workloads: %v", err)
+	}
+
+	if exp, recv := 1, len(wls); exp != recv {
+		t.Errorf("expected %d workload(s) on 'worker0', but got: %d", exp, recv)
+	}
+
+	if _, err := c.RunTask(context.TODO(), "workload0", &worker.Task{Command: "ping"}); !errors.As(err, &status) || status.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusNotImplemented, err)
+	}
+
+	if err := c.DeleteWorkload(context.TODO(), "workload0"); err != nil {
+		t.Errorf("unexpected error when deleting workload: %v", err)
+	}
+
+	if _, err := c.Workload(context.TODO(), "workload0"); !errors.As(err, &status) || status.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusNotFound, err)
+	}
+}
+
+func TestClientRunTask(t *testing.T) {
+	_, srv := newServer(t, WithTaskRunner(echoRunner{}))
+	c := NewClient(srv.URL)
+
+	res, err := c.RunTask(context.TODO(), "workload0", &worker.Task{Command: "ping", Args: []string{"-c", "1"}})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if !res.Success || res.Command != "ping" {
+		t.Errorf("expected a successful 'ping' result, but got: %+v", res)
+	}
+
+	var status *StatusError
+	if _, err := c.RunTask(context.TODO(), "workload1", &worker.Task{Command: "ping"}); !errors.As(err, &status) || status.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusNotFound, err)
+	}
+
+	if _, err := c.RunTask(context.TODO(), "workload0", &worker.Task{}); !errors.As(err, &status) || status.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusBadRequest, err)
+	}
+
+	if _, err := c.RunTaskOn(context.TODO(), FanOutRequest{Target: "access-*", Task: worker.Task{Command: "ping"}}); !errors.As(err, &status) || status.StatusCode != http.StatusNotFound {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusNotFound, err)
+	}
+	// fanned out tasks go through the runner too, the manager itself
+	// would fail them as the workloads aren't placed
+	for _, id := range []string{"workload0", "workload1"} {
+		if _, err := c.AddWorkload(context.TODO(), id); err != nil {
+			t.Fatalf("unexpected error when adding workload: %v", err)
+		}
+	}
+
+	fanned, err := c.RunTaskOn(context.TODO(), FanOutRequest{Target: "workload*", Task: worker.Task{Command: "ping"}})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := []string{"workload0"}, fanned.Succeeded; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to succeed, but got: %v", exp, recv)
+	}
+
+	_, srv = newServer(t, WithTaskRunner(nil))
+	c = NewClient(srv.URL)
+
+	if _, err := c.RunTaskOn(context.TODO(), FanOutRequest{Target: "workload*", Task: worker.Task{Command: "ping"}}); !errors.As(err, &status) || status.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusNotImplemented, err)
+	}
+}
+
+type taskWorker struct {
+	mockWorker
+}
+
+func (w *taskWorker) RunTask(_ context.Context, id string, task *worker.Task) (worker.Result, error) {
+	return worker.Result{WorkerID: w.id, Hostname: id, Command: task.Command, Success: true}, nil
+}
+
+func TestClientRunTaskOn(t *testing.T) {
+	mgr, srv := newServer(t)
+	c := NewClient(srv.URL)
+
+	if err := mgr.AddWorker(context.TODO(), &taskWorker{mockWorker{id: "worker0"}}); err != nil {
+		t.Fatalf("failed to add worker: %v", err)
+	}
+
+	for _, id := range []string{"core-1", "core-2", "edge-1"} {
+		if _, err := c.AddWorkload(context.TODO(), id); err != nil {
+			t.Fatalf("unexpected error when adding workload: %v", err)
+		}
+	}
+
+	if err := c.Distribute(context.TODO()); err != nil {
+		t.Fatalf("unexpected error when distributing: %v", err)
+	}
+
+	res, err := c.RunTaskOn(context.TODO(), FanOutRequest{Target: "core-*", Task: worker.Task{Command: "ping"}, Timeout: "5s"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := []string{"core-1", "core-2"}, res.Succeeded; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to succeed, but got: %v", exp, recv)
+	}
+
+	if exp, recv := "core-1", res.Results["core-1"].Hostname; exp != recv {
+		t.Errorf("expected the result of '%s', but got: '%s'", exp, recv)
+	}
+
+	var status *StatusError
+	if _, err := c.RunTaskOn(context.TODO(), FanOutRequest{Target: "(core-*", Task: worker.Task{Command: "ping"}}); !errors.As(err, &status) || status.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a %d status error, but got: %v", http.StatusBadRequest, err)
+	}
+}
+
+func TestClientEvents(t *testing.T) {
+	_, srv := newServer(t)
+	c := NewClient(srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 2*time.Second)
+	defer cancel()
+
+	events, err := c.Events(ctx, manager.EventFilter{Types: []manager.EventType{manager.EventWorkloadAdded}})
+	if err != nil {
+		t.Fatalf("unexpected error when streaming events: %v", err)
+	}
+
+	if _, err := c.AddWorkload(context.TODO(), "workload0"); err != nil {
+		t.Fatalf("unexpected error when adding workload: %v", err)
+	}
+
+	select {
+	case e := <-events:
+		if exp, recv := "workload0", e.ResourceID; exp != recv {
+			t.Errorf("expected event for '%s', but got: '%s'", exp, recv)
+		}
+	case <-ctx.Done():
+		t.Fatalf("expected an event before the deadline")
+	}
+
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+// Parses an event filter from the query, e.g.
+// ?type=workload.distributed&type=worker.added&worker=worker0
+func parseFilter(r *http.Request) (manager.EventFilter, error) {
+	q := r.URL.Query()
+
+	filter := manager.EventFilter{
+		WorkerIDs:   q["worker"],
+		ResourceIDs: q["resource"],
+	}
+
+	for _, name := range q["type"] {
+		var t manager.EventType
+		if err := t.UnmarshalJSON([]byte(`"` + name + `"`)); err != nil {
+			return filter, fmt.Errorf("%w: unknown event type '%s'", errBadRequest, name)
+		}
+
+		filter.Types = append(filter.Types, t)
+	}
+
+	return filter, nil
+}
+
+// Streams events matching the query filter as Server-Sent Events,
+// until the client goes away or the manager is stopped
+func (h *Handler) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	filter, err := parseFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	events, cancel := h.mgr.Subscribe(filter)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				continue
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+				return
+			}
+		}
+
+		flusher.Flush()
+	}
+}
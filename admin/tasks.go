@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/target"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// Runs a task on the worker holding a workload
+type TaskRunner interface {
+	RunTask(ctx context.Context, workloadID string, task *worker.Task) (worker.Result, error)
+}
+
+func (h *Handler) runTask(w http.ResponseWriter, r *http.Request) {
+	if h.tasks == nil {
+		writeJSON(w, http.StatusNotImplemented, Error{Error: "running tasks is not supported"})
+		return
+	}
+
+	var task worker.Task
+	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+		writeError(w, fmt.Errorf("%w: %w", errBadRequest, err))
+		return
+	}
+
+	if task.Command == "" {
+		writeError(w, fmt.Errorf("%w: missing task command", errBadRequest))
+		return
+	}
+
+	res, err := h.tasks.RunTask(r.Context(), r.PathValue("id"), &task)
+	if err != nil && res.Error == nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Runs a task on every workload matching a target expression, see target.Parse
+type FanOutRequest struct {
+	Target string      `json:"target"`
+	Task   worker.Task `json:"task"`
+	// Max amount of workloads running the task at once, 0 for the default
+	Concurrency int `json:"concurrency,omitempty"`
+	// Max time the task may run on a single workload, e.g. "30s"
+	Timeout string `json:"timeout,omitempty"`
+}
+
+func (h *Handler) runTaskOn(w http.ResponseWriter, r *http.Request) {
+	if h.tasks == nil {
+		writeJSON(w, http.StatusNotImplemented, Error{Error: "running tasks is not supported"})
+		return
+	}
+
+	var req FanOutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("%w: %w", errBadRequest, err))
+		return
+	}
+
+	if req.Task.Command == "" {
+		writeError(w, fmt.Errorf("%w: missing task command", errBadRequest))
+		return
+	}
+
+	t, err := target.Parse(req.Target)
+	if err != nil {
+		writeError(w, fmt.Errorf("%w: %w", errBadRequest, err))
+		return
+	}
+
+	var opts []worker.FanOutOption
+	if req.Concurrency > 0 {
+		opts = append(opts, worker.WithConcurrency(req.Concurrency))
+	}
+
+	if req.Timeout != "" {
+		d, err := time.ParseDuration(req.Timeout)
+		if err != nil {
+			writeError(w, fmt.Errorf("%w: %w", errBadRequest, err))
+			return
+		}
+
+		opts = append(opts, worker.WithTargetTimeout(d))
+	}
+
+	ids, err := h.mgr.Targets(r.Context(), t)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// every workload's task goes through the runner, as on a single workload
+	res := worker.FanOut(r.Context(), ids, func(ctx context.Context, id string) (worker.Result, error) {
+		return h.tasks.RunTask(ctx, id, &req.Task)
+	}, opts...)
+
+	writeJSON(w, http.StatusOK, res)
+}
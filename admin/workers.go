@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+type Worker struct {
+	ID        string               `json:"id"`
+	Workloads []string             `json:"workloads"`
+	Cordoned  bool                 `json:"cordoned"`
+	Breaker   manager.BreakerState `json:"breaker"`
+
+	// Set when the worker registered itself with the manager
+	Labels     map[string]string `json:"labels,omitempty"`
+	Capacity   int               `json:"capacity,omitempty"`
+	Version    string            `json:"version,omitempty"`
+	Generation uint64            `json:"generation,omitempty"`
+}
+
+func (h *Handler) worker(ctx context.Context, w manager.Worker) (Worker, error) {
+	wls, err := h.mgr.GetAssosiactions(ctx, w)
+	if err != nil {
+		return Worker{}, err
+	}
+
+	ids := make([]string, 0, len(wls))
+	for _, wl := range wls {
+		ids = append(ids, wl.GetID())
+	}
+
+	slices.Sort(ids)
+
+	v := Worker{
+		ID:        w.GetID(),
+		Workloads: ids,
+		Cordoned:  h.mgr.Cordoned(w.GetID()),
+		Breaker:   h.mgr.BreakerState(w.GetID()),
+	}
+
+	if reg, ok := h.mgr.WorkerRegistration(w.GetID()); ok {
+		v.Labels = reg.Labels
+		v.Capacity = reg.Capacity
+		v.Version = reg.Version
+		v.Generation = reg.Generation
+	}
+
+	return v, nil
+}
+
+func (h *Handler) listWorkers(w http.ResponseWriter, r *http.Request) {
+	workers, err := h.mgr.Workers(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	res := make([]Worker, 0, len(workers))
+	for _, wrk := range workers {
+		v, err := h.worker(r.Context(), wrk)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		res = append(res, v)
+	}
+
+	slices.SortFunc(res, func(a, b Worker) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *Handler) getWorker(w http.ResponseWriter, r *http.Request) {
+	wrk, err := h.mgr.GetWorker(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	v, err := h.worker(r.Context(), wrk)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (h *Handler) cordon(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.Cordon(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.getWorker(w, r)
+}
+
+func (h *Handler) uncordon(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.Uncordon(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.getWorker(w, r)
+}
+
+func (h *Handler) drain(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.Drain(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.getWorker(w, r)
+}
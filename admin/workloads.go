@@ -0,0 +1,176 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+type Workload struct {
+	ID               string               `json:"id"`
+	Status           manager.Status       `json:"status"`
+	Worker           string               `json:"worker,omitempty"`
+	LastStatusChange time.Time            `json:"lastStatusChange"`
+	Attempts         int                  `json:"attempts"`
+	History          []manager.Transition `json:"history,omitempty"`
+}
+
+type AddWorkloadRequest struct {
+	ID string `json:"id"`
+}
+
+func (h *Handler) workload(ctx context.Context, wl manager.Workload) (Workload, error) {
+	v := Workload{
+		ID:               wl.GetID(),
+		Status:           wl.GetStatus(),
+		LastStatusChange: wl.LastStatusChange(),
+		Attempts:         h.mgr.Attempts(wl.GetID()),
+	}
+
+	w, err := h.mgr.GetAssociation(ctx, wl)
+	switch {
+	case errors.Is(err, manager.ErrMissingAssociation):
+	case err != nil:
+		return v, err
+	default:
+		v.Worker = w.GetID()
+	}
+
+	return v, nil
+}
+
+func (h *Handler) listWorkloads(w http.ResponseWriter, r *http.Request) {
+	workloads, err := h.mgr.Workloads(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// optional filters, e.g. ?status=running&worker=worker0
+	status, worker := r.URL.Query().Get("status"), r.URL.Query().Get("worker")
+
+	res := make([]Workload, 0, len(workloads))
+	for _, wl := range workloads {
+		v, err := h.workload(r.Context(), wl)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if status != "" && v.Status.String() != status {
+			continue
+		}
+
+		if worker != "" && v.Worker != worker {
+			continue
+		}
+
+		res = append(res, v)
+	}
+
+	slices.SortFunc(res, func(a, b Workload) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *Handler) getWorkload(w http.ResponseWriter, r *http.Request) {
+	wl, err := h.mgr.GetWorkload(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	v, err := h.workload(r.Context(), wl)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if v.History, err = h.mgr.WorkloadHistory(r.Context(), wl.GetID()); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v)
+}
+
+func (h *Handler) addWorkload(w http.ResponseWriter, r *http.Request) {
+	var req AddWorkloadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("%w: %w", errBadRequest, err))
+		return
+	}
+
+	if req.ID == "" {
+		writeError(w, fmt.Errorf("%w: missing workload id", errBadRequest))
+		return
+	}
+
+	wl := manager.NewWorkload(req.ID)
+	if err := h.mgr.AddWorkload(r.Context(), wl); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	v, err := h.workload(r.Context(), wl)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/workloads/"+req.ID)
+	writeJSON(w, http.StatusCreated, v)
+}
+
+func (h *Handler) deleteWorkload(w http.ResponseWriter, r *http.Request) {
+	wl, err := h.mgr.GetWorkload(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if err := h.mgr.DeleteWorkload(r.Context(), wl); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) requeue(w http.ResponseWriter, r *http.Request) {
+	if err := h.mgr.Requeue(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.getWorkload(w, r)
+}
+
+func (h *Handler) distribute(w http.ResponseWriter, r *http.Request) {
+	h.mgr.Distribute()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) rebalance(w http.ResponseWriter, r *http.Request) {
+	h.mgr.Rebalance()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) plan(w http.ResponseWriter, r *http.Request) {
+	plan, err := h.mgr.Plan(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, plan)
+}
@@ -0,0 +1,248 @@
+// Package cloudevent maps event envelopes, and with them manager and
+// worker events, to CloudEvents 1.0 in both the structured and the
+// binary content mode.
+package cloudevent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+const (
+	SpecVersion = "1.0"
+
+	// Version of the event types and data produced by this package,
+	// appended to every type string
+	Version = "v1"
+
+	// Prefix of every type string, e.g. ottomato.manager.workload.added.v1
+	TypePrefix = "ottomato"
+
+	ContentTypeJSON       = "application/json"
+	ContentTypeStructured = "application/cloudevents+json"
+)
+
+var (
+	ErrInvalidEvent       = errors.New("invalid cloudevent")
+	ErrUnsupportedVersion = errors.New("unsupported cloudevent version")
+	ErrUnknownType        = errors.New("unknown cloudevent type")
+)
+
+// A CloudEvent in its structured JSON form. Data is kept as raw JSON
+// so it can be decoded in to the event type it originated from.
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time,omitzero"`
+	DataContentType string    `json:"datacontenttype,omitempty"`
+	Data            []byte    `json:"-"`
+
+	// extension attribute carrying the envelope's correlation id
+	CorrelationID string `json:"correlationid,omitempty"`
+}
+
+type Option func(*Event)
+
+// Set the id of the event, default: the id of the envelope
+func WithID(id string) Option {
+	return func(e *Event) {
+		e.ID = id
+	}
+}
+
+// Set the time of the event, default: the time of the envelope
+func WithTime(t time.Time) Option {
+	return func(e *Event) {
+		e.Time = t
+	}
+}
+
+// Maps an envelope to a CloudEvent. The source and subject are kept
+// as is, and the type is prefixed and suffixed with the version.
+func FromEnvelope(env event.Envelope, opts ...Option) (Event, error) {
+	if env.Type.Namespace() == "" || env.Type.Name() == "" {
+		return Event{}, fmt.Errorf("%w: '%s'", ErrUnknownType, env.Type)
+	}
+
+	if env.Source == "" {
+		return Event{}, fmt.Errorf("%w: missing source", ErrInvalidEvent)
+	}
+
+	e := Event{
+		SpecVersion:     SpecVersion,
+		ID:              env.ID,
+		Source:          env.Source,
+		Type:            typeString(env.Type),
+		Subject:         env.Subject,
+		Time:            env.Time,
+		DataContentType: ContentTypeJSON,
+		CorrelationID:   env.CorrelationID,
+	}
+
+	if env.Data != nil {
+		data, err := json.Marshal(env.Data)
+		if err != nil {
+			return Event{}, err
+		}
+
+		e.Data = data
+	}
+
+	if e.ID == "" {
+		e.ID = event.NewID()
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	for _, opt := range opts {
+		opt(&e)
+	}
+
+	return e, nil
+}
+
+// Maps a CloudEvent produced by FromEnvelope back to an envelope
+func ToEnvelope(e Event) (event.Envelope, error) {
+	if err := e.Validate(); err != nil {
+		return event.Envelope{}, err
+	}
+
+	typ, err := parseType(e.Type)
+	if err != nil {
+		return event.Envelope{}, err
+	}
+
+	if !isJSON(e.DataContentType) {
+		return event.Envelope{}, fmt.Errorf("%w: unsupported data content type '%s'", ErrInvalidEvent, e.DataContentType)
+	}
+
+	env := event.Envelope{
+		ID:            e.ID,
+		Type:          typ,
+		Source:        e.Source,
+		Subject:       e.Subject,
+		Time:          e.Time,
+		CorrelationID: e.CorrelationID,
+	}
+
+	if len(e.Data) > 0 {
+		env.Data = json.RawMessage(e.Data)
+	}
+
+	return env, nil
+}
+
+// Checks the attributes required by the specification
+func (e Event) Validate() error {
+	if e.SpecVersion != SpecVersion {
+		return fmt.Errorf("%w: specversion '%s'", ErrUnsupportedVersion, e.SpecVersion)
+	}
+
+	switch {
+	case e.ID == "":
+		return fmt.Errorf("%w: missing id", ErrInvalidEvent)
+	case e.Source == "":
+		return fmt.Errorf("%w: missing source", ErrInvalidEvent)
+	case e.Type == "":
+		return fmt.Errorf("%w: missing type", ErrInvalidEvent)
+	}
+
+	return nil
+}
+
+func typeString(t event.Type) string {
+	return TypePrefix + "." + string(t) + "." + Version
+}
+
+// Strips the prefix and version from a type string, rejecting
+// types from other producers or versions
+func parseType(typ string) (event.Type, error) {
+	rest, ok := strings.CutPrefix(typ, TypePrefix+".")
+	if !ok {
+		return "", fmt.Errorf("%w: '%s'", ErrUnknownType, typ)
+	}
+
+	i := strings.LastIndex(rest, ".")
+	if i < 0 {
+		return "", fmt.Errorf("%w: '%s'", ErrUnknownType, typ)
+	}
+
+	if v := rest[i+1:]; v != Version {
+		return "", fmt.Errorf("%w: type version '%s'", ErrUnsupportedVersion, v)
+	}
+
+	t := event.Type(rest[:i])
+	if t.Namespace() == "" || t.Name() == "" {
+		return "", fmt.Errorf("%w: '%s'", ErrUnknownType, typ)
+	}
+
+	return t, nil
+}
+
+// Marshals the event in the structured content mode. JSON data is
+// embedded as is, any other data is base64 encoded.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+
+	tmp := struct {
+		event
+		Data       json.RawMessage `json:"data,omitempty"`
+		DataBase64 []byte          `json:"data_base64,omitempty"`
+	}{
+		event: event(e),
+	}
+
+	if isJSON(e.DataContentType) {
+		tmp.Data = e.Data
+	} else {
+		tmp.DataBase64 = e.Data
+	}
+
+	return json.Marshal(tmp)
+}
+
+func (e *Event) UnmarshalJSON(input []byte) error {
+	type event Event
+
+	tmp := &struct {
+		*event
+		Data       json.RawMessage `json:"data,omitempty"`
+		DataBase64 []byte          `json:"data_base64,omitempty"`
+	}{
+		event: (*event)(e),
+	}
+
+	if err := json.Unmarshal(input, &tmp); err != nil {
+		return err
+	}
+
+	e.Data = tmp.Data
+	if len(tmp.DataBase64) > 0 {
+		e.Data = tmp.DataBase64
+	}
+
+	return nil
+}
+
+// Reports whether a content type is JSON, an empty content type is
+// implied to be JSON by the specification
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mt, _, _ := strings.Cut(contentType, ";")
+	mt = strings.TrimSpace(mt)
+
+	return mt == ContentTypeJSON || strings.HasSuffix(mt, "+json")
+}
@@ -0,0 +1,143 @@
+package cloudevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+func TestTypeString(t *testing.T) {
+	if exp, recv := "ottomato.manager.workload.added.v1", typeString("manager.workload.added"); exp != recv {
+		t.Errorf("expected type '%s', but got: '%s'", exp, recv)
+	}
+
+	typ, err := parseType("ottomato.worker.workload.init.error.v1")
+	if err != nil {
+		t.Fatalf("unexpected error when parsing type: %v", err)
+	}
+
+	if exp, recv := event.Type("worker.workload.init.error"), typ; exp != recv {
+		t.Errorf("expected type '%s', but got: '%s'", exp, recv)
+	}
+
+	if _, err := parseType("ottomato.worker.workload.added.v2"); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected '%v', but got: %v", ErrUnsupportedVersion, err)
+	}
+
+	if _, err := parseType("com.example.workload.added.v1"); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("expected '%v', but got: %v", ErrUnknownType, err)
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	ctx := event.WithCorrelationID(context.TODO(), "correlation0")
+	exp := event.New(ctx, "worker.workload.added", event.WorkerSource("worker0"), "workload0", map[string]any{"message": "hello"})
+
+	ce, err := FromEnvelope(exp)
+	if err != nil {
+		t.Fatalf("unexpected error when mapping envelope: %v", err)
+	}
+
+	if exp.ID != ce.ID || exp.Source != ce.Source || exp.CorrelationID != ce.CorrelationID {
+		t.Errorf("expected the envelope attributes to be kept, but got: %+v", ce)
+	}
+
+	recv, err := ToEnvelope(ce)
+	if err != nil {
+		t.Fatalf("unexpected error when mapping envelope back: %v", err)
+	}
+
+	if exp.Type != recv.Type || exp.Subject != recv.Subject || !exp.Time.Equal(recv.Time) {
+		t.Errorf("expected envelope to survive a round trip\nexp:  %+v\nrecv: %+v", exp, recv)
+	}
+
+	var data map[string]any
+	if err := recv.DecodeData(&data); err != nil {
+		t.Fatalf("unexpected error when decoding data: %v", err)
+	}
+
+	if exp, recv := "hello", data["message"]; exp != recv {
+		t.Errorf("expected message '%s', but got: '%v'", exp, recv)
+	}
+
+	if _, err := FromEnvelope(event.Envelope{Type: "added", Source: event.WorkerSource("worker0")}); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("expected '%v', but got: %v", ErrUnknownType, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	e, err := FromEnvelope(event.Envelope{Type: "manager.worker.added", Source: event.ManagerSource("manager0")})
+	if err != nil {
+		t.Fatalf("unexpected error when mapping envelope: %v", err)
+	}
+
+	if err := e.Validate(); err != nil {
+		t.Fatalf("unexpected error when validating event: %v", err)
+	}
+
+	e.SpecVersion = "0.3"
+	if err := e.Validate(); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected '%v', but got: %v", ErrUnsupportedVersion, err)
+	}
+
+	e.SpecVersion = SpecVersion
+	e.ID = ""
+	if err := e.Validate(); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("expected '%v', but got: %v", ErrInvalidEvent, err)
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []Event{
+		{
+			SpecVersion:     SpecVersion,
+			ID:              "1",
+			Source:          "/ottomato/managers/manager0",
+			Type:            "ottomato.manager.worker.added.v1",
+			Subject:         "worker0",
+			Time:            ts,
+			DataContentType: ContentTypeJSON,
+			Data:            []byte(`{"workerId":"worker0"}`),
+			CorrelationID:   "correlation0",
+		},
+		{
+			SpecVersion:     SpecVersion,
+			ID:              "2",
+			Source:          "/ottomato/workers/worker0",
+			Type:            "ottomato.worker.workload.added.v1",
+			DataContentType: "text/plain",
+			Data:            []byte("hello"),
+		},
+	}
+
+	for _, exp := range cases {
+		b, err := json.Marshal(exp)
+		if err != nil {
+			t.Fatalf("unexpected error when serializing event: %v", err)
+		}
+
+		if isJSON(exp.DataContentType) && !strings.Contains(string(b), `"data":{`) {
+			t.Errorf("expected JSON data to be embedded, but got: %s", string(b))
+		}
+
+		if !isJSON(exp.DataContentType) && !strings.Contains(string(b), `"data_base64":`) {
+			t.Errorf("expected other data to be base64 encoded, but got: %s", string(b))
+		}
+
+		var recv Event
+		if err := json.Unmarshal(b, &recv); err != nil {
+			t.Fatalf("unexpected error when deserializing event: %v", err)
+		}
+
+		if exp.ID != recv.ID || exp.Type != recv.Type || exp.CorrelationID != recv.CorrelationID || !exp.Time.Equal(recv.Time) || string(exp.Data) != string(recv.Data) {
+			t.Errorf("expected event to survive a round trip\nexp:  %+v\nrecv: %+v", exp, recv)
+		}
+	}
+}
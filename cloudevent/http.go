@@ -0,0 +1,94 @@
+package cloudevent
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// Prefix of the headers carrying attributes in the binary content mode
+const headerPrefix = "ce-"
+
+// Encodes an event in the structured content mode, where the body
+// holds the whole event and the content type is set accordingly
+func WriteStructured(h http.Header, e Event) ([]byte, error) {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Set("Content-Type", ContentTypeStructured)
+
+	return body, nil
+}
+
+// Encodes an event in the binary content mode, where the attributes
+// are carried as ce- headers and the body holds only the data
+func WriteBinary(h http.Header, e Event) []byte {
+	h.Set(headerPrefix+"specversion", e.SpecVersion)
+	h.Set(headerPrefix+"id", e.ID)
+	h.Set(headerPrefix+"source", e.Source)
+	h.Set(headerPrefix+"type", e.Type)
+
+	if e.Subject != "" {
+		h.Set(headerPrefix+"subject", e.Subject)
+	}
+
+	if !e.Time.IsZero() {
+		h.Set(headerPrefix+"time", e.Time.Format(time.RFC3339Nano))
+	}
+
+	if e.CorrelationID != "" {
+		h.Set(headerPrefix+"correlationid", e.CorrelationID)
+	}
+
+	if e.DataContentType != "" {
+		h.Set("Content-Type", e.DataContentType)
+	}
+
+	return e.Data
+}
+
+// Decodes an event in either content mode, picked by the content type
+func Read(h http.Header, body []byte) (Event, error) {
+	if mt, _, _ := mime.ParseMediaType(h.Get("Content-Type")); mt == ContentTypeStructured {
+		return ReadStructured(body)
+	}
+
+	return ReadBinary(h, body)
+}
+
+func ReadStructured(body []byte) (Event, error) {
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		return e, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+
+	return e, e.Validate()
+}
+
+func ReadBinary(h http.Header, body []byte) (Event, error) {
+	e := Event{
+		SpecVersion:     h.Get(headerPrefix + "specversion"),
+		ID:              h.Get(headerPrefix + "id"),
+		Source:          h.Get(headerPrefix + "source"),
+		Type:            h.Get(headerPrefix + "type"),
+		Subject:         h.Get(headerPrefix + "subject"),
+		DataContentType: h.Get("Content-Type"),
+		Data:            body,
+		CorrelationID:   h.Get(headerPrefix + "correlationid"),
+	}
+
+	if ts := h.Get(headerPrefix + "time"); ts != "" {
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return e, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+		}
+
+		e.Time = t
+	}
+
+	return e, e.Validate()
+}
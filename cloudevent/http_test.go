@@ -0,0 +1,77 @@
+package cloudevent
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+func TestBinary(t *testing.T) {
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	exp, err := FromWorker(worker.NewWorkloadDeadEvent("worker0", "workload0"), WithID("1"), WithTime(ts))
+	if err != nil {
+		t.Fatalf("unexpected error when mapping event: %v", err)
+	}
+
+	h := http.Header{}
+	body := WriteBinary(h, exp)
+
+	if recv := h.Get("ce-type"); exp.Type != recv {
+		t.Errorf("expected ce-type header '%s', but got: '%s'", exp.Type, recv)
+	}
+
+	if recv := h.Get("Content-Type"); recv != ContentTypeJSON {
+		t.Errorf("expected content type '%s', but got: '%s'", ContentTypeJSON, recv)
+	}
+
+	recv, err := Read(h, body)
+	if err != nil {
+		t.Fatalf("unexpected error when reading event: %v", err)
+	}
+
+	if exp.ID != recv.ID || exp.Source != recv.Source || exp.Subject != recv.Subject || !exp.Time.Equal(recv.Time) || string(exp.Data) != string(recv.Data) {
+		t.Errorf("expected event to survive a round trip\nexp:  %+v\nrecv: %+v", exp, recv)
+	}
+}
+
+func TestStructured(t *testing.T) {
+	exp, err := FromWorker(worker.NewWorkloadDeadEvent("worker0", "workload0"))
+	if err != nil {
+		t.Fatalf("unexpected error when mapping event: %v", err)
+	}
+
+	h := http.Header{}
+	body, err := WriteStructured(h, exp)
+	if err != nil {
+		t.Fatalf("unexpected error when writing event: %v", err)
+	}
+
+	if recv := h.Get("Content-Type"); recv != ContentTypeStructured {
+		t.Errorf("expected content type '%s', but got: '%s'", ContentTypeStructured, recv)
+	}
+
+	h.Set("Content-Type", ContentTypeStructured+"; charset=utf-8")
+
+	recv, err := Read(h, body)
+	if err != nil {
+		t.Fatalf("unexpected error when reading event: %v", err)
+	}
+
+	if exp.ID != recv.ID || exp.Type != recv.Type || string(exp.Data) != string(recv.Data) {
+		t.Errorf("expected event to survive a round trip\nexp:  %+v\nrecv: %+v", exp, recv)
+	}
+}
+
+func TestReadBinaryMissingAttributes(t *testing.T) {
+	h := http.Header{}
+	h.Set("ce-specversion", SpecVersion)
+	h.Set("ce-type", "ottomato.worker.workload.dead.v1")
+
+	if _, err := Read(h, nil); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("expected '%v', but got: %v", ErrInvalidEvent, err)
+	}
+}
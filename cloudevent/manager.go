@@ -0,0 +1,35 @@
+package cloudevent
+
+import (
+	"fmt"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+// Maps a manager event to a CloudEvent through its envelope
+func FromManager(e manager.Event, opts ...Option) (Event, error) {
+	if e.Type.String() == "" {
+		return Event{}, fmt.Errorf("%w: %w", ErrUnknownType, manager.ErrInvalidEvent)
+	}
+
+	if e.ManagerID == "" {
+		return Event{}, fmt.Errorf("%w: missing manager id", ErrInvalidEvent)
+	}
+
+	return FromEnvelope(e.Envelope(), opts...)
+}
+
+// Maps a CloudEvent produced by FromManager back to a manager event
+func ToManager(ce Event) (manager.Event, error) {
+	env, err := ToEnvelope(ce)
+	if err != nil {
+		return manager.Event{}, err
+	}
+
+	e, err := manager.FromEnvelope(env)
+	if err != nil {
+		return e, fmt.Errorf("%w: %w", ErrUnknownType, err)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,70 @@
+package cloudevent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+func TestFromManager(t *testing.T) {
+	e := manager.Event{
+		Type:       manager.EventWorkloadUnloaded,
+		ManagerID:  "manager0",
+		WorkerID:   "worker0",
+		ResourceID: "workload0",
+		Payload:    manager.UnloadedPayload{Reason: "rebalance"},
+	}
+
+	ce, err := FromManager(e, WithID("1"))
+	if err != nil {
+		t.Fatalf("unexpected error when mapping event: %v", err)
+	}
+
+	if exp, recv := "ottomato.manager.workload.unloaded.v1", ce.Type; exp != recv {
+		t.Errorf("expected type '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "/ottomato/managers/manager0", ce.Source; exp != recv {
+		t.Errorf("expected source '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "workload0", ce.Subject; exp != recv {
+		t.Errorf("expected subject '%s', but got: '%s'", exp, recv)
+	}
+
+	recv, err := ToManager(ce)
+	if err != nil {
+		t.Fatalf("unexpected error when mapping event back: %v", err)
+	}
+
+	if recv.Type != e.Type || recv.ManagerID != e.ManagerID || recv.WorkerID != e.WorkerID || recv.ResourceID != e.ResourceID {
+		t.Errorf("expected event to survive a round trip\nexp:  %+v\nrecv: %+v", e, recv)
+	}
+
+	if p, ok := recv.Payload.(manager.UnloadedPayload); !ok || p.Reason != "rebalance" {
+		t.Errorf("expected a typed payload, but got: %#v", recv.Payload)
+	}
+}
+
+func TestFromManagerInvalid(t *testing.T) {
+	if _, err := FromManager(manager.Event{Type: manager.EventType(-1), ManagerID: "manager0"}); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("expected '%v', but got: %v", ErrUnknownType, err)
+	}
+
+	if _, err := FromManager(manager.Event{Type: manager.EventWorkerAdded}); !errors.Is(err, ErrInvalidEvent) {
+		t.Errorf("expected '%v', but got: %v", ErrInvalidEvent, err)
+	}
+}
+
+func TestToManagerFromWorker(t *testing.T) {
+	ce, err := FromWorker(worker.NewWorkloadAddedEvent("worker0", "workload0"))
+	if err != nil {
+		t.Fatalf("unexpected error when mapping event: %v", err)
+	}
+
+	if _, err := ToManager(ce); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("expected '%v', but got: %v", ErrUnknownType, err)
+	}
+}
@@ -0,0 +1,35 @@
+package cloudevent
+
+import (
+	"fmt"
+
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// Maps a worker event to a CloudEvent through its envelope
+func FromWorker(e worker.Event, opts ...Option) (Event, error) {
+	if e.EventType.String() == "" {
+		return Event{}, fmt.Errorf("%w: invalid worker event type", ErrUnknownType)
+	}
+
+	if e.Worker == "" {
+		return Event{}, fmt.Errorf("%w: missing worker id", ErrInvalidEvent)
+	}
+
+	return FromEnvelope(e.Envelope(), opts...)
+}
+
+// Maps a CloudEvent produced by FromWorker back to a worker event
+func ToWorker(ce Event) (worker.Event, error) {
+	env, err := ToEnvelope(ce)
+	if err != nil {
+		return worker.Event{}, err
+	}
+
+	e, err := worker.FromEnvelope(env)
+	if err != nil {
+		return e, fmt.Errorf("%w: %w", ErrUnknownType, err)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,45 @@
+package cloudevent
+
+import (
+	"testing"
+
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+func TestFromWorker(t *testing.T) {
+	e := worker.NewWorkloadInitError("worker0", "workload0", "connection refused")
+	e.CorrelationID = "correlation0"
+
+	ce, err := FromWorker(e)
+	if err != nil {
+		t.Fatalf("unexpected error when mapping event: %v", err)
+	}
+
+	if exp, recv := "ottomato.worker.workload.init.error.v1", ce.Type; exp != recv {
+		t.Errorf("expected type '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "/ottomato/workers/worker0", ce.Source; exp != recv {
+		t.Errorf("expected source '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "workload0", ce.Subject; exp != recv {
+		t.Errorf("expected subject '%s', but got: '%s'", exp, recv)
+	}
+
+	if ce.ID == "" || ce.Time.IsZero() {
+		t.Errorf("expected a generated id and time, but got: '%s' and '%s'", ce.ID, ce.Time)
+	}
+
+	recv, err := ToWorker(ce)
+	if err != nil {
+		t.Fatalf("unexpected error when mapping event back: %v", err)
+	}
+
+	// the id and time are stamped when the event is mapped
+	e.ID, e.Time = ce.ID, ce.Time
+
+	if recv != e {
+		t.Errorf("expected event to survive a round trip\nexp:  %+v\nrecv: %+v", e, recv)
+	}
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/admin"
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+type cli struct {
+	client *admin.Client
+	out    io.Writer
+}
+
+func newCLI(addr string, out io.Writer) *cli {
+	return &cli{
+		client: admin.NewClient(addr),
+		out:    out,
+	}
+}
+
+// Repeatable string flag
+type multiFlag []string
+
+func (s *multiFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *multiFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+func newFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	return fs
+}
+
+// Parses flags placed anywhere among the arguments, returning the
+// positional arguments in order
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %w", errUsage, err)
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return pos, nil
+		}
+
+		pos, args = append(pos, args[0]), args[1:]
+	}
+}
+
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("o", "table", "output format, table or json")
+}
+
+func (c *cli) json(v any) error {
+	enc := json.NewEncoder(c.out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+func (c *cli) table(header string, rows func(w io.Writer)) error {
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, header)
+	rows(tw)
+
+	return tw.Flush()
+}
+
+func checkOutput(o string) error {
+	if o != "table" && o != "json" {
+		return fmt.Errorf("%w: unknown output format '%s'", errUsage, o)
+	}
+
+	return nil
+}
+
+func (c *cli) get(ctx context.Context, args []string) error {
+	fs := newFlags("get")
+	output := outputFlag(fs)
+	status := fs.String("status", "", "only list workloads with this status")
+	worker := fs.String("worker", "", "only list workloads on this worker")
+
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	if len(pos) != 1 {
+		return fmt.Errorf("%w: expected a resource, workers or workloads", errUsage)
+	}
+
+	switch pos[0] {
+	case "workers", "worker":
+		workers, err := c.client.Workers(ctx)
+		if err != nil {
+			return err
+		}
+
+		if *output == "json" {
+			return c.json(workers)
+		}
+
+		return c.table("ID\tWORKLOADS\tCORDONED\tBREAKER", func(w io.Writer) {
+			for _, wrk := range workers {
+				fmt.Fprintf(w, "%s\t%d\t%t\t%s\n", wrk.ID, len(wrk.Workloads), wrk.Cordoned, wrk.Breaker)
+			}
+		})
+	case "workloads", "workload":
+		workloads, err := c.client.Workloads(ctx, *status, *worker)
+		if err != nil {
+			return err
+		}
+
+		if *output == "json" {
+			return c.json(workloads)
+		}
+
+		return c.table("ID\tSTATUS\tWORKER\tSINCE\tATTEMPTS", func(w io.Writer) {
+			for _, wl := range workloads {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", wl.ID, wl.Status, orNone(wl.Worker), since(wl.LastStatusChange), wl.Attempts)
+			}
+		})
+	default:
+		return fmt.Errorf("%w: unknown resource '%s'", errUsage, pos[0])
+	}
+}
+
+func (c *cli) describe(ctx context.Context, args []string) error {
+	fs := newFlags("describe")
+	output := outputFlag(fs)
+
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	if len(pos) != 2 || (pos[0] != "workload" && pos[0] != "worker") {
+		return fmt.Errorf("%w: expected 'workload ID' or 'worker ID'", errUsage)
+	}
+
+	if pos[0] == "worker" {
+		wrk, err := c.client.Worker(ctx, pos[1])
+		if err != nil {
+			return err
+		}
+
+		if *output == "json" {
+			return c.json(wrk)
+		}
+
+		fmt.Fprintf(c.out, "ID:         %s\n", wrk.ID)
+		fmt.Fprintf(c.out, "Cordoned:   %t\n", wrk.Cordoned)
+		fmt.Fprintf(c.out, "Breaker:    %s\n", wrk.Breaker)
+		fmt.Fprintf(c.out, "Workloads:  %s\n", orNone(strings.Join(wrk.Workloads, ", ")))
+
+		return nil
+	}
+
+	wl, err := c.client.Workload(ctx, pos[1])
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return c.json(wl)
+	}
+
+	fmt.Fprintf(c.out, "ID:         %s\n", wl.ID)
+	fmt.Fprintf(c.out, "Status:     %s (%s)\n", wl.Status, since(wl.LastStatusChange))
+	fmt.Fprintf(c.out, "Worker:     %s\n", orNone(wl.Worker))
+	fmt.Fprintf(c.out, "Attempts:   %d\n", wl.Attempts)
+	fmt.Fprintln(c.out, "History:")
+
+	if len(wl.History) == 0 {
+		fmt.Fprintln(c.out, "  <none>")
+		return nil
+	}
+
+	return c.table("  TIME\tFROM\tTO\tREASON", func(w io.Writer) {
+		for _, t := range wl.History {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", t.Timestamp.Format(time.RFC3339), t.From, t.To, t.Reason)
+		}
+	})
+}
+
+func (c *cli) distribute(ctx context.Context, args []string) error {
+	fs := newFlags("distribute")
+	output := outputFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "show what a distribution pass would do")
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	if !*dryRun {
+		if err := c.client.Distribute(ctx); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(c.out, "distribution pass finished")
+		return nil
+	}
+
+	plan, err := c.client.Plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return c.json(plan)
+	}
+
+	return c.table("ACTION\tWORKLOAD\tWORKER", func(w io.Writer) {
+		for _, wrk := range slices.Sorted(maps.Keys(plan.Unloads)) {
+			for _, wl := range plan.Unloads[wrk] {
+				fmt.Fprintf(w, "unload\t%s\t%s\n", wl, wrk)
+			}
+		}
+
+		for _, wl := range slices.Sorted(maps.Keys(plan.Loads)) {
+			fmt.Fprintf(w, "load\t%s\t%s\n", wl, plan.Loads[wl])
+		}
+
+		for _, wl := range plan.Unplaced {
+			fmt.Fprintf(w, "unplaced\t%s\t<none>\n", wl)
+		}
+	})
+}
+
+func (c *cli) worker(ctx context.Context, action string, args []string) error {
+	pos, err := parse(newFlags(action), args)
+	if err != nil {
+		return err
+	}
+
+	if len(pos) != 2 || pos[0] != "worker" {
+		return fmt.Errorf("%w: expected 'worker ID'", errUsage)
+	}
+
+	var fn func(context.Context, string) (admin.Worker, error)
+	switch action {
+	case "cordon":
+		fn = c.client.Cordon
+	case "uncordon":
+		fn = c.client.Uncordon
+	default:
+		fn = c.client.Drain
+	}
+
+	wrk, err := fn(ctx, pos[1])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out, "worker '%s' %s, %d workload(s) left\n", wrk.ID, past(action), len(wrk.Workloads))
+	return nil
+}
+
+func (c *cli) events(ctx context.Context, args []string) error {
+	fs := newFlags("events")
+	output := outputFlag(fs)
+	follow := fs.Bool("follow", false, "stream events until interrupted")
+	timeout := fs.Duration("timeout", 10*time.Second, "how long to listen without --follow")
+
+	var types, workers, resources multiFlag
+	fs.Var(&types, "type", "only show events of this type")
+	fs.Var(&workers, "worker", "only show events for this worker")
+	fs.Var(&resources, "resource", "only show events for this resource")
+
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	if err := checkOutput(*output); err != nil {
+		return err
+	}
+
+	filter := manager.EventFilter{
+		WorkerIDs:   workers,
+		ResourceIDs: resources,
+	}
+
+	for _, name := range types {
+		var t manager.EventType
+		if err := t.UnmarshalJSON([]byte(`"` + name + `"`)); err != nil {
+			return fmt.Errorf("%w: unknown event type '%s'", errUsage, name)
+		}
+
+		filter.Types = append(filter.Types, t)
+	}
+
+	if !*follow {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	events, err := c.client.Events(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(c.out)
+	for e := range events {
+		if *output == "json" {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(c.out, "%s  %-28s  %s  %s\n", e.Time.Format(time.RFC3339), e.Type, orNone(e.ResourceID), e.WorkerID)
+	}
+
+	return nil
+}
+
+func (c *cli) runTask(ctx context.Context, args []string) error {
+	fs := newFlags("run-task")
+	kwargs := fs.String("kwargs", "", "keyword arguments as a JSON object")
+	tgt := fs.String("target", "", "run on every workload matching a target expression")
+	concurrency := fs.Int("concurrency", 0, "max workloads running the task at once")
+	timeout := fs.String("timeout", "", "max time the task may run on a single workload")
+
+	// flags go before the workload, so the task arguments are left alone
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	pos := fs.Args()
+	if *tgt == "" {
+		if len(pos) < 2 {
+			return fmt.Errorf("%w: expected a workload and a command", errUsage)
+		}
+
+		pos = pos[1:]
+	} else if len(pos) < 1 {
+		return fmt.Errorf("%w: expected a command", errUsage)
+	}
+
+	task := &worker.Task{
+		Command: pos[0],
+		Args:    pos[1:],
+	}
+
+	if *kwargs != "" {
+		if err := json.Unmarshal([]byte(*kwargs), &task.Kwargs); err != nil {
+			return fmt.Errorf("%w: invalid kwargs: %w", errUsage, err)
+		}
+	}
+
+	if *tgt != "" {
+		res, err := c.client.RunTaskOn(ctx, admin.FanOutRequest{
+			Target:      *tgt,
+			Task:        *task,
+			Concurrency: *concurrency,
+			Timeout:     *timeout,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := c.json(res); err != nil {
+			return err
+		}
+
+		if len(res.Failed) > 0 {
+			return fmt.Errorf("task failed on %d of %d workload(s)", len(res.Failed), len(res.Results))
+		}
+
+		return nil
+	}
+
+	if *timeout != "" {
+		d, err := time.ParseDuration(*timeout)
+		if err != nil {
+			return fmt.Errorf("%w: invalid timeout: %w", errUsage, err)
+		}
+
+		task.Timeout = d
+	}
+
+	res, err := c.client.RunTask(ctx, fs.Arg(0), task)
+	if err != nil {
+		return err
+	}
+
+	if err := c.json(res); err != nil {
+		return err
+	}
+
+	if res.Error != nil {
+		return fmt.Errorf("task failed: %w", res.Error)
+	}
+
+	return nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+
+	return s
+}
+
+func since(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return time.Since(t).Round(time.Second).String() + " ago"
+}
+
+func past(action string) string {
+	if action == "drain" {
+		return "drained"
+	}
+
+	return action + "ed"
+}
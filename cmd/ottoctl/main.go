@@ -0,0 +1,90 @@
+// Command ottoctl talks to the admin API of an ottomato manager.
+//
+//	ottoctl [--addr URL] <command> [flags] [args]
+//
+// The address defaults to $OTTOCTL_ADDR, or http://localhost:8080.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `usage: ottoctl [--addr URL] <command> [flags] [args]
+
+commands:
+  get workers [-o table|json]
+  get workloads [-o table|json] [--status S] [--worker W]
+  describe workload ID [-o table|json]
+  distribute [--dry-run] [-o table|json]
+  cordon worker ID
+  uncordon worker ID
+  drain worker ID
+  events [--follow] [--timeout D] [--type T]... [--worker W]... [--resource R]...
+  run-task [--timeout D] [--kwargs JSON] WORKLOAD COMMAND [ARGS...]
+  run-task --target EXPR [--concurrency N] [--timeout D] [--kwargs JSON] COMMAND [ARGS...]
+`
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+		}
+
+		fmt.Fprintf(os.Stderr, "ottoctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	addr := os.Getenv("OTTOCTL_ADDR")
+	if addr == "" {
+		addr = "http://localhost:8080"
+	}
+
+	fs := flag.NewFlagSet("ottoctl", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&addr, "addr", addr, "address of the manager admin API")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+
+	cli := newCLI(addr, out)
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+
+	switch cmd {
+	case "get":
+		return cli.get(ctx, args)
+	case "describe":
+		return cli.describe(ctx, args)
+	case "distribute":
+		return cli.distribute(ctx, args)
+	case "cordon", "uncordon", "drain":
+		return cli.worker(ctx, cmd, args)
+	case "events":
+		return cli.events(ctx, args)
+	case "run-task":
+		return cli.runTask(ctx, args)
+	case "help":
+		fmt.Fprint(out, usage)
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown command '%s'", errUsage, cmd)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/admin"
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+type mockWorker struct {
+	id string
+}
+
+func (w *mockWorker) GetID() string {
+	return w.id
+}
+
+func (w *mockWorker) Load(context.Context, manager.LoadRequest) error {
+	return nil
+}
+
+func (w *mockWorker) Unload(context.Context, manager.UnloadRequest) error {
+	return nil
+}
+
+type nopSignaller struct{}
+
+func (nopSignaller) Event(manager.Event) {}
+func (nopSignaller) Error(error)         {}
+
+func TestRun(t *testing.T) {
+	mgr, err := manager.New(
+		context.Background(),
+		manager.WithSignaller(nopSignaller{}),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+		manager.WithTaskWait(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	srv := httptest.NewServer(admin.New(mgr))
+	defer srv.Close()
+
+	if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker0"}); err != nil {
+		t.Fatalf("failed to add worker: %v", err)
+	}
+
+	if err := mgr.AddWorkload(context.TODO(), manager.NewWorkload("workload0")); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	tests := []struct {
+		args []string
+		exp  []string
+	}{
+		{[]string{"distribute", "--dry-run"}, []string{"load", "workload0", "worker0"}},
+		{[]string{"distribute"}, []string{"distribution pass finished"}},
+		{[]string{"get", "workloads"}, []string{"ID", "STATUS", "workload0", "running", "worker0"}},
+		{[]string{"get", "workloads", "-o", "json"}, []string{`"id": "workload0"`, `"status": "running"`}},
+		{[]string{"get", "workers"}, []string{"worker0", "closed"}},
+		{[]string{"describe", "workload", "workload0"}, []string{"Status:     running", "Worker:     worker0", "History:"}},
+		{[]string{"drain", "worker", "worker0"}, []string{"worker 'worker0' drained, 0 workload(s) left"}},
+	}
+
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		if err := run(context.TODO(), append([]string{"--addr", srv.URL}, tt.args...), out); err != nil {
+			t.Fatalf("unexpected error running %v: %v", tt.args, err)
+		}
+
+		for _, exp := range tt.exp {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("expected output of %v to contain '%s', but got:\n%s", tt.args, exp, out)
+			}
+		}
+	}
+
+	out := &bytes.Buffer{}
+	if err := run(context.TODO(), []string{"--addr", srv.URL, "get", "pods"}, out); !errors.Is(err, errUsage) {
+		t.Errorf("expected '%v', but got: %v", errUsage, err)
+	}
+
+	// drained off the only worker, so it isn't waited for
+	var se *admin.StatusError
+	err = run(context.TODO(), []string{"--addr", srv.URL, "run-task", "workload0", "ping"}, out)
+	if !errors.As(err, &se) || se.StatusCode != http.StatusConflict || !strings.Contains(se.Message, manager.ErrNotPlaced.Error()) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrNotPlaced, err)
+	}
+
+	if err := run(context.TODO(), []string{"--addr", srv.URL, "run-task", "--target", "workload*", "ping"}, out); err == nil || !strings.Contains(err.Error(), "failed on 1 of 1") {
+		t.Errorf("expected the task to fail on the workload, but got: %v", err)
+	}
+}
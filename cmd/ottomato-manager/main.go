@@ -0,0 +1,208 @@
+// Command ottomato-manager runs a manager serving the admin API,
+// Prometheus metrics and health checks on a single address. Worker
+// registrations are served on an address of their own, joinListen,
+// and refused when it isn't set, since they make the manager dial
+// back to any address a worker gives. With joinTlsCert and joinTlsKey
+// the join address serves TLS, and with joinClientCa it only takes
+// registrations from workers with a certificate signed by that CA.
+// The main address doesn't check who's calling, so it's best kept on
+// a private network.
+//
+//	ottomato-manager [--config FILE]
+//
+// The config file is YAML or JSON, and every setting can be overridden
+// with an OTTOMATO_MANAGER_ environment variable, see package config.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/admin"
+	"github.com/Telenor-NMS-SE/ottomato/config"
+	"github.com/Telenor-NMS-SE/ottomato/health"
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/metrics"
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+)
+
+func main() {
+	path := flag.String("config", os.Getenv("OTTOMATO_CONFIG"), "path to a YAML or JSON config file")
+	flag.Parse()
+
+	cfg, err := config.LoadManager(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ottomato-manager: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger, err := config.NewLogger(os.Stderr, cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ottomato-manager: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		logger.Error("failed to listen", "address", cfg.Listen, "error", err)
+		os.Exit(1)
+	}
+
+	var joinLn net.Listener
+	if cfg.JoinListen != "" {
+		if joinLn, err = net.Listen("tcp", cfg.JoinListen); err != nil {
+			logger.Error("failed to listen", "address", cfg.JoinListen, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := run(ctx, cfg, ln, joinLn, logger); err != nil {
+		logger.Error("manager failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// Runs the manager until the context is cancelled, then shuts down
+// gracefully within the configured shutdown timeout. Worker
+// registrations are only served with a join listener.
+func run(ctx context.Context, cfg config.Manager, ln, joinLn net.Listener, logger *slog.Logger) error {
+	serverTLS, clientTLS, err := joinTLS(cfg)
+	if err != nil {
+		return err
+	}
+
+	m := metrics.New(metrics.WithSignaller(manager.NewSlogSignaller(logger)))
+
+	// the manager outlives the signal context, so running
+	// passes are left to finish when shutting down
+	mgr, err := manager.New(context.WithoutCancel(ctx), append(cfg.Options(), manager.WithSignaller(m))...)
+	if err != nil {
+		return fmt.Errorf("failed to create manager: %w", err)
+	}
+
+	m.SetInventory(mgr)
+
+	h := health.New()
+	h.AddCheck("state", func(ctx context.Context) error {
+		_, err := mgr.Workers(ctx)
+		return err
+	})
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+	mux.Handle("GET /metrics", m.Handler())
+	mux.Handle("/", admin.New(mgr))
+
+	srvs := map[net.Listener]*http.Server{
+		ln: {Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+	}
+
+	if joinLn != nil {
+		var join http.Handler
+		if clientTLS != nil {
+			join = transport.RequireClientCert(transport.NewJoinHandler(mgr, transport.WithTLSConfig(clientTLS)))
+		} else {
+			join = transport.NewJoinHandler(mgr)
+		}
+
+		if serverTLS != nil {
+			joinLn = tls.NewListener(joinLn, serverTLS)
+		}
+
+		srvs[joinLn] = &http.Server{Handler: join, ReadHeaderTimeout: 10 * time.Second}
+	}
+
+	errCh := make(chan error, len(srvs))
+	for l, srv := range srvs {
+		go func() {
+			errCh <- srv.Serve(l)
+		}()
+	}
+
+	attrs := []any{"address", ln.Addr().String()}
+	if joinLn != nil {
+		attrs = append(attrs, "joinAddress", joinLn.Addr().String())
+	}
+
+	h.SetReady(true)
+	logger.Info("manager started", attrs...)
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		for _, srv := range srvs {
+			_ = srv.Close()
+		}
+
+		_ = mgr.Stop()
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+
+	logger.Info("shutting down", "timeout", cfg.ShutdownTimeout)
+	h.SetReady(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// stopping the manager first ends any event streams,
+	// which would otherwise hold the server open
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- mgr.Stop()
+	}()
+
+	var errs []error
+	select {
+	case err := <-stopped:
+		errs = append(errs, err)
+	case <-shutdownCtx.Done():
+		errs = append(errs, fmt.Errorf("manager did not stop in time: %w", shutdownCtx.Err()))
+	}
+
+	for _, srv := range srvs {
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down server: %w", err))
+			_ = srv.Close()
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Loads the TLS config of the join listener, and with a client CA
+// the one workers are dialed back with. Both are nil without a join
+// certificate.
+func joinTLS(cfg config.Manager) (*tls.Config, *tls.Config, error) {
+	if cfg.JoinTLSCert == "" {
+		return nil, nil, nil
+	}
+
+	server, err := transport.ServerTLSConfig(cfg.JoinTLSCert, cfg.JoinTLSKey, cfg.JoinClientCA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load join TLS config: %w", err)
+	}
+
+	if cfg.JoinClientCA == "" {
+		return server, nil, nil
+	}
+
+	client, err := transport.ClientTLSConfig(cfg.JoinTLSCert, cfg.JoinTLSKey, cfg.JoinClientCA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load join TLS config: %w", err)
+	}
+
+	return server, client, nil
+}
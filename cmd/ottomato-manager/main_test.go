@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/config"
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+)
+
+func TestRun(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	joinLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	cfg := config.Manager{ShutdownTimeout: 5 * time.Second}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, cfg, ln, joinLn, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	}()
+
+	// connections dialed but never used would hold up the shutdown
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	url := "http://" + ln.Addr().String()
+	for _, path := range []string{"/readyz", "/metrics", "/workers"} {
+		var status int
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			res, err := client.Get(url + path)
+			if err != nil {
+				continue
+			}
+			res.Body.Close()
+
+			if status = res.StatusCode; status == http.StatusOK {
+				break
+			}
+		}
+
+		if exp, recv := http.StatusOK, status; exp != recv {
+			t.Errorf("expected status %d from %s, but got: %d", exp, path, recv)
+		}
+	}
+
+	// registrations are only served on the join address
+	for addr, exp := range map[string]int{ln.Addr().String(): http.StatusNotFound, joinLn.Addr().String(): http.StatusBadRequest} {
+		res, err := client.Post("http://"+addr+transport.PathJoin, "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("unexpected error when joining: %v", err)
+		}
+		res.Body.Close()
+
+		if recv := res.StatusCode; exp != recv {
+			t.Errorf("expected status %d from %s on %s, but got: %d", exp, transport.PathJoin, addr, recv)
+		}
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error when shutting down: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the manager to shut down")
+	}
+}
+
+func TestRunJoinTLS(t *testing.T) {
+	cfg := config.Manager{
+		ShutdownTimeout: time.Second,
+		JoinTLSCert:     filepath.Join(t.TempDir(), "missing.crt"),
+		JoinTLSKey:      filepath.Join(t.TempDir(), "missing.key"),
+	}
+
+	// a join certificate which can't be loaded fails before serving
+	err := run(context.TODO(), cfg, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err == nil {
+		t.Errorf("expected an error for a missing join certificate")
+	}
+}
@@ -0,0 +1,215 @@
+// Command ottomato-worker runs a worker serving health checks and the
+// HTTP transport on a single address. With managerUrl it registers with
+// the manager on start, giving the advertise address to be dialed back
+// on, and leaves on shutdown. Its workloads echo their tasks back: they
+// stand in for those of an application, which runs the same daemon
+// around a factory of its own.
+//
+// With tlsCert and tlsKey the address serves TLS, and with tlsCa the
+// transport only answers callers with a certificate signed by that CA,
+// while health checks stay open. The worker then joins presenting its
+// own certificate.
+//
+//	ottomato-worker [--config FILE]
+//
+// The config file is YAML or JSON, and every setting can be overridden
+// with an OTTOMATO_WORKER_ environment variable, see package config.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/config"
+	"github.com/Telenor-NMS-SE/ottomato/health"
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+func main() {
+	path := flag.String("config", os.Getenv("OTTOMATO_CONFIG"), "path to a YAML or JSON config file")
+	flag.Parse()
+
+	cfg, err := config.LoadWorker(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ottomato-worker: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger, err := config.NewLogger(os.Stderr, cfg.LogLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ottomato-worker: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		logger.Error("failed to listen", "address", cfg.Listen, "error", err)
+		os.Exit(1)
+	}
+
+	if err := run(ctx, cfg, ln, logger); err != nil {
+		logger.Error("worker failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// Runs the worker until the context is cancelled, then shuts down
+// gracefully within the configured shutdown timeout. A worker which
+// fails to join its manager doesn't start.
+func run(ctx context.Context, cfg config.Worker, ln net.Listener, logger *slog.Logger) error {
+	serverTLS, clientTLS, err := workerTLS(cfg)
+	if err != nil {
+		return err
+	}
+
+	opts := append(cfg.Options(),
+		worker.WithErrorCallback(func(err error) {
+			logger.Error("worker error", "error", err)
+		}),
+		worker.WithEventCallback(func(_ context.Context, e worker.Event) {
+			logger.Debug("worker event", "type", e.EventType, "workload", e.WorkloadName, "message", e.Message)
+		}),
+	)
+
+	// the worker outlives the signal context, so its
+	// workloads are stopped in order when shutting down
+	w, err := worker.New(context.WithoutCancel(ctx), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create worker: %w", err)
+	}
+
+	var api http.Handler = transport.NewHandler(transport.NewService(w, newEcho))
+	if clientTLS != nil {
+		api = transport.RequireClientCert(api)
+	}
+
+	h := health.New()
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+	mux.Handle("/v1/", api)
+
+	if serverTLS != nil {
+		ln = tls.NewListener(ln, serverTLS)
+	}
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	// the listener is bound, so the manager can dial
+	// back as soon as the worker has registered
+	client := &http.Client{Timeout: 30 * time.Second}
+	if clientTLS != nil {
+		client.Transport = &http.Transport{TLSClientConfig: clientTLS}
+	}
+
+	var session manager.Session
+	if cfg.ManagerURL != "" {
+		session, err = transport.Join(ctx, client, cfg.ManagerURL, transport.JoinRequest{
+			Registration: manager.Registration{ID: w.GetWorkerID(), Capacity: cfg.Capacity},
+			Address:      cfg.Advertise,
+		})
+		if err != nil {
+			_ = srv.Close()
+			_ = w.Stop()
+			return fmt.Errorf("failed to join '%s': %w", cfg.ManagerURL, err)
+		}
+
+		logger.Info("worker joined", "manager", cfg.ManagerURL, "generation", session.Generation)
+	}
+
+	h.SetReady(true)
+	logger.Info("worker started", "id", w.GetWorkerID(), "address", ln.Addr().String())
+
+	select {
+	case <-ctx.Done():
+	case err := <-errCh:
+		_ = w.Stop()
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+
+	logger.Info("shutting down", "timeout", cfg.ShutdownTimeout)
+	h.SetReady(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	// leaving first lets the manager place the
+	// workloads elsewhere while they're stopped here
+	if session.Generation > 0 {
+		if err := transport.Leave(shutdownCtx, client, cfg.ManagerURL, session.WorkerID, session.Generation); err != nil {
+			errs = append(errs, fmt.Errorf("failed to leave '%s': %w", cfg.ManagerURL, err))
+		}
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- w.Stop()
+	}()
+
+	select {
+	case err := <-stopped:
+		errs = append(errs, err)
+	case <-shutdownCtx.Done():
+		errs = append(errs, fmt.Errorf("worker did not stop in time: %w", shutdownCtx.Err()))
+	}
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down server: %w", err))
+		_ = srv.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+// Loads the TLS config of the listener, and with a CA the one the
+// worker joins with. Both are nil without a certificate.
+func workerTLS(cfg config.Worker) (*tls.Config, *tls.Config, error) {
+	if cfg.TLSCert == "" {
+		return nil, nil, nil
+	}
+
+	server, err := transport.ServerTLSConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+
+	if cfg.TLSCA == "" {
+		return server, nil, nil
+	}
+
+	// health checks are served without a certificate,
+	// the transport is guarded on its own
+	server.ClientAuth = tls.VerifyClientCertIfGiven
+
+	client, err := transport.ClientTLSConfig(cfg.TLSCert, cfg.TLSKey, cfg.TLSCA)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+
+	return server, client, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/config"
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+func TestRun(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	cfg := config.Worker{ShutdownTimeout: 5 * time.Second}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, cfg, ln, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	}()
+
+	// connections dialed but never used would hold up the shutdown
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+	url := "http://" + ln.Addr().String()
+	for _, path := range []string{"/healthz", "/readyz"} {
+		var status int
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			res, err := client.Get(url + path)
+			if err != nil {
+				continue
+			}
+			res.Body.Close()
+
+			if status = res.StatusCode; status == http.StatusOK {
+				break
+			}
+		}
+
+		if exp, recv := http.StatusOK, status; exp != recv {
+			t.Errorf("expected status %d from %s, but got: %d", exp, path, recv)
+		}
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error when shutting down: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the worker to shut down")
+	}
+}
+
+func TestRunJoin(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	mgr, err := manager.New(
+		context.TODO(),
+		manager.WithSignaller(manager.NewSlogSignaller(logger)),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	msrv := httptest.NewServer(transport.NewJoinHandler(mgr))
+	defer msrv.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	cfg := config.Worker{
+		ID:              "worker0",
+		ShutdownTimeout: 5 * time.Second,
+		ManagerURL:      msrv.URL,
+		Advertise:       "http://" + ln.Addr().String(),
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, cfg, ln, logger)
+	}()
+
+	registered := func() bool {
+		workers, _ := mgr.Workers(context.TODO())
+		return slices.ContainsFunc(workers, func(w manager.Worker) bool { return w.GetID() == "worker0" })
+	}
+
+	for deadline := time.Now().Add(2 * time.Second); !registered() && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if !registered() {
+		t.Fatalf("expected the worker to join the manager")
+	}
+
+	if err := mgr.AddWorkload(context.TODO(), manager.NewWorkload("workload0")); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	mgr.Distribute()
+
+	// tasks are routed through the manager to the echo workload
+	res, err := mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "show", Args: []string{"version"}})
+	if err != nil || !res.Success {
+		t.Errorf("expected the task to run on the worker, but got: %+v, %v", res, err)
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error when shutting down: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the worker to shut down")
+	}
+
+	if registered() {
+		t.Errorf("expected the worker to leave the manager")
+	}
+}
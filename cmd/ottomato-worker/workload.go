@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// Workload of the stock daemon, answering every task with its command
+// and arguments. It stands in for the workloads of an application,
+// which runs the same daemon around a factory of its own.
+type echo struct {
+	name string
+}
+
+func newEcho(_ context.Context, id string) (worker.Workload, error) {
+	return &echo{name: id}, nil
+}
+
+func (e *echo) Init(context.Context) error {
+	return nil
+}
+
+func (e *echo) Ping(context.Context) error {
+	return nil
+}
+
+func (e *echo) RunTask(_ context.Context, task *worker.Task) (worker.Result, error) {
+	return worker.Result{
+		Command: task.Command,
+		Args:    task.Args,
+		Kwargs:  task.Kwargs,
+		Success: true,
+		Return:  task.Args,
+	}, nil
+}
+
+func (e *echo) Stop() error {
+	return nil
+}
+
+func (e *echo) Info() map[string]any {
+	return map[string]any{"kind": "echo"}
+}
+
+func (e *echo) Name() string {
+	return e.name
+}
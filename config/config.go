@@ -0,0 +1,116 @@
+// Package config loads daemon configuration from YAML or JSON files
+// and environment variables, and maps it to manager and worker options.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Reads a config file into v, then applies environment overrides
+// with the given prefix. An empty path only applies the environment.
+// JSON files are read as YAML, of which JSON is a subset.
+func Load(path, prefix string, v any) error {
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+
+		if err := yaml.Unmarshal(b, v); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidConfig, path, err)
+		}
+	}
+
+	return FromEnv(prefix, v)
+}
+
+// Sets every field tagged with `env:"NAME"` from the environment
+// variable <prefix>NAME, when it's set
+func FromEnv(prefix string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: expected a pointer to a struct", ErrInvalidConfig)
+	}
+
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	for i := range rt.NumField() {
+		name, ok := rt.Field(i).Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+
+		val, ok := os.LookupEnv(prefix + name)
+		if !ok {
+			continue
+		}
+
+		if err := set(rv.Field(i), val); err != nil {
+			return fmt.Errorf("%w: %s%s: %w", ErrInvalidConfig, prefix, name, err)
+		}
+	}
+
+	return nil
+}
+
+func set(f reflect.Value, val string) error {
+	if f.Kind() == reflect.Pointer {
+		v := reflect.New(f.Type().Elem())
+		if err := set(v.Elem(), val); err != nil {
+			return err
+		}
+
+		f.Set(v)
+		return nil
+	}
+
+	if f.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+
+		f.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(val)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		f.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return err
+		}
+
+		f.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+
+		f.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+
+	return nil
+}
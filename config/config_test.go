@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadManager(t *testing.T) {
+	path := writeFile(t, "manager.yaml", `
+id: manager0
+distributorInterval: 30s
+maxDelta: 3
+breakerRatio: 0.5
+breakerWindow: 10
+`)
+
+	t.Setenv(ManagerEnvPrefix+"MAX_DELTA", "7")
+	t.Setenv(ManagerEnvPrefix+"LISTEN", ":9090")
+
+	cfg, err := LoadManager(path)
+	if err != nil {
+		t.Fatalf("unexpected error when loading config: %v", err)
+	}
+
+	if exp, recv := "manager0", cfg.ID; exp != recv {
+		t.Errorf("expected id '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := 30*time.Second, cfg.DistributorInterval; exp != recv {
+		t.Errorf("expected distributor interval %s, but got: %s", exp, recv)
+	}
+
+	if exp, recv := 7, *cfg.MaxDelta; exp != recv {
+		t.Errorf("expected the environment to override max delta to %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := ":9090", cfg.Listen; exp != recv {
+		t.Errorf("expected listen address '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := 30*time.Second, cfg.ShutdownTimeout; exp != recv {
+		t.Errorf("expected default shutdown timeout %s, but got: %s", exp, recv)
+	}
+
+	// id, distributor interval, max delta and the breaker
+	if exp, recv := 4, len(cfg.Options()); exp != recv {
+		t.Errorf("expected %d option(s), but got: %d", exp, recv)
+	}
+}
+
+func TestLoadManagerZero(t *testing.T) {
+	path := writeFile(t, "manager.yaml", `
+maxDelta: 0
+maxAttempts: 0
+breakerRatio: 0
+`)
+
+	t.Setenv(ManagerEnvPrefix+"BACKFILL_TIMEOUT", "0s")
+
+	cfg, err := LoadManager(path)
+	if err != nil {
+		t.Fatalf("unexpected error when loading config: %v", err)
+	}
+
+	if cfg.MaxDelta == nil || cfg.MaxAttempts == nil || cfg.BreakerRatio == nil || cfg.BackfillTimeout == nil {
+		t.Fatalf("expected zero values to be set, but got: %+v", cfg)
+	}
+
+	// max delta, max attempts, the breaker and the backfill
+	if exp, recv := 4, len(cfg.Options()); exp != recv {
+		t.Errorf("expected %d option(s), but got: %d", exp, recv)
+	}
+
+	if exp, recv := 0, len(Manager{}.Options()); exp != recv {
+		t.Errorf("expected no options of an empty config, but got: %d", recv)
+	}
+}
+
+func TestLoadWorker(t *testing.T) {
+	path := writeFile(t, "worker.json", `{"id": "worker0", "pingTimeout": "5s", "pingdownThreshold": 3, "taskQueueDepth": 0}`)
+
+	t.Setenv(WorkerEnvPrefix+"INIT_TIMEOUT", "1m")
+
+	cfg, err := LoadWorker(path)
+	if err != nil {
+		t.Fatalf("unexpected error when loading config: %v", err)
+	}
+
+	if exp, recv := 5*time.Second, cfg.PingTimeout; exp != recv {
+		t.Errorf("expected ping timeout %s, but got: %s", exp, recv)
+	}
+
+	if exp, recv := time.Minute, cfg.InitTimeout; exp != recv {
+		t.Errorf("expected init timeout %s, but got: %s", exp, recv)
+	}
+
+	// an unbounded queue is a setting of its own
+	if cfg.TaskQueueDepth == nil || *cfg.TaskQueueDepth != 0 {
+		t.Errorf("expected task queue depth 0, but got: %v", cfg.TaskQueueDepth)
+	}
+
+	if exp, recv := 5, len(cfg.Options()); exp != recv {
+		t.Errorf("expected %d option(s), but got: %d", exp, recv)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := writeFile(t, "manager.yaml", "maxDelta: many\n")
+	if _, err := LoadManager(path); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected '%v', but got: %v", ErrInvalidConfig, err)
+	}
+
+	path = writeFile(t, "manager.yaml", "retryBackoffBase: 1s\n")
+	if _, err := LoadManager(path); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected '%v' for a partial backoff, but got: %v", ErrInvalidConfig, err)
+	}
+
+	path = writeFile(t, "manager.yaml", "joinClientCa: ca.crt\n")
+	if _, err := LoadManager(path); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected '%v' for a client CA without a certificate, but got: %v", ErrInvalidConfig, err)
+	}
+
+	path = writeFile(t, "worker.yaml", "managerUrl: http://manager0:8090\n")
+	if _, err := LoadWorker(path); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected '%v' for a manager without an advertised address, but got: %v", ErrInvalidConfig, err)
+	}
+
+	t.Setenv(WorkerEnvPrefix+"PING_TIMEOUT", "soon")
+	if _, err := LoadWorker(""); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected '%v', but got: %v", ErrInvalidConfig, err)
+	}
+
+	if _, err := LoadWorker(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Errorf("expected an error for a missing file")
+	}
+
+	if _, err := NewLogger(os.Stderr, "loud"); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected '%v', but got: %v", ErrInvalidConfig, err)
+	}
+}
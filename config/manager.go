@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+// Environment variable prefix of the manager config
+const ManagerEnvPrefix = "OTTOMATO_MANAGER_"
+
+// Configuration of a manager daemon. Zero values keep the defaults
+// of the manager package, except for the pointer fields, where zero
+// is a setting of its own and only unset values keep the defaults.
+type Manager struct {
+	ID string `yaml:"id" env:"ID"`
+
+	// Address serving the admin API, metrics and health checks, default: ":8080"
+	Listen string `yaml:"listen" env:"LISTEN"`
+	// Address serving worker registrations, which dial back to the
+	// address a worker gives, default: none, registrations are refused
+	JoinListen string `yaml:"joinListen" env:"JOIN_LISTEN"`
+	// Certificate and key of the join address, which then serves TLS
+	JoinTLSCert string `yaml:"joinTlsCert" env:"JOIN_TLS_CERT"`
+	JoinTLSKey  string `yaml:"joinTlsKey" env:"JOIN_TLS_KEY"`
+	// CA signing worker certificates. When set, registrations without
+	// a verified certificate are refused, and workers serving TLS are
+	// dialed back with the join certificate, trusting the same CA.
+	JoinClientCA string `yaml:"joinClientCa" env:"JOIN_CLIENT_CA"`
+	// Time given to in-flight work on shutdown, default: 30 seconds
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+	// One of debug, info, warn or error, default: info
+	LogLevel string `yaml:"logLevel" env:"LOG_LEVEL"`
+
+	DistributorInterval  time.Duration `yaml:"distributorInterval" env:"DISTRIBUTOR_INTERVAL"`
+	DistributionDeadline time.Duration `yaml:"distributionDeadline" env:"DISTRIBUTION_DEADLINE"`
+	RebalanceInterval    time.Duration `yaml:"rebalanceInterval" env:"REBALANCE_INTERVAL"`
+	CleanupInterval      time.Duration `yaml:"cleanupInterval" env:"CLEANUP_INTERVAL"`
+	CleanupMaxTime       time.Duration `yaml:"cleanupMaxTime" env:"CLEANUP_MAX_TIME"`
+	MaxDelta             *int          `yaml:"maxDelta" env:"MAX_DELTA"`
+
+	LoadTimeout              time.Duration `yaml:"loadTimeout" env:"LOAD_TIMEOUT"`
+	UnloadTimeout            time.Duration `yaml:"unloadTimeout" env:"UNLOAD_TIMEOUT"`
+	MaxConcurrentCalls       int           `yaml:"maxConcurrentCalls" env:"MAX_CONCURRENT_CALLS"`
+	MaxConcurrentWorkerCalls int           `yaml:"maxConcurrentWorkerCalls" env:"MAX_CONCURRENT_WORKER_CALLS"`
+
+	// Both or neither are set
+	RetryBackoffBase time.Duration `yaml:"retryBackoffBase" env:"RETRY_BACKOFF_BASE"`
+	RetryBackoffMax  time.Duration `yaml:"retryBackoffMax" env:"RETRY_BACKOFF_MAX"`
+	MaxAttempts      *int          `yaml:"maxAttempts" env:"MAX_ATTEMPTS"`
+
+	// A ratio of 0 disables the breakers
+	BreakerRatio    *float64      `yaml:"breakerRatio" env:"BREAKER_RATIO"`
+	BreakerWindow   int           `yaml:"breakerWindow" env:"BREAKER_WINDOW"`
+	BreakerCooldown time.Duration `yaml:"breakerCooldown" env:"BREAKER_COOLDOWN"`
+
+	HistorySize int `yaml:"historySize" env:"HISTORY_SIZE"`
+
+	// A timeout of 0 disables the backfill
+	BackfillTimeout *time.Duration `yaml:"backfillTimeout" env:"BACKFILL_TIMEOUT"`
+	TaskWait        time.Duration  `yaml:"taskWait" env:"TASK_WAIT"`
+}
+
+// Loads a manager config from a file and the environment, with
+// daemon defaults filled in
+func LoadManager(path string) (Manager, error) {
+	cfg := Manager{
+		Listen:          ":8080",
+		ShutdownTimeout: 30 * time.Second,
+		LogLevel:        "info",
+	}
+
+	if err := Load(path, ManagerEnvPrefix, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Checks settings which only make sense together
+func (c Manager) Validate() error {
+	if (c.RetryBackoffBase > 0) != (c.RetryBackoffMax > 0) {
+		return fmt.Errorf("%w: retryBackoffBase and retryBackoffMax must be set together", ErrInvalidConfig)
+	}
+
+	if c.RetryBackoffBase > c.RetryBackoffMax {
+		return fmt.Errorf("%w: retryBackoffBase exceeds retryBackoffMax", ErrInvalidConfig)
+	}
+
+	if (c.JoinTLSCert != "") != (c.JoinTLSKey != "") {
+		return fmt.Errorf("%w: joinTlsCert and joinTlsKey must be set together", ErrInvalidConfig)
+	}
+
+	if c.JoinClientCA != "" && c.JoinTLSCert == "" {
+		return fmt.Errorf("%w: joinClientCa requires joinTlsCert and joinTlsKey", ErrInvalidConfig)
+	}
+
+	if c.BreakerRatio != nil && (*c.BreakerRatio < 0 || *c.BreakerRatio > 1) {
+		return fmt.Errorf("%w: breakerRatio must be between 0 and 1", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// Maps the config to manager options, leaving out unset values
+func (c Manager) Options() []manager.Option {
+	opts := []manager.Option{}
+
+	if c.ID != "" {
+		opts = append(opts, manager.WithManagerID(c.ID))
+	}
+
+	if c.DistributorInterval > 0 {
+		opts = append(opts, manager.WithDistributorInterval(c.DistributorInterval))
+	}
+
+	if c.DistributionDeadline > 0 {
+		opts = append(opts, manager.WithDistributionDeadline(c.DistributionDeadline))
+	}
+
+	if c.RebalanceInterval > 0 {
+		opts = append(opts, manager.WithRebalanceInterval(c.RebalanceInterval))
+	}
+
+	if c.CleanupInterval > 0 {
+		opts = append(opts, manager.WithCleanupInterval(c.CleanupInterval))
+	}
+
+	if c.CleanupMaxTime > 0 {
+		opts = append(opts, manager.WithCleanupMaxTime(c.CleanupMaxTime))
+	}
+
+	if c.MaxDelta != nil {
+		opts = append(opts, manager.WithMaxDelta(*c.MaxDelta))
+	}
+
+	if c.LoadTimeout > 0 {
+		opts = append(opts, manager.WithLoadTimeout(c.LoadTimeout))
+	}
+
+	if c.UnloadTimeout > 0 {
+		opts = append(opts, manager.WithUnloadTimeout(c.UnloadTimeout))
+	}
+
+	if c.MaxConcurrentCalls > 0 {
+		opts = append(opts, manager.WithMaxConcurrentCalls(c.MaxConcurrentCalls))
+	}
+
+	if c.MaxConcurrentWorkerCalls > 0 {
+		opts = append(opts, manager.WithMaxConcurrentWorkerCalls(c.MaxConcurrentWorkerCalls))
+	}
+
+	if c.RetryBackoffBase > 0 && c.RetryBackoffMax > 0 {
+		opts = append(opts, manager.WithRetryBackoff(c.RetryBackoffBase, c.RetryBackoffMax))
+	}
+
+	if c.MaxAttempts != nil {
+		opts = append(opts, manager.WithMaxAttempts(*c.MaxAttempts))
+	}
+
+	// unset breaker settings keep the manager defaults
+	if c.BreakerRatio != nil || c.BreakerWindow > 0 || c.BreakerCooldown > 0 {
+		ratio, window, cooldown := 0.5, c.BreakerWindow, c.BreakerCooldown
+		if c.BreakerRatio != nil {
+			ratio = *c.BreakerRatio
+		}
+
+		if window == 0 {
+			window = 10
+		}
+
+		if cooldown == 0 {
+			cooldown = time.Minute
+		}
+
+		opts = append(opts, manager.WithCircuitBreaker(ratio, window, cooldown))
+	}
+
+	if c.HistorySize > 0 {
+		opts = append(opts, manager.WithHistorySize(c.HistorySize))
+	}
+
+	if c.BackfillTimeout != nil {
+		opts = append(opts, manager.WithBackfillTimeout(*c.BackfillTimeout))
+	}
+
+	if c.TaskWait > 0 {
+		opts = append(opts, manager.WithTaskWait(c.TaskWait))
+	}
+
+	return opts
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// Environment variable prefix of the worker config
+const WorkerEnvPrefix = "OTTOMATO_WORKER_"
+
+// Configuration of a worker daemon. Zero values keep the defaults
+// of the worker package, except for the pointer fields, where zero
+// is a setting of its own and only unset values keep the defaults.
+type Worker struct {
+	ID string `yaml:"id" env:"ID"`
+
+	// Address serving health checks and the transport, default: ":8081"
+	Listen string `yaml:"listen" env:"LISTEN"`
+	// Join address of the manager the worker registers with on start
+	// and leaves on shutdown, default: none, the worker waits to be
+	// added by other means
+	ManagerURL string `yaml:"managerUrl" env:"MANAGER_URL"`
+	// URL the manager dials the worker back on, required with managerUrl
+	Advertise string `yaml:"advertise" env:"ADVERTISE"`
+	// Max amount of workloads placed on the worker, 0 for no limit
+	Capacity int `yaml:"capacity" env:"CAPACITY"`
+	// Certificate and key of the listen address, which then serves TLS
+	TLSCert string `yaml:"tlsCert" env:"TLS_CERT"`
+	TLSKey  string `yaml:"tlsKey" env:"TLS_KEY"`
+	// CA signing the manager's certificates. When set, the transport is
+	// refused to callers without a verified certificate, and the worker
+	// joins presenting its own certificate, trusting the same CA.
+	TLSCA string `yaml:"tlsCa" env:"TLS_CA"`
+	// Time given to in-flight work on shutdown, default: 30 seconds
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+	// One of debug, info, warn or error, default: info
+	LogLevel string `yaml:"logLevel" env:"LOG_LEVEL"`
+
+	PingSplayLo       time.Duration `yaml:"pingSplayLo" env:"PING_SPLAY_LO"`
+	PingSplayHi       time.Duration `yaml:"pingSplayHi" env:"PING_SPLAY_HI"`
+	PingTimeout       time.Duration `yaml:"pingTimeout" env:"PING_TIMEOUT"`
+	PingdownThreshold int           `yaml:"pingdownThreshold" env:"PINGDOWN_THRESHOLD"`
+	InitTimeout       time.Duration `yaml:"initTimeout" env:"INIT_TIMEOUT"`
+	JobTTL            time.Duration `yaml:"jobTTL" env:"JOB_TTL"`
+	TaskConcurrency   int           `yaml:"taskConcurrency" env:"TASK_CONCURRENCY"`
+	// A depth of 0 leaves the task queues unbounded
+	TaskQueueDepth *int `yaml:"taskQueueDepth" env:"TASK_QUEUE_DEPTH"`
+}
+
+// Loads a worker config from a file and the environment, with
+// daemon defaults filled in
+func LoadWorker(path string) (Worker, error) {
+	cfg := Worker{
+		Listen:          ":8081",
+		ShutdownTimeout: 30 * time.Second,
+		LogLevel:        "info",
+	}
+
+	if err := Load(path, WorkerEnvPrefix, &cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Checks settings which only make sense together
+func (c Worker) Validate() error {
+	if c.ManagerURL != "" && c.Advertise == "" {
+		return fmt.Errorf("%w: managerUrl requires advertise", ErrInvalidConfig)
+	}
+
+	if (c.TLSCert != "") != (c.TLSKey != "") {
+		return fmt.Errorf("%w: tlsCert and tlsKey must be set together", ErrInvalidConfig)
+	}
+
+	if c.TLSCA != "" && c.TLSCert == "" {
+		return fmt.Errorf("%w: tlsCa requires tlsCert and tlsKey", ErrInvalidConfig)
+	}
+
+	return nil
+}
+
+// Maps the config to worker options, leaving out unset values
+func (c Worker) Options() []worker.Option {
+	opts := []worker.Option{}
+
+	if c.ID != "" {
+		opts = append(opts, worker.WithWorkerID(c.ID))
+	}
+
+	if c.PingSplayLo > 0 || c.PingSplayHi > 0 {
+		lo, hi := c.PingSplayLo, c.PingSplayHi
+		if lo == 0 {
+			lo = worker.DEFAULT_SPLAY_LO
+		}
+
+		if hi == 0 {
+			hi = worker.DEFAULT_SPLAY_HI
+		}
+
+		opts = append(opts, worker.WithPingSplay(hi, lo))
+	}
+
+	if c.PingTimeout > 0 {
+		opts = append(opts, worker.WithPingTimeout(c.PingTimeout))
+	}
+
+	if c.PingdownThreshold > 0 {
+		opts = append(opts, worker.WithPingdownThreshold(c.PingdownThreshold))
+	}
+
+	if c.InitTimeout > 0 {
+		opts = append(opts, worker.WithInitTimeout(c.InitTimeout))
+	}
+
+	if c.JobTTL > 0 {
+		opts = append(opts, worker.WithJobTTL(c.JobTTL))
+	}
+
+	if c.TaskConcurrency > 0 {
+		opts = append(opts, worker.WithTaskConcurrency(c.TaskConcurrency))
+	}
+
+	if c.TaskQueueDepth != nil {
+		opts = append(opts, worker.WithTaskQueueDepth(*c.TaskQueueDepth))
+	}
+
+	return opts
+}
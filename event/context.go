@@ -0,0 +1,31 @@
+package event
+
+import "context"
+
+type correlationKey struct{}
+
+// Returns a context carrying a correlation id, which is attached to
+// every event emitted on behalf of the context
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// Returns a context carrying a new correlation id, unless it already
+// carries one
+func EnsureCorrelationID(ctx context.Context) context.Context {
+	if CorrelationID(ctx) != "" {
+		return ctx
+	}
+
+	return WithCorrelationID(ctx, NewID())
+}
+
+// Returns the correlation id carried by the context, or an empty string
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
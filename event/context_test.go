@@ -0,0 +1,27 @@
+package event
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCorrelationID(t *testing.T) {
+	ctx := context.TODO()
+	if recv := CorrelationID(ctx); recv != "" {
+		t.Errorf("expected no correlation id, but got: '%s'", recv)
+	}
+
+	ctx = EnsureCorrelationID(ctx)
+	id := CorrelationID(ctx)
+	if id == "" {
+		t.Fatalf("expected a correlation id, but got none")
+	}
+
+	if recv := CorrelationID(EnsureCorrelationID(ctx)); id != recv {
+		t.Errorf("expected correlation id '%s' to be kept, but got: '%s'", id, recv)
+	}
+
+	if exp, recv := "correlation0", CorrelationID(WithCorrelationID(ctx, "correlation0")); exp != recv {
+		t.Errorf("expected correlation id '%s', but got: '%s'", exp, recv)
+	}
+}
@@ -0,0 +1,153 @@
+// Package event holds the envelope shared by manager and worker events,
+// so consumers can handle events from both packages uniformly.
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NamespaceManager = "manager"
+	NamespaceWorker  = "worker"
+
+	// Prefix of every source, e.g. /ottomato/workers/worker0
+	SourcePrefix = "/ottomato"
+)
+
+var (
+	ErrInvalidSource = errors.New("invalid event source")
+)
+
+// Namespaced event type, e.g. manager.workload.added
+type Type string
+
+func NewType(namespace, name string) Type {
+	return Type(namespace + "." + name)
+}
+
+// Returns the namespace of the type, e.g. manager
+func (t Type) Namespace() string {
+	ns, _, _ := strings.Cut(string(t), ".")
+	return ns
+}
+
+// Returns the type without its namespace, e.g. workload.added
+func (t Type) Name() string {
+	_, name, _ := strings.Cut(string(t), ".")
+	return name
+}
+
+type Envelope struct {
+	ID            string    `json:"id"`
+	Type          Type      `json:"type"`
+	Source        string    `json:"source"`
+	Subject       string    `json:"subject,omitempty"`
+	Time          time.Time `json:"time"`
+	CorrelationID string    `json:"correlationId,omitempty"`
+	Data          any       `json:"data,omitempty"`
+}
+
+// Creates an envelope with a new id, the current time and the
+// correlation id carried by the context, if any
+func New(ctx context.Context, typ Type, source, subject string, data any) Envelope {
+	return Envelope{
+		ID:            NewID(),
+		Type:          typ,
+		Source:        source,
+		Subject:       subject,
+		Time:          time.Now().UTC(),
+		CorrelationID: CorrelationID(ctx),
+		Data:          data,
+	}
+}
+
+func NewID() string {
+	return uuid.NewString()
+}
+
+// Keeps the data as raw JSON until it is decoded with DecodeData
+func (e *Envelope) UnmarshalJSON(input []byte) error {
+	type envelope Envelope
+
+	tmp := &struct {
+		*envelope
+		Data json.RawMessage `json:"data,omitempty"`
+	}{
+		envelope: (*envelope)(e),
+	}
+
+	if err := json.Unmarshal(input, &tmp); err != nil {
+		return err
+	}
+
+	e.Data = nil
+	if len(tmp.Data) > 0 && !bytes.Equal(tmp.Data, []byte("null")) {
+		e.Data = tmp.Data
+	}
+
+	return nil
+}
+
+// Decodes the data in to v, regardless of whether the envelope was
+// created in-process or deserialized
+func (e Envelope) DecodeData(v any) error {
+	if e.Data == nil {
+		return nil
+	}
+
+	raw, ok := e.Data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(e.Data); err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(raw, v)
+}
+
+func ManagerSource(id string) string {
+	return source(NamespaceManager, id)
+}
+
+func WorkerSource(id string) string {
+	return source(NamespaceWorker, id)
+}
+
+func source(namespace, id string) string {
+	return SourcePrefix + "/" + namespace + "s/" + url.PathEscape(id)
+}
+
+// Splits a source in to the namespace and id of whoever emitted the event
+func ParseSource(src string) (namespace string, id string, err error) {
+	rest, ok := strings.CutPrefix(src, SourcePrefix+"/")
+	if !ok {
+		return "", "", fmt.Errorf("%w: '%s'", ErrInvalidSource, src)
+	}
+
+	kind, id, ok := strings.Cut(rest, "/")
+	if !ok || id == "" {
+		return "", "", fmt.Errorf("%w: '%s'", ErrInvalidSource, src)
+	}
+
+	switch kind {
+	case NamespaceManager + "s":
+		namespace = NamespaceManager
+	case NamespaceWorker + "s":
+		namespace = NamespaceWorker
+	default:
+		return "", "", fmt.Errorf("%w: '%s'", ErrInvalidSource, src)
+	}
+
+	id, err = url.PathUnescape(id)
+	return namespace, id, err
+}
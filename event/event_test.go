@@ -0,0 +1,103 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestType(t *testing.T) {
+	typ := NewType(NamespaceManager, "workload.added")
+
+	if exp, recv := "manager", typ.Namespace(); exp != recv {
+		t.Errorf("expected namespace '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "workload.added", typ.Name(); exp != recv {
+		t.Errorf("expected name '%s', but got: '%s'", exp, recv)
+	}
+}
+
+func TestSource(t *testing.T) {
+	cases := map[string]struct {
+		Source    string
+		Namespace string
+		ID        string
+	}{
+		"manager": {Source: ManagerSource("manager0"), Namespace: NamespaceManager, ID: "manager0"},
+		"worker":  {Source: WorkerSource("zone-a/worker0"), Namespace: NamespaceWorker, ID: "zone-a/worker0"},
+	}
+
+	for name, c := range cases {
+		ns, id, err := ParseSource(c.Source)
+		if err != nil {
+			t.Fatalf("%s: unexpected error when parsing source: %v", name, err)
+		}
+
+		if c.Namespace != ns || c.ID != id {
+			t.Errorf("%s: expected '%s' and '%s', but got: '%s' and '%s'", name, c.Namespace, c.ID, ns, id)
+		}
+	}
+
+	for _, src := range []string{"", "/ottomato", "/ottomato/workers/", "/ottomato/jobs/job0", "/other/workers/worker0"} {
+		if _, _, err := ParseSource(src); !errors.Is(err, ErrInvalidSource) {
+			t.Errorf("expected '%v' for '%s', but got: %v", ErrInvalidSource, src, err)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctx := WithCorrelationID(context.TODO(), "correlation0")
+	e := New(ctx, "worker.workload.added", WorkerSource("worker0"), "workload0", nil)
+
+	if e.ID == "" || e.Time.IsZero() {
+		t.Errorf("expected a generated id and time, but got: '%s' and '%s'", e.ID, e.Time)
+	}
+
+	if exp, recv := "correlation0", e.CorrelationID; exp != recv {
+		t.Errorf("expected correlation id '%s', but got: '%s'", exp, recv)
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	type data struct {
+		Message string `json:"message"`
+	}
+
+	exp := New(context.TODO(), "worker.workload.dead", WorkerSource("worker0"), "workload0", data{Message: "hello"})
+
+	// in-process
+	var recv data
+	if err := exp.DecodeData(&recv); err != nil {
+		t.Fatalf("unexpected error when decoding data: %v", err)
+	}
+
+	if exp, recv := "hello", recv.Message; exp != recv {
+		t.Errorf("expected message '%s', but got: '%s'", exp, recv)
+	}
+
+	// deserialized
+	b, err := json.Marshal(exp)
+	if err != nil {
+		t.Fatalf("unexpected error when serializing envelope: %v", err)
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(b, &env); err != nil {
+		t.Fatalf("unexpected error when deserializing envelope: %v", err)
+	}
+
+	recv = data{}
+	if err := env.DecodeData(&recv); err != nil {
+		t.Fatalf("unexpected error when decoding data: %v", err)
+	}
+
+	if exp, recv := "hello", recv.Message; exp != recv {
+		t.Errorf("expected message '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp.ID != env.ID || exp.Type != env.Type || !exp.Time.Equal(env.Time) {
+		t.Errorf("expected envelope to survive a round trip\nexp:  %+v\nrecv: %+v", exp, env)
+	}
+}
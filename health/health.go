@@ -0,0 +1,112 @@
+// Package health serves liveness and readiness endpoints for daemons.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Reports whether a dependency is usable
+type Check func(context.Context) error
+
+type Health struct {
+	ready atomic.Bool
+
+	mu     sync.RWMutex
+	checks map[string]Check
+
+	timeout time.Duration
+}
+
+type Option func(*Health)
+
+// Set the time a single readiness check may take, default: 5 seconds
+func WithTimeout(t time.Duration) Option {
+	return func(h *Health) {
+		h.timeout = t
+	}
+}
+
+func New(opts ...Option) *Health {
+	h := &Health{
+		checks:  map[string]Check{},
+		timeout: 5 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Adds a named check which must pass for the daemon to be ready
+func (h *Health) AddCheck(name string, c Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks[name] = c
+}
+
+// Marks the daemon as ready or not, e.g. when starting up or shutting down
+func (h *Health) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Registers GET /healthz and GET /readyz on the mux
+func (h *Health) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", h.live)
+	mux.HandleFunc("GET /readyz", h.readiness)
+}
+
+func (h *Health) live(w http.ResponseWriter, r *http.Request) {
+	write(w, http.StatusOK, Status{Status: "ok"})
+}
+
+func (h *Health) readiness(w http.ResponseWriter, r *http.Request) {
+	if !h.ready.Load() {
+		write(w, http.StatusServiceUnavailable, Status{Status: "not ready"})
+		return
+	}
+
+	h.mu.RLock()
+	checks := maps.Clone(h.checks)
+	h.mu.RUnlock()
+
+	res := Status{Status: "ok", Checks: map[string]string{}}
+	status := http.StatusOK
+
+	for name, c := range checks {
+		ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+		err := c(ctx)
+		cancel()
+
+		if err != nil {
+			res.Status = "not ready"
+			res.Checks[name] = err.Error()
+			status = http.StatusServiceUnavailable
+			continue
+		}
+
+		res.Checks[name] = "ok"
+	}
+
+	write(w, status, res)
+}
+
+func write(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
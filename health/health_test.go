@@ -0,0 +1,46 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func get(t *testing.T, h *Health, path string) int {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return rec.Code
+}
+
+func TestHealth(t *testing.T) {
+	h := New()
+
+	if exp, recv := http.StatusOK, get(t, h, "/healthz"); exp != recv {
+		t.Errorf("expected liveness status %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := http.StatusServiceUnavailable, get(t, h, "/readyz"); exp != recv {
+		t.Errorf("expected readiness status %d before ready, but got: %d", exp, recv)
+	}
+
+	h.SetReady(true)
+	if exp, recv := http.StatusOK, get(t, h, "/readyz"); exp != recv {
+		t.Errorf("expected readiness status %d, but got: %d", exp, recv)
+	}
+
+	h.AddCheck("state", func(context.Context) error {
+		return errors.New("unreachable")
+	})
+
+	if exp, recv := http.StatusServiceUnavailable, get(t, h, "/readyz"); exp != recv {
+		t.Errorf("expected readiness status %d with a failing check, but got: %d", exp, recv)
+	}
+}
@@ -0,0 +1,146 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+// Implemented by workers which can tell which workloads they're running,
+// used to backfill the associations when the manager starts after them
+type WorkloadLister interface {
+	Workloads(context.Context) ([]string, error)
+}
+
+// Collects the workloads of the workers already in the state storage and
+// assigns them, releasing the distributor when done. Workers added later
+// are backfilled as they're added.
+func (m *Manager) backfill() {
+	defer close(m.backfilled)
+
+	ctx, cancel := context.WithTimeout(event.EnsureCorrelationID(m.ctx), m.backfillTimeout)
+	defer cancel()
+
+	ctx, span := m.startJob(ctx, "backfill")
+	defer span.End()
+
+	m.state.Lock()
+	workers, err := m.state.GetAllWorkers(ctx)
+	m.state.Unlock()
+
+	if err != nil {
+		m.emitError(ctx, err)
+		return
+	}
+
+	for _, w := range workers {
+		lister, ok := w.(WorkloadLister)
+		if !ok {
+			continue
+		}
+
+		ids, err := lister.Workloads(ctx)
+		if err != nil {
+			m.emitError(ctx, err)
+			continue
+		}
+
+		m.state.Lock()
+		m.adopt(ctx, w, ids)
+		m.state.Unlock()
+	}
+}
+
+// Lists the workloads a worker reports running, bound by the backfill
+// timeout. Returns none when the backfill is disabled or the worker
+// can't tell.
+func (m *Manager) running(ctx context.Context, w Worker) []string {
+	lister, ok := w.(WorkloadLister)
+	if !ok || m.backfillTimeout <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.backfillTimeout)
+	defer cancel()
+
+	ids, err := lister.Workloads(ctx)
+	if err != nil {
+		m.emitError(ctx, fmt.Errorf("failed to backfill workloads of '%s': %w", w.GetID(), err))
+		return nil
+	}
+
+	return ids
+}
+
+// Holds the distributor back until the backfill is done, or the backfill
+// timeout has passed since the manager started
+func (m *Manager) waitBackfill(ctx context.Context) {
+	if m.backfilled == nil {
+		return
+	}
+
+	t := time.NewTimer(time.Until(m.started.Add(m.backfillTimeout)))
+	defer t.Stop()
+
+	select {
+	case <-m.backfilled:
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// Assigns the workloads a worker reports running, unless the manager
+// already has them associated elsewhere. Workloads left down, failed
+// or quarantined, e.g. by the worker's previous deletion, are put back
+// in line first, since they can't go straight to running. Expects the
+// state to be locked.
+func (m *Manager) adopt(ctx context.Context, w Worker, ids []string) {
+	for _, id := range ids {
+		wl, err := m.state.GetWorkload(ctx, id)
+		switch {
+		case errors.Is(err, ErrWorkloadNotFound):
+			wl = NewWorkload(id)
+		case err != nil:
+			m.emitError(ctx, err)
+			continue
+		}
+
+		_, err = m.state.GetAssociation(ctx, wl)
+		switch {
+		case err == nil:
+			continue
+		case !errors.Is(err, ErrMissingAssociation) && !errors.Is(err, ErrWorkerNotFound):
+			m.emitError(ctx, err)
+			continue
+		}
+
+		if err := m.reconcile(ctx, w, wl); err != nil {
+			m.emitError(ctx, err)
+			continue
+		}
+
+		m.assign(ctx, w, wl)
+	}
+}
+
+// Moves a workload a worker reports running to init, when its status
+// doesn't allow it to go to running directly, clearing its failures
+func (m *Manager) reconcile(ctx context.Context, w Worker, wl Workload) error {
+	switch wl.GetStatus() {
+	case StatusDown, StatusErr, StatusQuarantined:
+	default:
+		return nil
+	}
+
+	if err := m.transition(ctx, wl, StatusInit, "reported running by "+w.GetID()); err != nil {
+		return err
+	}
+
+	m.succeed(wl.GetID())
+	m.operationDone("load", wl.GetID())
+
+	return nil
+}
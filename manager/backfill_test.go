@@ -0,0 +1,226 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Worker reporting the workloads it's running
+type listingWorker struct {
+	mockWorker
+	delay     time.Duration
+	workloads []string
+}
+
+func (w *listingWorker) Workloads(ctx context.Context) ([]string, error) {
+	select {
+	case <-time.After(w.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return w.workloads, nil
+}
+
+func TestBackfill(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &listingWorker{
+				mockWorker: mockWorker{id: "worker0"},
+				delay:      100 * time.Millisecond,
+				workloads:  []string{"workload0", "workload1", "workload2"},
+			},
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+			"workload2": &mockWorkload{id: "workload2", status: StatusRunning},
+		},
+		associations: map[string]string{
+			"workload2": "worker1",
+		},
+	}
+
+	mgr, err := New(
+		context.TODO(),
+		WithStateStorage(state),
+		WithSignaller(&mockSignaller{}),
+		WithDistributorInterval(time.Hour),
+		WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer func() { _ = mgr.Stop() }()
+
+	// held back until the backfill is done, so nothing is placed anew
+	mgr.Distribute()
+
+	for _, id := range []string{"workload0", "workload1"} {
+		if exp, recv := "worker0", state.associations[id]; exp != recv {
+			t.Errorf("expected '%s' to be backfilled on '%s', but got: '%s'", id, exp, recv)
+		}
+
+		if exp, recv := StatusRunning, state.workloads[id].GetStatus(); exp != recv {
+			t.Errorf("expected '%s' to be '%s', but got: '%s'", id, exp, recv)
+		}
+	}
+
+	if exp, recv := "worker1", state.associations["workload2"]; exp != recv {
+		t.Errorf("expected a placed workload to stay on '%s', but got: '%s'", exp, recv)
+	}
+}
+
+func TestBackfillTimeout(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &listingWorker{
+				mockWorker: mockWorker{id: "worker0"},
+				delay:      time.Hour,
+				workloads:  []string{"workload0"},
+			},
+		},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	mgr, err := New(
+		context.TODO(),
+		WithStateStorage(state),
+		WithSignaller(&mockSignaller{}),
+		WithDistributorInterval(time.Hour),
+		WithRebalanceInterval(time.Hour),
+		WithBackfillTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer func() { _ = mgr.Stop() }()
+
+	start := time.Now()
+	mgr.Distribute()
+
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("expected distribution to go ahead after the timeout, but it took: %s", took)
+	}
+
+	if _, ok := state.associations["workload0"]; ok {
+		t.Errorf("expected nothing to be backfilled after the timeout")
+	}
+}
+
+func TestBackfillAddedWorker(t *testing.T) {
+	state := NewMemoryStore()
+
+	mgr, err := New(
+		context.TODO(),
+		WithStateStorage(state),
+		WithSignaller(&mockSignaller{}),
+		WithDistributorInterval(time.Hour),
+		WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer func() { _ = mgr.Stop() }()
+
+	// added after the startup backfill is done
+	mgr.Distribute()
+
+	w := &listingWorker{mockWorker: mockWorker{id: "worker0"}, workloads: []string{"workload0"}}
+	if err := mgr.AddWorker(context.TODO(), w); err != nil {
+		t.Fatalf("unexpected error when adding worker: %v", err)
+	}
+
+	if exp, recv := "worker0", state.associations["workload0"]; exp != recv {
+		t.Errorf("expected the reported workload to be assigned to '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := StatusRunning, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Errorf("expected status %s, but got: %s", exp, recv)
+	}
+}
+
+func TestBackfillDown(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker1": &mockWorker{id: "worker1", onLoad: func(wl Workload) {
+				t.Errorf("expected '%s' not to be loaded elsewhere", wl.GetID())
+			}},
+		},
+		workloads: map[string]Workload{
+			// left down and failed by the worker's previous deletion
+			"workload0": &mockWorkload{id: "workload0", status: StatusDown},
+			"workload1": &mockWorkload{id: "workload1", status: StatusErr},
+		},
+		associations: map[string]string{},
+	}
+
+	mgr := &Manager{
+		state:           state,
+		ctx:             context.TODO(),
+		signal:          &mockSignaller{},
+		calls:           newLimiter(0, 0),
+		backfillTimeout: time.Second,
+		failures:        map[string]*failure{"workload1": {attempts: 1}},
+	}
+
+	w := &listingWorker{mockWorker: mockWorker{id: "worker0"}, workloads: []string{"workload0", "workload1"}}
+	if err := mgr.AddWorker(context.TODO(), w); err != nil {
+		t.Fatalf("unexpected error when adding worker: %v", err)
+	}
+
+	mgr.distributor()
+
+	for _, id := range []string{"workload0", "workload1"} {
+		if exp, recv := "worker0", state.associations[id]; exp != recv {
+			t.Errorf("expected '%s' to be backfilled on '%s', but got: '%s'", id, exp, recv)
+		}
+
+		if exp, recv := StatusRunning, state.workloads[id].GetStatus(); exp != recv {
+			t.Errorf("expected '%s' to be %s, but got: %s", id, exp, recv)
+		}
+	}
+
+	if recv := mgr.Attempts("workload1"); recv != 0 {
+		t.Errorf("expected the failures of an adopted workload to be cleared, but got: %d", recv)
+	}
+}
+
+func TestRegisterAdopt(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads: map[string]Workload{
+			"workload1": &mockWorkload{id: "workload1", status: StatusRunning},
+		},
+		associations: map[string]string{
+			"workload1": "worker1",
+		},
+	}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	reg := Registration{ID: "worker0", Workloads: []string{"workload0", "workload1"}}
+	if _, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, reg); err != nil {
+		t.Fatalf("unexpected error when registering: %v", err)
+	}
+
+	if exp, recv := "worker0", state.associations["workload0"]; exp != recv {
+		t.Errorf("expected the reported workload to be assigned to '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "worker1", state.associations["workload1"]; exp != recv {
+		t.Errorf("expected a placed workload to stay on '%s', but got: '%s'", exp, recv)
+	}
+
+	if recv, _ := mgr.WorkerRegistration("worker0"); recv.Workloads != nil {
+		t.Errorf("expected the reported workloads not to be kept, but got: %v", recv.Workloads)
+	}
+}
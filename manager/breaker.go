@@ -0,0 +1,235 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type BreakerState uint8
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "invalid"
+	}
+}
+
+func (s BreakerState) MarshalJSON() ([]byte, error) {
+	str := s.String()
+	if str == "invalid" {
+		return []byte{}, fmt.Errorf("invalid breaker state")
+	}
+
+	return json.Marshal(str)
+}
+
+func (s *BreakerState) UnmarshalJSON(val []byte) error {
+	switch string(val) {
+	case `"closed"`:
+		*s = BreakerClosed
+	case `"open"`:
+		*s = BreakerOpen
+	case `"half-open"`:
+		*s = BreakerHalfOpen
+	default:
+		return fmt.Errorf("invalid breaker state")
+	}
+
+	return nil
+}
+
+// Circuit breaker for a single worker, tracking the outcome
+// of the last Load and Unload calls made towards it
+type breaker struct {
+	state    BreakerState
+	outcomes []bool // true for every failed call
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *breaker) ratio() float64 {
+	if len(b.outcomes) == 0 {
+		return 0
+	}
+
+	failed := 0
+	for _, f := range b.outcomes {
+		if f {
+			failed++
+		}
+	}
+
+	return float64(failed) / float64(len(b.outcomes))
+}
+
+func (m *Manager) breaker(id string) *breaker {
+	if m.breakers == nil {
+		m.breakers = map[string]*breaker{}
+	}
+
+	b, ok := m.breakers[id]
+	if !ok {
+		b = &breaker{}
+		m.breakers[id] = b
+	}
+
+	return b
+}
+
+// Reports whether a worker may receive new workloads, and whether
+// it is on probation with a half-open breaker. A half-open worker
+// is admitted for a single probe until the probe has reported back.
+func (m *Manager) admit(ctx context.Context, id string) (allowed bool, probe bool) {
+	if m.breakerRatio <= 0 {
+		return true, false
+	}
+
+	m.breakersMu.Lock()
+	b := m.breaker(id)
+	from := b.state
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < m.breakerCooldown {
+			m.breakersMu.Unlock()
+			return false, false
+		}
+
+		b.state = BreakerHalfOpen
+		fallthrough
+	case BreakerHalfOpen:
+		allowed = !b.probing
+		probe = allowed
+		b.probing = true
+	default:
+		allowed = true
+	}
+
+	to := b.state
+	m.breakersMu.Unlock()
+
+	if from != to {
+		m.emit(ctx, NewWorkerBreakerEvent(m.id, id, from, to))
+	}
+
+	return allowed, probe
+}
+
+// Reports what admit would answer for a worker, without moving
+// its breaker along or claiming the probe
+func (m *Manager) peek(id string) (allowed bool, probe bool) {
+	if m.breakerRatio <= 0 {
+		return true, false
+	}
+
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+
+	b, ok := m.breakers[id]
+	if !ok {
+		return true, false
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < m.breakerCooldown {
+			return false, false
+		}
+
+		return true, true
+	case BreakerHalfOpen:
+		return !b.probing, true
+	default:
+		return true, false
+	}
+}
+
+// Gives up a probe which was admitted but never used
+func (m *Manager) release(id string) {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+
+	m.breaker(id).probing = false
+}
+
+// Records the outcome of a call towards a worker, opening its breaker
+// when the failure ratio over the window exceeds the threshold
+func (m *Manager) report(ctx context.Context, id string, err error) {
+	if m.breakerRatio <= 0 {
+		return
+	}
+
+	m.breakersMu.Lock()
+	b := m.breaker(id)
+	from := b.state
+
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probing = false
+		b.outcomes = b.outcomes[:0]
+
+		if err != nil {
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+		} else {
+			b.state = BreakerClosed
+		}
+	case BreakerClosed:
+		b.outcomes = append(b.outcomes, err != nil)
+		if len(b.outcomes) > m.breakerWindow {
+			b.outcomes = b.outcomes[len(b.outcomes)-m.breakerWindow:]
+		}
+
+		if len(b.outcomes) >= m.breakerWindow && b.ratio() >= m.breakerRatio {
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+			b.outcomes = b.outcomes[:0]
+		}
+	}
+
+	to := b.state
+	m.breakersMu.Unlock()
+
+	if from != to {
+		m.emit(ctx, NewWorkerBreakerEvent(m.id, id, from, to))
+	}
+}
+
+// Returns the breaker state of a worker
+func (m *Manager) BreakerState(id string) BreakerState {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+
+	if b, ok := m.breakers[id]; ok {
+		return b.state
+	}
+
+	return BreakerClosed
+}
+
+// Returns the breaker state of every worker which has been called
+func (m *Manager) Breakers() map[string]BreakerState {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+
+	states := make(map[string]BreakerState, len(m.breakers))
+	for id, b := range m.breakers {
+		states[id] = b.state
+	}
+
+	return states
+}
@@ -0,0 +1,162 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpens(t *testing.T) {
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		ctx:             context.TODO(),
+		signal:          signal,
+		breakerRatio:    0.5,
+		breakerWindow:   4,
+		breakerCooldown: time.Hour,
+	}
+
+	mgr.report(context.TODO(), "worker0", nil)
+	mgr.report(context.TODO(), "worker0", errors.New("failed"))
+	mgr.report(context.TODO(), "worker0", nil)
+
+	if exp, recv := BreakerClosed, mgr.BreakerState("worker0"); exp != recv {
+		t.Fatalf("expected breaker to be '%s' before the window is full, but got: %s", exp, recv)
+	}
+
+	mgr.report(context.TODO(), "worker0", errors.New("failed"))
+
+	if exp, recv := BreakerOpen, mgr.BreakerState("worker0"); exp != recv {
+		t.Fatalf("expected breaker to be '%s', but got: %s", exp, recv)
+	}
+
+	if allowed, _ := mgr.admit(context.TODO(), "worker0"); allowed {
+		t.Errorf("expected worker with an open breaker to be excluded")
+	}
+
+	if exp, recv := 1, signal.count(EventWorkerBreakerChanged); exp != recv {
+		t.Errorf("expected %d breaker events, but got: %d", exp, recv)
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		ctx:             context.TODO(),
+		signal:          signal,
+		breakerRatio:    0.5,
+		breakerWindow:   1,
+		breakerCooldown: 10 * time.Millisecond,
+	}
+
+	mgr.report(context.TODO(), "worker0", errors.New("failed"))
+	time.Sleep(15 * time.Millisecond)
+
+	allowed, probe := mgr.admit(context.TODO(), "worker0")
+	if !allowed || !probe {
+		t.Fatalf("expected a single probe to be admitted after the cooldown, got allowed=%t probe=%t", allowed, probe)
+	}
+
+	if exp, recv := BreakerHalfOpen, mgr.BreakerState("worker0"); exp != recv {
+		t.Fatalf("expected breaker to be '%s', but got: %s", exp, recv)
+	}
+
+	if allowed, _ := mgr.admit(context.TODO(), "worker0"); allowed {
+		t.Fatalf("expected only one probe to be admitted while half-open")
+	}
+
+	mgr.report(context.TODO(), "worker0", errors.New("failed"))
+
+	if exp, recv := BreakerOpen, mgr.BreakerState("worker0"); exp != recv {
+		t.Fatalf("expected a failed probe to re-open the breaker, but got: %s", recv)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	mgr.admit(context.TODO(), "worker0")
+	mgr.report(context.TODO(), "worker0", nil)
+
+	if exp, recv := BreakerClosed, mgr.BreakerState("worker0"); exp != recv {
+		t.Fatalf("expected a successful probe to close the breaker, but got: %s", recv)
+	}
+
+	if exp, recv := 5, signal.count(EventWorkerBreakerChanged); exp != recv {
+		t.Errorf("expected %d breaker events, but got: %d", exp, recv)
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	mgr := &Manager{
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	for range 10 {
+		mgr.report(context.TODO(), "worker0", errors.New("failed"))
+	}
+
+	if allowed, probe := mgr.admit(context.TODO(), "worker0"); !allowed || probe {
+		t.Errorf("expected disabled breakers to always admit workers")
+	}
+
+	if exp, recv := 0, len(mgr.Breakers()); exp != recv {
+		t.Errorf("expected no breakers to be tracked, but got: %d", recv)
+	}
+}
+
+func TestDistributorSkipsOpenBreaker(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	for i := range 4 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id}
+	}
+
+	mgr := &Manager{
+		state:           state,
+		ctx:             context.TODO(),
+		signal:          &mockSignaller{},
+		breakerRatio:    0.5,
+		breakerWindow:   1,
+		breakerCooldown: time.Hour,
+	}
+	mgr.report(context.TODO(), "worker0", errors.New("failed"))
+	mgr.distributor()
+
+	for wl, w := range state.associations {
+		if w == "worker0" {
+			t.Errorf("expected '%s' not to be placed on a worker with an open breaker", wl)
+		}
+	}
+
+	if exp, recv := 4, len(state.associations); exp != recv {
+		t.Errorf("expected %d workloads to be placed, but got: %d", exp, recv)
+	}
+}
+
+func TestBreakerStateJSON(t *testing.T) {
+	for _, s := range []BreakerState{BreakerClosed, BreakerOpen, BreakerHalfOpen} {
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatalf("unexpected error when serializing breaker state: %v", err)
+		}
+
+		var recv BreakerState
+		if err := json.Unmarshal(b, &recv); err != nil {
+			t.Fatalf("unexpected error when deserializing breaker state: %v", err)
+		}
+
+		if s != recv {
+			t.Errorf("expected to get '%s', but got: %s", s, recv)
+		}
+	}
+}
@@ -0,0 +1,342 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+// How events are handed to a subscriber which isn't keeping up
+type Backpressure uint8
+
+const (
+	// Drop events when the subscriber's buffer is full
+	BackpressureDrop Backpressure = iota
+	// Block the manager until the subscriber has room for the event, up
+	// to the block timeout. A subscriber which times out is lagging, and
+	// its events are dropped until it has room again.
+	BackpressureBlock
+	// Queue events for the subscriber without any bound
+	BackpressureBuffer
+)
+
+const (
+	defaultSubscriberBuffer = 64
+	defaultBlockTimeout     = 5 * time.Second
+)
+
+// Selects events by type, worker and resource. Empty fields match
+// any value, and an empty filter matches every event.
+type EventFilter struct {
+	Types       []EventType
+	WorkerIDs   []string
+	ResourceIDs []string
+}
+
+func (f EventFilter) Match(e Event) bool {
+	if len(f.Types) > 0 && !slices.Contains(f.Types, e.Type) {
+		return false
+	}
+
+	if len(f.WorkerIDs) > 0 && !slices.Contains(f.WorkerIDs, e.WorkerID) {
+		return false
+	}
+
+	if len(f.ResourceIDs) > 0 && !slices.Contains(f.ResourceIDs, e.ResourceID) {
+		return false
+	}
+
+	return true
+}
+
+type SubscribeOption func(*subscriber)
+
+// Set the backpressure policy of a subscriber, default: BackpressureDrop
+func WithBackpressure(p Backpressure) SubscribeOption {
+	return func(s *subscriber) {
+		s.policy = p
+	}
+}
+
+// Set the channel buffer of a subscriber, default: 64
+func WithBufferSize(n int) SubscribeOption {
+	return func(s *subscriber) {
+		s.size = n
+	}
+}
+
+// Set the max time the manager blocks on a subscriber with the block
+// policy, default: 5 seconds
+func WithBlockTimeout(t time.Duration) SubscribeOption {
+	return func(s *subscriber) {
+		s.timeout = t
+	}
+}
+
+type subscriber struct {
+	filter  EventFilter
+	policy  Backpressure
+	size    int
+	timeout time.Duration
+
+	ch      chan Event
+	done    chan struct{}
+	once    sync.Once
+	cancel  func()
+	lagging atomic.Bool // a blocking send timed out
+
+	// guards sends against the channel being closed
+	sendMu sync.RWMutex
+	closed bool
+
+	// queue used by the buffer policy, drained by pump()
+	mu    sync.Mutex
+	queue []Event
+	wake  chan struct{}
+}
+
+func (s *subscriber) deliver(e Event) {
+	s.sendMu.RLock()
+	defer s.sendMu.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	switch s.policy {
+	case BackpressureBlock:
+		if s.lagging.Load() {
+			select {
+			case s.ch <- e:
+				s.lagging.Store(false)
+			default:
+			}
+
+			return
+		}
+
+		t := time.NewTimer(s.timeout)
+		defer t.Stop()
+
+		select {
+		case s.ch <- e:
+		case <-s.done:
+		case <-t.C:
+			s.lagging.Store(true)
+		}
+	case BackpressureBuffer:
+		s.mu.Lock()
+		s.queue = append(s.queue, e)
+		s.mu.Unlock()
+
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	default:
+		select {
+		case s.ch <- e:
+		default:
+		}
+	}
+}
+
+// Moves queued events on to the subscriber's channel
+func (s *subscriber) pump() {
+	defer close(s.ch)
+
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+
+			select {
+			case <-s.wake:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+
+		e := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+
+		select {
+		case s.ch <- e:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Fans events out to any number of subscribers, after handing them
+// to the built-in handlers
+type bus struct {
+	mu       sync.RWMutex
+	subs     map[uint64]*subscriber
+	next     uint64
+	handlers []func(Event)
+}
+
+// Adds a built-in subscriber, receiving every event synchronously
+// before the subscribers' channels
+func (b *bus) handle(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, fn)
+}
+
+func (b *bus) subscribe(filter EventFilter, opts ...SubscribeOption) (*subscriber, func()) {
+	s := &subscriber{
+		filter:  filter,
+		size:    defaultSubscriberBuffer,
+		timeout: defaultBlockTimeout,
+		done:    make(chan struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.ch = make(chan Event, max(s.size, 0))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subs == nil {
+		b.subs = map[uint64]*subscriber{}
+	}
+
+	id := b.next
+	b.next++
+	b.subs[id] = s
+
+	if s.policy == BackpressureBuffer {
+		go s.pump()
+	}
+
+	s.cancel = func() {
+		s.once.Do(func() {
+			// unblocks any publisher waiting on this subscriber
+			close(s.done)
+
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+
+			if s.policy != BackpressureBuffer {
+				s.sendMu.Lock()
+				s.closed = true
+				close(s.ch)
+				s.sendMu.Unlock()
+			}
+		})
+	}
+
+	return s, s.cancel
+}
+
+func (b *bus) publish(e Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	// handlers may subscribe, so they're called without the lock
+	for _, fn := range handlers {
+		fn(e)
+	}
+
+	// delivered without the lock, so a blocking subscriber doesn't hold
+	// up subscribing and cancelling
+	b.mu.RLock()
+	subs := make([]*subscriber, 0, len(b.subs))
+	for _, s := range b.subs {
+		if s.filter.Match(e) {
+			subs = append(subs, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, s := range subs {
+		s.deliver(e)
+	}
+}
+
+// Cancels every subscription
+func (b *bus) close() {
+	b.mu.RLock()
+	cancels := make([]func(), 0, len(b.subs))
+	for _, s := range b.subs {
+		cancels = append(cancels, s.cancel)
+	}
+	b.mu.RUnlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// Subscribe to events matching the filter. The returned channel is
+// closed when the subscription is cancelled or the manager is stopped.
+func (m *Manager) Subscribe(filter EventFilter, opts ...SubscribeOption) (<-chan Event, func()) {
+	s, cancel := m.bus.subscribe(filter, opts...)
+	return s.ch, cancel
+}
+
+// Hands the events of the bus to a signaller, errors are passed to its
+// Error method
+func signalHandler(s Signals) func(Event) {
+	return func(e Event) {
+		if p, ok := e.Payload.(ErrorPayload); ok && e.Type == EventError {
+			err := p.err
+			if err == nil {
+				err = errors.New(p.Error)
+			}
+
+			s.Error(err)
+			return
+		}
+
+		s.Event(e)
+		if s, ok := s.(EnvelopeSignals); ok {
+			s.Envelope(e.Envelope())
+		}
+	}
+}
+
+// Publishes an error as an event, see emit
+func (m *Manager) emitError(ctx context.Context, err error) {
+	m.emit(ctx, NewErrorEvent(m.id, err))
+}
+
+// Publishes an event on the bus. The signaller is the bus's built-in
+// subscriber, receiving every event synchronously before any other
+// subscriber. Events are stamped with an id, the time and the
+// correlation id carried by the context.
+func (m *Manager) emit(ctx context.Context, e Event) {
+	if e.ID == "" {
+		e.ID = event.NewID()
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	if e.CorrelationID == "" {
+		e.CorrelationID = event.CorrelationID(ctx)
+	}
+
+	m.signalOnce.Do(func() {
+		m.bus.handle(signalHandler(m.signal))
+	})
+
+	m.bus.publish(e)
+}
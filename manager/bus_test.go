@@ -0,0 +1,274 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEventFilterMatch(t *testing.T) {
+	e := Event{
+		Type:       EventWorkloadDistributed,
+		WorkerID:   "worker0",
+		ResourceID: "workload0",
+	}
+
+	cases := []struct {
+		Filter EventFilter
+		Exp    bool
+	}{
+		{Filter: EventFilter{}, Exp: true},
+		{Filter: EventFilter{Types: []EventType{EventWorkloadDistributed}}, Exp: true},
+		{Filter: EventFilter{Types: []EventType{EventWorkloadAdded}}, Exp: false},
+		{Filter: EventFilter{WorkerIDs: []string{"worker1", "worker0"}}, Exp: true},
+		{Filter: EventFilter{WorkerIDs: []string{"worker1"}}, Exp: false},
+		{Filter: EventFilter{ResourceIDs: []string{"workload0"}}, Exp: true},
+		{Filter: EventFilter{WorkerIDs: []string{"worker0"}, ResourceIDs: []string{"workload1"}}, Exp: false},
+	}
+
+	for i, c := range cases {
+		if recv := c.Filter.Match(e); c.Exp != recv {
+			t.Errorf("case %d: expected match to be %t, but got: %t", i, c.Exp, recv)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  NewMemoryStore(),
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+
+	workers, cancelWorkers := mgr.Subscribe(EventFilter{Types: []EventType{EventWorkerAdded}})
+	defer cancelWorkers()
+
+	all, cancelAll := mgr.Subscribe(EventFilter{})
+	defer cancelAll()
+
+	if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker0"}); err != nil {
+		t.Fatalf("unexpected error when adding worker: %v", err)
+	}
+
+	if err := mgr.AddWorkload(context.TODO(), &mockWorkload{id: "workload0"}); err != nil {
+		t.Fatalf("unexpected error when adding workload: %v", err)
+	}
+
+	if exp, recv := 1, len(workers); exp != recv {
+		t.Errorf("expected filtered subscriber to have %d event(s), but got: %d", exp, recv)
+	}
+
+	if exp, recv := 2, len(all); exp != recv {
+		t.Errorf("expected unfiltered subscriber to have %d event(s), but got: %d", exp, recv)
+	}
+
+	if e := <-workers; e.WorkerID != "worker0" {
+		t.Errorf("expected event for 'worker0', but got: %+v", e)
+	}
+
+	// the signaller keeps receiving every event
+	if exp, recv := 2, len(signal.events); exp != recv {
+		t.Errorf("expected signaller to have %d event(s), but got: %d", exp, recv)
+	}
+}
+
+func TestSubscribeCancel(t *testing.T) {
+	mgr := &Manager{signal: &mockSignaller{}}
+
+	for _, p := range []Backpressure{BackpressureDrop, BackpressureBlock, BackpressureBuffer} {
+		ch, cancel := mgr.Subscribe(EventFilter{}, WithBackpressure(p))
+		cancel()
+		cancel()
+
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Errorf("expected no events after cancel")
+			}
+		case <-time.After(time.Second):
+			t.Errorf("expected channel to be closed after cancel with policy %d", p)
+		}
+
+		mgr.emit(context.TODO(), Event{Type: EventWorkerAdded})
+	}
+}
+
+func TestBackpressureDrop(t *testing.T) {
+	mgr := &Manager{signal: &mockSignaller{}}
+
+	ch, cancel := mgr.Subscribe(EventFilter{}, WithBufferSize(2))
+	defer cancel()
+
+	for range 5 {
+		mgr.emit(context.TODO(), Event{Type: EventWorkerAdded})
+	}
+
+	if exp, recv := 2, len(ch); exp != recv {
+		t.Errorf("expected %d buffered events, but got: %d", exp, recv)
+	}
+}
+
+func TestBackpressureBlock(t *testing.T) {
+	mgr := &Manager{signal: &mockSignaller{}}
+
+	ch, cancel := mgr.Subscribe(EventFilter{}, WithBackpressure(BackpressureBlock), WithBufferSize(0))
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mgr.emit(context.TODO(), Event{Type: EventWorkerAdded})
+	}()
+
+	select {
+	case <-done:
+		t.Fatalf("expected publisher to block until the event is received")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	<-ch
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected publisher to be released once the event was received")
+	}
+}
+
+func TestBackpressureBlockStalled(t *testing.T) {
+	state := NewMemoryStore()
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+		calls:  newLimiter(0, 0),
+	}
+
+	// never reads its events
+	ch, cancel := mgr.Subscribe(EventFilter{}, WithBackpressure(BackpressureBlock), WithBufferSize(0), WithBlockTimeout(50*time.Millisecond))
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker0"}); err != nil {
+			t.Errorf("unexpected error when adding worker: %v", err)
+		}
+
+		for i := range 10 {
+			if err := mgr.AddWorkload(context.TODO(), &mockWorkload{id: fmt.Sprintf("workload%d", i)}); err != nil {
+				t.Errorf("unexpected error when adding workload: %v", err)
+			}
+		}
+
+		mgr.Distribute()
+	}()
+
+	// a single timeout, then the subscriber's events are dropped
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a stalled subscriber not to hold up the manager")
+	}
+
+	if exp, recv := 10, len(state.associations); exp != recv {
+		t.Errorf("expected %d distributed workload(s), but got: %d", exp, recv)
+	}
+
+	// receiving events again once it reads
+	received := make(chan Event, 1)
+	go func() { received <- <-ch }()
+
+	deadline := time.After(time.Second)
+	for {
+		mgr.emit(context.TODO(), Event{Type: EventWorkerAdded})
+
+		select {
+		case e := <-received:
+			if exp, recv := EventWorkerAdded, e.Type; exp != recv {
+				t.Errorf("expected a '%s' event, but got: '%s'", exp, recv)
+			}
+
+			return
+		case <-deadline:
+			t.Fatalf("expected the subscriber to receive events once it reads")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestBackpressureBuffer(t *testing.T) {
+	mgr := &Manager{signal: &mockSignaller{}}
+
+	ch, cancel := mgr.Subscribe(EventFilter{}, WithBackpressure(BackpressureBuffer), WithBufferSize(0))
+	defer cancel()
+
+	for range 100 {
+		mgr.emit(context.TODO(), Event{Type: EventWorkerAdded})
+	}
+
+	for i := range 100 {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatalf("expected all events to be buffered, but only got %d", i)
+		}
+	}
+}
+
+func TestStopClosesSubscriptions(t *testing.T) {
+	mgr, err := New(context.Background(), WithSignaller(&mockSignaller{}))
+	if err != nil {
+		t.Fatalf("unexpected error when creating manager: %v", err)
+	}
+
+	ch, _ := mgr.Subscribe(EventFilter{})
+	mgr.Stop()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Errorf("expected no events after stop")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("expected channel to be closed when the manager stops")
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		id:     "manager0",
+		state:  NewMemoryStore(),
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+
+	errs, cancel := mgr.Subscribe(EventFilter{Types: []EventType{EventError}})
+	defer cancel()
+
+	// distributing without any workers is an error
+	mgr.Distribute()
+
+	select {
+	case e := <-errs:
+		p, ok := e.Payload.(ErrorPayload)
+		if !ok || p.Error != "no workers available for distribution" {
+			t.Errorf("expected an error event, but got: %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the error to reach the subscriber")
+	}
+
+	// the signaller receives errors through its Error method
+	if exp, recv := 1, len(signal.errors); exp != recv {
+		t.Errorf("expected signaller to have %d error(s), but got: %d", exp, recv)
+	}
+
+	if exp, recv := 0, signal.count(EventError); exp != recv {
+		t.Errorf("expected signaller to have %d error event(s), but got: %d", exp, recv)
+	}
+}
@@ -0,0 +1,171 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Marks a worker as unschedulable, it keeps its workloads but is
+// left out of distribution and rebalancing until uncordoned
+func (m *Manager) Cordon(ctx context.Context, id string) error {
+	w, err := m.GetWorker(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	m.cordonMu.Lock()
+	if m.cordoned == nil {
+		m.cordoned = map[string]bool{}
+	}
+
+	changed := !m.cordoned[id]
+	m.cordoned[id] = true
+	m.cordonMu.Unlock()
+
+	if changed {
+		m.emit(ctx, NewWorkerCordonedEvent(m.id, w))
+	}
+
+	return nil
+}
+
+// Makes a cordoned worker schedulable again
+func (m *Manager) Uncordon(ctx context.Context, id string) error {
+	w, err := m.GetWorker(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	m.cordonMu.Lock()
+	changed := m.cordoned[id]
+	delete(m.cordoned, id)
+	m.cordonMu.Unlock()
+
+	if changed {
+		m.emit(ctx, NewWorkerUncordonedEvent(m.id, w))
+	}
+
+	return nil
+}
+
+// Reports whether a worker is cordoned
+func (m *Manager) Cordoned(id string) bool {
+	m.cordonMu.Lock()
+	defer m.cordonMu.Unlock()
+
+	return m.cordoned[id]
+}
+
+// Returns the ids of every cordoned worker
+func (m *Manager) CordonedWorkers() []string {
+	m.cordonMu.Lock()
+	defer m.cordonMu.Unlock()
+
+	ids := make([]string, 0, len(m.cordoned))
+	for id := range m.cordoned {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+	return ids
+}
+
+// Cordons a worker and moves its workloads off it. Workloads are
+// marked as draining, unloaded, and put back in line for distribution
+// on the remaining workers. Workloads the worker fails to unload are
+// left running on it, and reported in the returned error. The state
+// lock isn't held during the Unload calls, so other calls aren't held
+// up by a slow worker.
+func (m *Manager) Drain(ctx context.Context, id string) error {
+	if err := m.Cordon(ctx, id); err != nil {
+		return err
+	}
+
+	m.mainJobMu.Lock()
+	defer m.mainJobMu.Unlock()
+
+	w, draining, err := m.draining(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, wl := range draining {
+		if err := m.drainWorkload(ctx, w, wl); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to drain %d of %d workload(s) from '%s': %w", len(errs), len(draining), id, err)
+	}
+
+	return nil
+}
+
+// Marks the running workloads of a worker as draining
+func (m *Manager) draining(ctx context.Context, id string) (Worker, []Workload, error) {
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	w, err := m.state.GetWorker(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workloads, err := m.state.GetAssociations(ctx, w)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	draining := make([]Workload, 0, len(workloads))
+	for _, wl := range workloads {
+		if wl.GetStatus() != StatusRunning {
+			continue
+		}
+
+		if err := m.transition(ctx, wl, StatusDraining, "draining "+id); err != nil {
+			m.emitError(ctx, err)
+			continue
+		}
+
+		if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+			m.emitError(ctx, err)
+		}
+
+		draining = append(draining, wl)
+	}
+
+	return w, draining, nil
+}
+
+// Unloads a draining workload, only holding the state lock while
+// the workload's state is updated
+func (m *Manager) drainWorkload(ctx context.Context, w Worker, wl Workload) error {
+	release, err := m.calls.acquire(ctx, w.GetID(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to unload workload '%s' from '%s': %w", wl.GetID(), w.GetID(), err)
+	}
+
+	m.state.Lock()
+	err = m.transition(ctx, wl, StatusUnloading, "drain: unloading from "+w.GetID())
+	if err == nil {
+		err = m.state.UpdateWorkload(ctx, wl)
+	}
+	m.state.Unlock()
+
+	if err != nil {
+		release()
+		return err
+	}
+
+	took, err := m.unloadCall(ctx, w, wl, "drain")
+	release()
+
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	return m.unloaded(ctx, w, wl, "drain", took, err)
+}
@@ -0,0 +1,267 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Worker which fails to unload the given workloads
+type stubbornWorker struct {
+	mockWorker
+	keep map[string]bool
+}
+
+func (w *stubbornWorker) Unload(ctx context.Context, req UnloadRequest) error {
+	if w.keep[req.Workload.GetID()] {
+		return errors.New("refused")
+	}
+
+	return nil
+}
+
+func TestCordon(t *testing.T) {
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state: &MemoryStore{
+			workers: map[string]Worker{
+				"worker0": &mockWorker{id: "worker0"},
+			},
+		},
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+
+	if err := mgr.Cordon(context.TODO(), "worker0"); err != nil {
+		t.Fatalf("unexpected error when cordoning worker: %v", err)
+	}
+
+	// cordoning twice is a no-op
+	if err := mgr.Cordon(context.TODO(), "worker0"); err != nil {
+		t.Fatalf("unexpected error when cordoning worker: %v", err)
+	}
+
+	if !mgr.Cordoned("worker0") {
+		t.Errorf("expected worker to be cordoned")
+	}
+
+	if exp, recv := 1, signal.count(EventWorkerCordoned); exp != recv {
+		t.Errorf("expected %d cordoned event(s), but got: %d", exp, recv)
+	}
+
+	if err := mgr.Uncordon(context.TODO(), "worker0"); err != nil {
+		t.Fatalf("unexpected error when uncordoning worker: %v", err)
+	}
+
+	if mgr.Cordoned("worker0") || len(mgr.CordonedWorkers()) != 0 {
+		t.Errorf("expected worker not to be cordoned")
+	}
+
+	if err := mgr.Cordon(context.TODO(), "worker1"); !errors.Is(err, ErrWorkerNotFound) {
+		t.Errorf("expected '%v', but got: %v", ErrWorkerNotFound, err)
+	}
+
+	// a worker added again under the same id isn't cordoned
+	if err := mgr.Cordon(context.TODO(), "worker0"); err != nil {
+		t.Fatalf("unexpected error when cordoning worker: %v", err)
+	}
+
+	if err := mgr.DeleteWorker(context.TODO(), &mockWorker{id: "worker0"}); err != nil {
+		t.Fatalf("unexpected error when deleting worker: %v", err)
+	}
+
+	if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker0"}); err != nil {
+		t.Fatalf("unexpected error when adding worker: %v", err)
+	}
+
+	if mgr.Cordoned("worker0") {
+		t.Errorf("expected a re-added worker not to be cordoned")
+	}
+}
+
+func TestDistributorCordoned(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	for i := range 4 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id}
+	}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	if err := mgr.Cordon(context.TODO(), "worker0"); err != nil {
+		t.Fatalf("unexpected error when cordoning worker: %v", err)
+	}
+
+	mgr.Distribute()
+
+	for wl, w := range state.associations {
+		if w == "worker0" {
+			t.Errorf("expected '%s' not to be placed on a cordoned worker", wl)
+		}
+	}
+
+	if exp, recv := 4, len(state.associations); exp != recv {
+		t.Errorf("expected %d workloads to be placed, but got: %d", exp, recv)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	w0 := &stubbornWorker{mockWorker: mockWorker{id: "worker0"}, keep: map[string]bool{"workload3": true}}
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": w0,
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	for i := range 4 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id, status: StatusRunning}
+		state.associations[id] = "worker0"
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+
+	err := mgr.Drain(context.TODO(), "worker0")
+	if err == nil {
+		t.Fatalf("expected an error for the workload which couldn't be unloaded")
+	}
+
+	if !mgr.Cordoned("worker0") {
+		t.Errorf("expected worker to be cordoned")
+	}
+
+	if exp, recv := StatusRunning, state.workloads["workload3"].GetStatus(); exp != recv {
+		t.Errorf("expected the stubborn workload to be '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "worker0", state.associations["workload3"]; exp != recv {
+		t.Errorf("expected the stubborn workload to stay on '%s', but got: '%s'", exp, recv)
+	}
+
+	for _, id := range []string{"workload0", "workload1", "workload2"} {
+		if exp, recv := StatusInit, state.workloads[id].GetStatus(); exp != recv {
+			t.Errorf("expected '%s' to be '%s', but got: '%s'", id, exp, recv)
+		}
+
+		if _, ok := state.associations[id]; ok {
+			t.Errorf("expected '%s' to be disassociated", id)
+		}
+
+		h, err := mgr.WorkloadHistory(context.TODO(), id)
+		if err != nil {
+			t.Fatalf("unexpected error when getting history: %v", err)
+		}
+
+		if exp, recv := 3, len(h); exp != recv || h[0].To != StatusDraining || h[1].To != StatusUnloading {
+			t.Errorf("expected '%s' to move through draining and unloading, but got: %+v", id, h)
+		}
+	}
+
+	if exp, recv := 3, signal.count(EventWorkloadUnloaded); exp != recv {
+		t.Errorf("expected %d unloaded events, but got: %d", exp, recv)
+	}
+
+	// the drained workloads end up on the remaining worker
+	mgr.Distribute()
+
+	for _, id := range []string{"workload0", "workload1", "workload2"} {
+		if exp, recv := "worker1", state.associations[id]; exp != recv {
+			t.Errorf("expected '%s' to be placed on '%s', but got: '%s'", id, exp, recv)
+		}
+	}
+}
+
+// Store with a state lock which actually locks
+type lockingStore struct {
+	*MemoryStore
+	mu sync.Mutex
+}
+
+func (s *lockingStore) Lock() {
+	s.mu.Lock()
+}
+
+func (s *lockingStore) Unlock() {
+	s.mu.Unlock()
+}
+
+// Worker which holds on to Unload calls until released
+type slowWorker struct {
+	mockWorker
+	unloading chan struct{}
+	release   chan struct{}
+}
+
+func (w *slowWorker) Unload(ctx context.Context, req UnloadRequest) error {
+	w.unloading <- struct{}{}
+	<-w.release
+
+	return nil
+}
+
+func TestDrainUnlocked(t *testing.T) {
+	w0 := &slowWorker{mockWorker: mockWorker{id: "worker0"}, unloading: make(chan struct{}), release: make(chan struct{})}
+	state := &lockingStore{MemoryStore: NewMemoryStore()}
+	state.workers["worker0"] = w0
+	state.workloads["workload0"] = &mockWorkload{id: "workload0", status: StatusRunning}
+	state.associations["workload0"] = "worker0"
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- mgr.Drain(context.TODO(), "worker0")
+	}()
+
+	<-w0.unloading
+
+	// the state is free while the worker is unloading
+	done := make(chan struct{})
+	go func() {
+		_, _ = mgr.Workers(context.TODO())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("expected the state lock to be released during the Unload call")
+	}
+
+	close(w0.release)
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected error when draining: %v", err)
+	}
+
+	if _, ok := state.associations["workload0"]; ok {
+		t.Errorf("expected the workload to be unloaded from the drained worker")
+	}
+}
@@ -2,16 +2,21 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
 )
 
-// Assign a workload to a worker, commonly used to backfill
-// when a manager is started post worker startup. Bypasses
-// distribution steps for the workload.
+// Assign a workload to a worker, bypassing distribution steps for
+// the workload. The manager backfills workers implementing
+// WorkloadLister on startup, and the workloads reported when a
+// worker registers, by itself.
 func (m *Manager) Assign(w Worker, wl Workload) {
 	ctx, cancel := context.WithTimeout(m.ctx, 5*time.Second)
 	defer cancel()
@@ -19,62 +24,119 @@ func (m *Manager) Assign(w Worker, wl Workload) {
 	m.state.Lock()
 	defer m.state.Unlock()
 
-	wl.SetStatus(StatusRunning)
+	m.assign(ctx, w, wl)
+}
+
+func (m *Manager) assign(ctx context.Context, w Worker, wl Workload) {
+	if err := m.transition(ctx, wl, StatusRunning, "assigned to "+w.GetID()); err != nil {
+		m.emitError(ctx, err)
+		return
+	}
 
-	if err := m.state.AddWorkload(ctx, wl); err != nil {
-		m.signal.Error(err)
+	if _, err := m.state.GetWorkload(ctx, wl.GetID()); errors.Is(err, ErrWorkloadNotFound) {
+		if err := m.state.AddWorkload(ctx, wl); err != nil {
+			m.emitError(ctx, err)
+		}
+	} else if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+		m.emitError(ctx, err)
 	}
 
 	if err := m.state.Associate(ctx, wl, w); err != nil {
-		m.signal.Error(err)
+		m.emitError(ctx, err)
 	}
 }
 
+// Runs a distribution pass right away, waiting for any running
+// distribution or rebalance pass to finish first
+func (m *Manager) Distribute() {
+	m.distributor()
+}
+
+// Runs a rebalance pass right away, waiting for any running
+// distribution or rebalance pass to finish first
+func (m *Manager) Rebalance() {
+	m.rebalance()
+}
+
 func (m *Manager) cleanup() {
-	ctx, cancel := context.WithTimeout(m.ctx, 5*time.Second)
+	ctx, cancel := context.WithTimeout(event.EnsureCorrelationID(m.ctx), 5*time.Second)
 	defer cancel()
 
+	ctx, span := m.startJob(ctx, "cleanup")
+	defer span.End()
+
 	m.state.Lock()
 	defer m.state.Unlock()
 
 	workloads, err := m.state.GetAllWorkloads(ctx)
 	if err != nil {
-		m.signal.Error(err)
+		m.emitError(ctx, err)
 		return
 	}
 
 	for _, wl := range workloads {
-		if time.Since(wl.LastStatusChange()) < m.cleanupMaxTime {
-			continue
-		}
-
 		switch wl.GetStatus() {
 		case StatusDistributing:
-			wl.SetStatus(StatusErr)
+			if time.Since(wl.LastStatusChange()) < m.cleanupMaxTime {
+				continue
+			}
+
+			age := time.Since(wl.LastStatusChange())
+			if err := m.transition(ctx, wl, StatusErr, "distribution timed out"); err != nil {
+				m.emitError(ctx, err)
+				continue
+			}
+
+			m.emit(ctx, NewWorkloadCleanupResetEvent(m.id, wl, StatusDistributing, StatusErr, age))
+
+			// a stuck load counts as a failed placement attempt
+			if m.fail(wl.GetID()) {
+				m.operationDone("load", wl.GetID())
+
+				reason := fmt.Sprintf("quarantined after %d failed attempts", m.Attempts(wl.GetID()))
+				if err := m.transition(ctx, wl, StatusQuarantined, reason); err != nil {
+					m.emitError(ctx, err)
+				}
+			}
+
 			if err := m.state.UpdateWorkload(ctx, wl); err != nil {
-				m.signal.Error(err)
+				m.emitError(ctx, err)
 				continue
 			}
 
 			w, err := m.state.GetAssociation(ctx, wl)
+			if errors.Is(err, ErrMissingAssociation) {
+				continue
+			}
+
 			if err != nil {
-				m.signal.Error(err)
+				m.emitError(ctx, err)
 				continue
 			}
 
 			if err := m.state.Disassociate(ctx, wl, w); err != nil {
-				m.signal.Error(err)
+				m.emitError(ctx, err)
 				continue
 			}
 		case StatusErr:
-			wl.SetStatus(StatusInit)
+			if !m.retryDue(wl) {
+				continue
+			}
+
+			age := time.Since(wl.LastStatusChange())
+			if err := m.transition(ctx, wl, StatusInit, "reset by cleanup"); err != nil {
+				m.emitError(ctx, err)
+				continue
+			}
+
+			m.emit(ctx, NewWorkloadCleanupResetEvent(m.id, wl, StatusErr, StatusInit, age))
+
 			if err := m.state.UpdateWorkload(ctx, wl); err != nil {
-				m.signal.Error(err)
+				m.emitError(ctx, err)
 			}
 		default:
 			continue
 		}
-
 	}
 }
 
@@ -82,9 +144,23 @@ func (m *Manager) distributor() {
 	m.mainJobMu.Lock()
 	defer m.mainJobMu.Unlock()
 
-	ctx, cancel := context.WithCancel(m.ctx)
+	ctx, cancel := context.WithCancel(event.EnsureCorrelationID(m.ctx))
 	defer cancel()
 
+	ctx, span := m.startJob(ctx, "distribute")
+	defer span.End()
+
+	m.waitBackfill(ctx)
+
+	// The deadline stops new calls from being made, calls in flight are
+	// left to finish within their own timeout so the state is kept in sync
+	pass, cancelPass := ctx, context.CancelFunc(func() {})
+	if m.distributionDeadline > 0 {
+		pass, cancelPass = context.WithTimeout(ctx, m.distributionDeadline)
+	}
+	defer cancelPass()
+
+	calls := &callStats{}
 	stats := map[string]any{
 		"workers":   0,
 		"workloads": 0,
@@ -95,18 +171,22 @@ func (m *Manager) distributor() {
 
 		"loadBefore": map[string]int{},
 		"loadAfter":  map[string]int{},
+
+		"throttled": 0,
+		"timedOut":  0,
+		"skipped":   0,
 	}
 
 	workers, err := m.state.GetAllWorkers(ctx)
 	if err != nil {
-		m.signal.Error(fmt.Errorf("failed to distribute: failed to get workers: %w", err))
+		m.emitError(ctx, fmt.Errorf("failed to distribute: failed to get workers: %w", err))
 		return
 	}
 
 	stats["workers"] = len(workers)
 
 	if len(workers) == 0 {
-		m.signal.Error(fmt.Errorf("no workers available for distribution"))
+		m.emitError(ctx, fmt.Errorf("no workers available for distribution"))
 		return
 	}
 
@@ -117,7 +197,7 @@ func (m *Manager) distributor() {
 
 		workloads, err := m.state.GetAssociations(ctx, w)
 		if err != nil {
-			m.signal.Error(fmt.Errorf("failed to distribute: failed to get worker associations: %w", err))
+			m.emitError(ctx, fmt.Errorf("failed to distribute: failed to get worker associations: %w", err))
 			return
 		}
 
@@ -129,17 +209,24 @@ func (m *Manager) distributor() {
 
 	workloads, err := m.state.GetAllWorkloads(ctx)
 	if err != nil {
-		m.signal.Error(fmt.Errorf("failed to distribute: failed to get workloads: %w", err))
+		m.emitError(ctx, fmt.Errorf("failed to distribute: failed to get workloads: %w", err))
 		return
 	}
 
 	stats["workloads"] = len(workloads)
 
 	if len(workloads) == 0 {
-		m.signal.Error(fmt.Errorf("no workloads to distribute"))
+		m.emitError(ctx, fmt.Errorf("no workloads to distribute"))
 		return
 	}
 
+	placed := map[string]bool{}
+	for _, wls := range current {
+		for _, wl := range wls {
+			placed[wl] = true
+		}
+	}
+
 	var wlm = make(map[string]Workload, len(workloads))
 	wanted := make([]string, 0, len(workloads))
 	for _, wl := range workloads {
@@ -147,6 +234,22 @@ func (m *Manager) distributor() {
 		wanted = append(wanted, wl.GetID())
 	}
 
+	distribute := m.pending(ctx, workloads, placed, true)
+
+	start := time.Now()
+	m.emit(ctx, NewDistributionStartedEvent(m.id, len(workers), len(workloads)))
+
+	var distributed, failed, unloaded atomic.Int64
+	defer func() {
+		m.emit(ctx, NewDistributionFinishedEvent(m.id, DistributionFinishedPayload{
+			Distributed: int(distributed.Load()),
+			Failed:      int(failed.Load()),
+			Unloaded:    int(unloaded.Load()),
+			Skipped:     int(calls.skipped.Load()),
+			Duration:    time.Since(start),
+		}))
+	}()
+
 	stats["wanted"] = wanted
 
 	deletes := map[string][]string{}
@@ -168,40 +271,47 @@ func (m *Manager) distributor() {
 
 	stats["deletes"] = deletes
 
-	m.signal.Event(Event{
-		Type:      EventDistributionStats,
-		ManagerID: m.id,
-		Extra:     stats,
-	})
+	defer func() {
+		stats["throttled"] = calls.throttled.Load()
+		stats["timedOut"] = calls.timedOut.Load()
+		stats["skipped"] = calls.skipped.Load()
 
-	var wg sync.WaitGroup
-	for w, dels := range deletes {
-		for _, del := range dels {
-			wg.Go(func() {
-				if err := ctx.Err(); err != nil {
-					m.signal.Error(fmt.Errorf("failed to delete workload '%s' from '%s': %w", del, w, err))
-					return
-				}
+		m.emit(ctx, Event{
+			Type:      EventDistributionStats,
+			ManagerID: m.id,
+			Extra:     stats,
+		})
+	}()
 
-				if err := wm[w].Unload(&workload{id: del}); err != nil {
-					m.signal.Error(fmt.Errorf("failed to unload unwanted workload '%s' from '%s': %w", del, w, err))
-				}
-			})
+	m.perWorker(deletes, func(w, del string) {
+		release, err := m.calls.acquire(pass, w, calls)
+		if err != nil {
+			m.emitError(ctx, fmt.Errorf("failed to delete workload '%s' from '%s': %w", del, w, err))
+			return
 		}
-	}
-	wg.Wait()
+		defer release()
 
-	distribute := []string{}
-outer:
-	for _, wl := range wanted {
-		for _, wls := range current {
-			if slices.Contains(wls, wl) {
-				continue outer
-			}
+		wl := &workload{id: del}
+
+		callCtx, span := m.startCall(ctx, "Unload", w, del)
+		start := time.Now()
+		err = m.call(callCtx, calls, m.unloadTimeout, func(ctx context.Context) error {
+			return wm[w].Unload(ctx, m.unloadRequest(wl, "unwanted"))
+		})
+		took := time.Since(start)
+		endSpan(span, err)
+		m.report(ctx, w, err)
+
+		if err != nil {
+			m.emitError(ctx, fmt.Errorf("failed to unload unwanted workload '%s' from '%s': %w", del, w, err))
+			m.emit(ctx, NewWorkloadUnloadedErrorEvent(m.id, w, wl, "unwanted", err, took))
+			return
 		}
 
-		distribute = append(distribute, wl)
-	}
+		m.operationDone("unload", del)
+		m.emit(ctx, NewWorkloadUnloadedEvent(m.id, w, wl, "unwanted", took))
+		unloaded.Add(1)
+	})
 
 	load := make(map[string]int, len(current))
 	for w, wls := range current {
@@ -210,12 +320,118 @@ outer:
 
 	stats["loadBefore"] = load
 
-	distribution := map[string]string{}
-	for _, wl := range distribute {
+	// cordoned workers, full workers and workers with an open breaker
+	// are left out of placement, and workers with a half-open breaker
+	// only receive a single probe
+	caps := m.capacities()
+	candidates := make(map[string]int, len(load))
+	probes := map[string]bool{}
+	if len(distribute) > 0 {
+		for w, l := range load {
+			if m.Cordoned(w) || full(caps, w, l) {
+				continue
+			}
+
+			allowed, probe := m.admit(ctx, w)
+			if !allowed {
+				continue
+			}
+
+			candidates[w] = l
+			probes[w] = probe
+		}
+	}
+
+	distribution, probing, unplaced := place(distribute, candidates, probes, caps)
+	if len(unplaced) > 0 {
+		m.emitError(ctx, fmt.Errorf("no workers available for placement of %d workload(s)", len(unplaced)))
+	}
+
+	for _, w := range distribution {
+		load[w] += 1
+	}
+
+	for w, probe := range probes {
+		if probe && !probing[w] {
+			m.release(w)
+		}
+	}
+
+	stats["distributes"] = distribution
+	stats["loadAfter"] = load
+
+	loads := map[string][]string{}
+	for wl, w := range distribution {
+		loads[w] = append(loads[w], wl)
+	}
+
+	m.perWorker(loads, func(w, wl string) {
+		release, err := m.calls.acquire(pass, w, calls)
+		if err != nil {
+			m.emitError(ctx, fmt.Errorf("failed to distribute workload '%s' to '%s': %w", wl, w, err))
+			if probing[w] {
+				m.release(w)
+			}
+			return
+		}
+		defer release()
+
+		if err := m.load(ctx, calls, wm[w], wlm[wl]); err != nil {
+			failed.Add(1)
+			return
+		}
+
+		distributed.Add(1)
+	})
+}
+
+// Calls fn for every workload queued per worker, from as many goroutines
+// per worker as calls may be made to it at once, rather than one per
+// workload. Workers are served side by side, so a saturated worker
+// doesn't hold back calls to the others. Returns when all calls are done.
+func (m *Manager) perWorker(queues map[string][]string, fn func(w, wl string)) {
+	width := m.calls.width()
+
+	var wg sync.WaitGroup
+	for w, wls := range queues {
+		n := len(wls)
+		if width > 0 {
+			n = min(n, width)
+		}
+
+		var next atomic.Int64
+		for range n {
+			wg.Go(func() {
+				for i := next.Add(1) - 1; i < int64(len(wls)); i = next.Add(1) - 1 {
+					fn(w, wls[i])
+				}
+			})
+		}
+	}
+	wg.Wait()
+}
+
+// Reports whether a worker has reached its capacity
+func full(caps map[string]int, w string, load int) bool {
+	return caps[w] > 0 && load >= caps[w]
+}
+
+// Places workloads on the least loaded candidates, keyed by workload id.
+// Candidates on probation leave the pool after a single workload, and
+// are returned as probing, while candidates leave it when they reach
+// their capacity. Workloads left once the pool is empty are returned
+// as unplaced. The candidate load is updated in place.
+func place(workloads []string, candidates map[string]int, probes map[string]bool, caps map[string]int) (map[string]string, map[string]bool, []string) {
+	distribution, probing := map[string]string{}, map[string]bool{}
+	for i, wl := range workloads {
+		if len(candidates) == 0 {
+			return distribution, probing, workloads[i:]
+		}
+
 		var wid = ""
 		var min = 999_999_999
 
-		for w, l := range load {
+		for w, l := range candidates {
 			if l < min || wid == "" {
 				wid = w
 				min = l
@@ -223,79 +439,264 @@ outer:
 		}
 
 		distribution[wl] = wid
-		load[wid] += 1
+		candidates[wid] += 1
+
+		if probes[wid] {
+			delete(candidates, wid)
+			probing[wid] = true
+		}
+
+		if full(caps, wid, candidates[wid]) {
+			delete(candidates, wid)
+		}
 	}
 
-	stats["distributes"] = distribution
-	stats["loadAfter"] = load
+	return distribution, probing, nil
+}
 
-	for wl, w := range distribution {
-		wg.Go(func() {
-			if err := ctx.Err(); err != nil {
-				m.signal.Error(fmt.Errorf("failed to distribute workload '%s' to '%s': %w", wl, w, err))
-				return
-			}
+// Loads a workload on to a worker. The workload is persisted as
+// distributing and associated with the worker before the call, so
+// a hung or interrupted load is picked up by the cleanup job.
+func (m *Manager) load(ctx context.Context, calls *callStats, w Worker, wl Workload) error {
+	if err := m.transition(ctx, wl, StatusDistributing, "distributing to "+w.GetID()); err != nil {
+		m.emitError(ctx, err)
+		return err
+	}
 
-			if err := wm[w].Load(wlm[wl]); err != nil {
-				m.signal.Error(fmt.Errorf("failed to load workload '%s' on to worker '%s': %w", wl, w, err))
-				return
-			}
+	if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+		m.emitError(ctx, fmt.Errorf("failed to update workload state on '%s' before distribution: %w", wl.GetID(), err))
+	}
+
+	if err := m.state.Associate(ctx, wl, w); err != nil {
+		m.emitError(ctx, fmt.Errorf("failed to associate workload '%s' with worker '%s': %w", wl.GetID(), w.GetID(), err))
+	}
+
+	callCtx, span := m.startCall(ctx, "Load", w.GetID(), wl.GetID())
+	start := time.Now()
+	err := m.call(callCtx, calls, m.loadTimeout, func(ctx context.Context) error {
+		return w.Load(ctx, m.loadRequest(wl, "distribution"))
+	})
+	took := time.Since(start)
+	endSpan(span, err)
+	m.report(ctx, w.GetID(), err)
+
+	if err != nil {
+		m.emitError(ctx, fmt.Errorf("failed to load workload '%s' on to worker '%s': %w", wl.GetID(), w.GetID(), err))
+		m.emit(ctx, NewWorkloadDistributedErrorEvent(m.id, w.GetID(), wl, err, took))
+
+		if err := m.transition(ctx, wl, StatusErr, "load failed: "+err.Error()); err != nil {
+			m.emitError(ctx, err)
+		}
 
-			wlm[wl].SetStatus(StatusRunning)
-			if err := m.state.UpdateWorkload(ctx, wlm[wl]); err != nil {
-				m.signal.Error(fmt.Errorf("failed to update workload state on '%s' after distribution: %w", wl, err))
+		if m.fail(wl.GetID()) {
+			// a requeue is a new placement decision
+			m.operationDone("load", wl.GetID())
+
+			reason := fmt.Sprintf("quarantined after %d failed attempts", m.Attempts(wl.GetID()))
+			if err := m.transition(ctx, wl, StatusQuarantined, reason); err != nil {
+				m.emitError(ctx, err)
 			}
+		}
+
+		if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+			m.emitError(ctx, fmt.Errorf("failed to update workload state on '%s' after distribution: %w", wl.GetID(), err))
+		}
+
+		if err := m.state.Disassociate(ctx, wl, w); err != nil {
+			m.emitError(ctx, fmt.Errorf("failed to disassociate workload '%s' from worker '%s': %w", wl.GetID(), w.GetID(), err))
+		}
+
+		return err
+	}
+
+	if err := m.transition(ctx, wl, StatusRunning, "distributed to "+w.GetID()); err != nil {
+		m.emitError(ctx, err)
+	}
+
+	m.succeed(wl.GetID())
+	m.operationDone("load", wl.GetID())
+
+	if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+		m.emitError(ctx, fmt.Errorf("failed to update workload state on '%s' after distribution: %w", wl.GetID(), err))
+	}
+
+	m.emit(ctx, NewWorkloadDistributedEvent(m.id, w.GetID(), wl, took))
+	return nil
+}
 
-			if err := m.state.Associate(ctx, wlm[wl], wm[w]); err != nil {
-				m.signal.Error(fmt.Errorf("failed to associate workload '%s' with worker '%s': %w", wl, w, err))
+// Returns the ids of the workloads a distribution pass places: those
+// waiting for placement, failed ones whose backoff has passed, and
+// running or down ones which aren't placed on any worker. With requeue
+// set the latter are put back in line, otherwise no state is changed,
+// like for a plan.
+func (m *Manager) pending(ctx context.Context, workloads []Workload, placed map[string]bool, requeue bool) []string {
+	ids := []string{}
+	for _, wl := range workloads {
+		id, s := wl.GetID(), wl.GetStatus()
+
+		switch {
+		case requeue:
+			m.retry(ctx, wl)
+			if !placed[id] {
+				m.requeueOrphan(ctx, wl)
 			}
-		})
+
+			s = wl.GetStatus()
+		case m.retryable(wl), !placed[id] && orphaned(wl):
+			s = StatusInit
+		}
+
+		if s == StatusInit && !placed[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+// Reports whether a workload failed a placement attempt and
+// its backoff has passed
+func (m *Manager) retryable(wl Workload) bool {
+	return wl.GetStatus() == StatusErr && m.Attempts(wl.GetID()) > 0 && m.retryDue(wl)
+}
+
+// Reports whether a workload not placed on any worker is
+// requeued, i.e. it's running or down
+func orphaned(wl Workload) bool {
+	s := wl.GetStatus()
+	return s == StatusRunning || s == StatusDown
+}
+
+// Puts a workload which failed a placement attempt back in line
+// for distribution once its backoff has passed
+func (m *Manager) retry(ctx context.Context, wl Workload) {
+	if !m.retryable(wl) {
+		return
+	}
+
+	if err := m.transition(ctx, wl, StatusInit, "retrying after backoff"); err != nil {
+		m.emitError(ctx, err)
+		return
+	}
+
+	if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+		m.emitError(ctx, fmt.Errorf("failed to update workload state on '%s' before retry: %w", wl.GetID(), err))
+	}
+}
+
+// Puts a workload which isn't placed on any worker back in line for
+// distribution, e.g. once its worker was deleted or its association
+// was lost. Workloads in other statuses are left to the cleanup job
+// and the retry logic.
+func (m *Manager) requeueOrphan(ctx context.Context, wl Workload) {
+	if !orphaned(wl) {
+		return
+	}
+
+	if wl.GetStatus() == StatusRunning {
+		if err := m.transition(ctx, wl, StatusDown, "not placed on any worker"); err != nil {
+			m.emitError(ctx, err)
+			return
+		}
+	}
+
+	if err := m.transition(ctx, wl, StatusInit, "requeued: not placed on any worker"); err != nil {
+		m.emitError(ctx, err)
+		return
+	}
+
+	if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+		m.emitError(ctx, fmt.Errorf("failed to update workload state on '%s' before requeue: %w", wl.GetID(), err))
 	}
-	wg.Wait()
 }
 
 func (m *Manager) rebalance() {
 	m.mainJobMu.Lock()
 	defer m.mainJobMu.Unlock()
 
-	ctx, cancel := context.WithCancel(m.ctx)
+	ctx, cancel := context.WithCancel(event.EnsureCorrelationID(m.ctx))
 	defer cancel()
 
+	ctx, span := m.startJob(ctx, "rebalance")
+	defer span.End()
+
 	m.state.Lock()
 	defer m.state.Unlock()
 
+	started, total := time.Now(), 0
+	defer func() {
+		m.emit(ctx, NewRebalanceFinishedEvent(m.id, total, time.Since(started)))
+	}()
+
 	for {
 		workers, err := m.state.GetAllWorkers(ctx)
 		if err != nil {
-			m.signal.Error(err)
+			m.emitError(ctx, err)
 			break // no continue (possible infinite loop)
 		}
 
-		counters := map[string]int{}
+		caps := m.capacities()
+		counters, destinations := map[string]int{}, map[string]int{}
+		room, unbounded := 0, false
 		for _, w := range workers {
+			// cordoned workers don't take part, as they would
+			// never receive the workloads moved on their behalf
+			if m.Cordoned(w.GetID()) {
+				continue
+			}
+
 			assocs, err := m.state.GetAssociations(ctx, w)
 			if err != nil {
-				m.signal.Error(err)
+				m.emitError(ctx, err)
 				continue
 			}
 
 			counters[w.GetID()] = len(assocs)
+
+			// full workers shed load, but can't receive any
+			if full(caps, w.GetID(), len(assocs)) {
+				continue
+			}
+
+			destinations[w.GetID()] = len(assocs)
+			if c, ok := caps[w.GetID()]; ok {
+				room += c - len(assocs)
+			} else {
+				unbounded = true
+			}
 		}
 
-		_, hi, delta := m.sort(counters)
+		_, hi, _ := m.sort(counters)
+		lo, _, _ := m.sort(destinations)
+		if lo == "" || lo == hi {
+			break
+		}
+
+		delta := counters[hi] - counters[lo]
 		if delta <= m.maxDelta {
 			break
 		}
 
+		// workloads are only moved as long as they can be placed again,
+		// counting the ones moved earlier in the pass
+		moves := delta - m.maxDelta + 1
+		if !unbounded {
+			moves = min(moves, room-total)
+		}
+
 		w, err := m.state.GetWorker(ctx, hi)
 		if err != nil {
-			m.signal.Error(err)
+			m.emitError(ctx, err)
 			continue
 		}
 
+		m.emit(ctx, NewWorkerOverloadedEvent(m.id, hi, counters[hi], delta, m.maxDelta))
+
 		workloads, err := m.state.GetAssociations(ctx, w)
 		if err != nil {
-			m.signal.Error(err)
+			m.emitError(ctx, err)
 			continue
 		}
 
@@ -303,25 +704,98 @@ func (m *Manager) rebalance() {
 			return workloads[i].LastStatusChange().Before(workloads[i].LastStatusChange())
 		})
 
-		for i := 0; i <= (delta - m.maxDelta); i++ {
-			wl := workloads[i]
+		moved := 0
+		for i := 0; i < moves && i < len(workloads); i++ {
+			if err := m.unload(ctx, w, workloads[i], "rebalance"); err != nil {
+				m.emitError(ctx, err)
 
-			if err := w.Unload(wl); err != nil {
-				continue
-			}
+				if ctx.Err() != nil {
+					break
+				}
 
-			if err := m.state.Disassociate(ctx, wl, w); err != nil {
-				m.signal.Error(err)
 				continue
 			}
 
-			wl.SetStatus(StatusInit)
-			if err := m.state.UpdateWorkload(ctx, wl); err != nil {
-				m.signal.Error(err)
-				continue
-			}
+			moved++
 		}
+
+		total += moved
+
+		// nothing could be moved, bail out rather than spinning
+		if moved == 0 {
+			break
+		}
+	}
+}
+
+// Unloads a workload from a worker and puts it back in line for
+// distribution. If the worker fails to unload it, the workload is
+// left running on the worker.
+func (m *Manager) unload(ctx context.Context, w Worker, wl Workload, reason string) error {
+	release, err := m.calls.acquire(ctx, w.GetID(), nil)
+	if err != nil {
+		return fmt.Errorf("%w '%s' from '%s': %w", ErrUnloadFailed, wl.GetID(), w.GetID(), err)
+	}
+
+	if err := m.transition(ctx, wl, StatusUnloading, reason+": unloading from "+w.GetID()); err != nil {
+		release()
+		return err
+	}
+
+	took, err := m.unloadCall(ctx, w, wl, reason)
+	release()
+
+	return m.unloaded(ctx, w, wl, reason, took, err)
+}
+
+// Makes the Unload call for a workload which is unloading, it doesn't
+// touch the state so it can be made without holding the state lock
+func (m *Manager) unloadCall(ctx context.Context, w Worker, wl Workload, reason string) (time.Duration, error) {
+	callCtx, span := m.startCall(ctx, "Unload", w.GetID(), wl.GetID())
+	start := time.Now()
+	err := m.call(callCtx, nil, m.unloadTimeout, func(ctx context.Context) error {
+		return w.Unload(ctx, m.unloadRequest(wl, reason))
+	})
+	took := time.Since(start)
+	endSpan(span, err)
+	m.report(ctx, w.GetID(), err)
+
+	return took, err
+}
+
+// Records the outcome of an Unload call, putting the workload back in
+// line for distribution or back to running on the worker
+func (m *Manager) unloaded(ctx context.Context, w Worker, wl Workload, reason string, took time.Duration, err error) error {
+	if err != nil {
+		m.emit(ctx, NewWorkloadUnloadedErrorEvent(m.id, w.GetID(), wl, reason, err, took))
+
+		if err := m.transition(ctx, wl, StatusRunning, reason+": unload failed"); err != nil {
+			m.emitError(ctx, err)
+		}
+
+		if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+			m.emitError(ctx, err)
+		}
+
+		return fmt.Errorf("%w '%s' from '%s': %w", ErrUnloadFailed, wl.GetID(), w.GetID(), err)
 	}
+
+	m.operationDone("unload", wl.GetID())
+
+	if err := m.state.Disassociate(ctx, wl, w); err != nil {
+		return err
+	}
+
+	if err := m.transition(ctx, wl, StatusInit, reason+": unloaded from "+w.GetID()); err != nil {
+		m.emitError(ctx, err)
+	}
+
+	if err := m.state.UpdateWorkload(ctx, wl); err != nil {
+		return err
+	}
+
+	m.emit(ctx, NewWorkloadUnloadedEvent(m.id, w.GetID(), wl, reason, took))
+	return nil
 }
 
 func (m *Manager) sort(counters map[string]int) (string, string, int) {
@@ -2,6 +2,9 @@ package manager
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 )
@@ -202,3 +205,298 @@ func TestDistributionCleanup(t *testing.T) {
 		t.Errorf("expected errornous workload to have ben set to %s, got: %s", StatusInit, state.workloads["workload-1"].GetStatus())
 	}
 }
+
+func TestRebalancerStatus(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	for i := range 6 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id, status: StatusRunning}
+		state.associations[id] = "worker0"
+	}
+
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   &mockSignaller{},
+		maxDelta: 2,
+	}
+	mgr.rebalance()
+
+	moved := 0
+	for id, wl := range state.workloads {
+		if _, ok := state.associations[id]; ok {
+			continue
+		}
+
+		moved++
+		if exp, recv := StatusInit, wl.GetStatus(); exp != recv {
+			t.Errorf("expected unloaded workload '%s' to be '%s', but got: %s", id, exp, recv)
+		}
+
+		h := mgr.history[id]
+		if exp, recv := 2, len(h); exp != recv {
+			t.Fatalf("expected %d transitions for '%s', but got: %d", exp, id, recv)
+		}
+
+		if h[0].To != StatusUnloading || h[1].To != StatusInit {
+			t.Errorf("expected '%s' to go through '%s' to '%s', but got: %+v", id, StatusUnloading, StatusInit, h)
+		}
+	}
+
+	if moved == 0 {
+		t.Fatalf("expected rebalance to move workloads, but none were moved")
+	}
+}
+
+func TestDistributorStatus(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]Status{}
+
+	w := &mockWorker{
+		id: "worker0",
+		onLoad: func(wl Workload) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			seen[wl.GetID()] = wl.GetStatus()
+		},
+	}
+
+	state := &MemoryStore{
+		workers: map[string]Worker{w.GetID(): w},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+			"workload1": &mockWorkload{id: "workload1"},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+	mgr.distributor()
+
+	for id, s := range seen {
+		if exp, recv := StatusDistributing, s; exp != recv {
+			t.Errorf("expected '%s' to be '%s' during load, but was: %s", id, exp, recv)
+		}
+	}
+
+	if exp, recv := 2, signal.count(EventWorkloadDistributed); exp != recv {
+		t.Errorf("expected %d distributed events, but got: %d", exp, recv)
+	}
+}
+
+func TestDistributorLoadTimeout(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0", delay: time.Second},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:       state,
+		ctx:         context.TODO(),
+		signal:      signal,
+		loadTimeout: 10 * time.Millisecond,
+	}
+
+	start := time.Now()
+	mgr.distributor()
+
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected distribution to give up on the hung load, but it took: %s", elapsed)
+	}
+
+	if exp, recv := StatusErr, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Errorf("expected timed out workload to be '%s', but got: %s", exp, recv)
+	}
+
+	if exp, recv := 0, len(state.associations); exp != recv {
+		t.Errorf("expected %d associations after a failed load, but got: %d", exp, recv)
+	}
+
+	if exp, recv := 1, signal.count(EventWorkloadDistributedError); exp != recv {
+		t.Errorf("expected %d distribution error events, but got: %d", exp, recv)
+	}
+}
+
+func TestDistributorLoadError(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0", loadErr: errors.New("unreachable")},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:     state,
+		ctx:       context.TODO(),
+		signal:    signal,
+		retryBase: time.Minute,
+	}
+	mgr.distributor()
+
+	if exp, recv := StatusErr, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Errorf("expected failed workload to be '%s', but got: %s", exp, recv)
+	}
+
+	if exp, recv := 1, signal.count(EventWorkloadDistributedError); exp != recv {
+		t.Errorf("expected %d distribution error events, but got: %d", exp, recv)
+	}
+
+	// a failed workload must not be picked up again until its backoff has passed
+	mgr.distributor()
+
+	if exp, recv := 1, signal.count(EventWorkloadDistributedError); exp != recv {
+		t.Errorf("expected failed workload to be skipped, but got %d error events", recv)
+	}
+}
+
+func TestDistributorEvents(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+			"workload1": &mockWorkload{id: "workload1"},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+	mgr.distributor()
+
+	if exp, recv := 1, signal.count(EventDistributionStarted); exp != recv {
+		t.Errorf("expected %d distribution started event(s), but got: %d", exp, recv)
+	}
+
+	// init -> distributing -> running for both workloads
+	if exp, recv := 4, signal.count(EventWorkloadStatusChanged); exp != recv {
+		t.Errorf("expected %d status changed events, but got: %d", exp, recv)
+	}
+
+	for _, e := range signal.events {
+		if e.Type != EventDistributionFinished {
+			continue
+		}
+
+		p, ok := e.Payload.(DistributionFinishedPayload)
+		if !ok {
+			t.Fatalf("expected a typed distribution finished payload, but got: %T", e.Payload)
+		}
+
+		if exp, recv := 2, p.Distributed; exp != recv {
+			t.Errorf("expected %d distributed workloads, but got: %d", exp, recv)
+		}
+
+		return
+	}
+
+	t.Errorf("expected a distribution finished event, but got none")
+}
+
+func TestRebalancerEvents(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	for i := range 4 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id, status: StatusRunning}
+		state.associations[id] = "worker0"
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   signal,
+		maxDelta: 1,
+	}
+	mgr.rebalance()
+
+	if signal.count(EventWorkerOverloaded) == 0 {
+		t.Errorf("expected a worker overloaded event, but got none")
+	}
+
+	unassociated := len(state.workloads) - len(state.associations)
+	if exp, recv := unassociated, signal.count(EventWorkloadUnloaded); exp != recv {
+		t.Errorf("expected %d workload unloaded events, but got: %d", exp, recv)
+	}
+
+	if exp, recv := 1, signal.count(EventRebalanceFinished); exp != recv {
+		t.Errorf("expected %d rebalance finished event(s), but got: %d", exp, recv)
+	}
+}
+
+func TestCleanupEvents(t *testing.T) {
+	state := &MemoryStore{
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{
+				id:           "workload0",
+				status:       StatusErr,
+				statusChange: time.Now().Add(-time.Hour),
+			},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+	mgr.cleanup()
+
+	if exp, recv := 1, signal.count(EventWorkloadCleanupReset); exp != recv {
+		t.Fatalf("expected %d cleanup reset event(s), but got: %d", exp, recv)
+	}
+
+	for _, e := range signal.events {
+		if e.Type != EventWorkloadCleanupReset {
+			continue
+		}
+
+		p, ok := e.Payload.(CleanupResetPayload)
+		if !ok {
+			t.Fatalf("expected a typed cleanup reset payload, but got: %T", e.Payload)
+		}
+
+		if p.From != StatusErr || p.To != StatusInit || p.Age < time.Hour {
+			t.Errorf("unexpected cleanup reset payload: %+v", p)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+// Signallers implementing EnvelopeSignals additionally receive
+// every event wrapped in the envelope shared with the worker
+type EnvelopeSignals interface {
+	Envelope(event.Envelope)
+}
+
+// Data carried by the envelope of a manager event, the resource is
+// carried as the subject and the manager as the source
+type EnvelopeData struct {
+	WorkerID string         `json:"workerId,omitempty"`
+	Extra    map[string]any `json:"extra,omitempty"`
+	Payload  any            `json:"payload,omitempty"`
+}
+
+// Wraps the event in the envelope shared with the worker
+func (e Event) Envelope() event.Envelope {
+	env := event.Envelope{
+		ID:            e.ID,
+		Type:          event.NewType(event.NamespaceManager, e.Type.String()),
+		Source:        event.ManagerSource(e.ManagerID),
+		Subject:       e.ResourceID,
+		Time:          e.Time,
+		CorrelationID: e.CorrelationID,
+	}
+
+	if e.WorkerID != "" || e.Extra != nil || e.Payload != nil {
+		env.Data = EnvelopeData{
+			WorkerID: e.WorkerID,
+			Extra:    e.Extra,
+			Payload:  e.Payload,
+		}
+	}
+
+	return env
+}
+
+// Unwraps a manager event from an envelope
+func FromEnvelope(env event.Envelope) (Event, error) {
+	var e Event
+
+	if ns := env.Type.Namespace(); ns != event.NamespaceManager {
+		return e, fmt.Errorf("%w: '%s' is not a manager event", ErrInvalidEvent, env.Type)
+	}
+
+	if err := e.Type.UnmarshalJSON([]byte(`"` + env.Type.Name() + `"`)); err != nil {
+		return e, fmt.Errorf("%w: '%s'", err, env.Type)
+	}
+
+	ns, id, err := event.ParseSource(env.Source)
+	if err != nil {
+		return e, err
+	}
+
+	if ns != event.NamespaceManager {
+		return e, fmt.Errorf("%w: '%s' is not a manager", event.ErrInvalidSource, env.Source)
+	}
+
+	data := struct {
+		WorkerID string          `json:"workerId"`
+		Extra    map[string]any  `json:"extra"`
+		Payload  json.RawMessage `json:"payload"`
+	}{}
+
+	if err := env.DecodeData(&data); err != nil {
+		return e, err
+	}
+
+	payload, err := decodeEventPayload(e.Type, data.Payload)
+	if err != nil {
+		return e, err
+	}
+
+	e.ID = env.ID
+	e.ManagerID = id
+	e.ResourceID = env.Subject
+	e.WorkerID = data.WorkerID
+	e.Time = env.Time
+	e.CorrelationID = env.CorrelationID
+	e.Extra = data.Extra
+	e.Payload = payload
+
+	return e, nil
+}
@@ -0,0 +1,130 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+type envelopeSignaller struct {
+	recordingSignaller
+
+	envMu     sync.Mutex
+	envelopes []event.Envelope
+}
+
+func (s *envelopeSignaller) Envelope(e event.Envelope) {
+	s.envMu.Lock()
+	defer s.envMu.Unlock()
+
+	s.envelopes = append(s.envelopes, e)
+}
+
+func TestEventEnvelope(t *testing.T) {
+	exp := NewWorkerBreakerEvent("manager0", "worker0", BreakerClosed, BreakerOpen)
+	exp.ID = "event0"
+	exp.Time = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	exp.CorrelationID = "correlation0"
+
+	env := exp.Envelope()
+
+	if exp, recv := event.Type("manager.worker.breaker.changed"), env.Type; exp != recv {
+		t.Errorf("expected type '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "/ottomato/managers/manager0", env.Source; exp != recv {
+		t.Errorf("expected source '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "worker0", env.Subject; exp != recv {
+		t.Errorf("expected subject '%s', but got: '%s'", exp, recv)
+	}
+
+	b, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("unexpected error when serializing envelope: %v", err)
+	}
+
+	var decoded event.Envelope
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error when deserializing envelope: %v", err)
+	}
+
+	for _, env := range []event.Envelope{env, decoded} {
+		recv, err := FromEnvelope(env)
+		if err != nil {
+			t.Fatalf("unexpected error when unwrapping envelope: %v", err)
+		}
+
+		if exp.ID != recv.ID || exp.Type != recv.Type || exp.ManagerID != recv.ManagerID || exp.WorkerID != recv.WorkerID ||
+			exp.ResourceID != recv.ResourceID || exp.CorrelationID != recv.CorrelationID || !exp.Time.Equal(recv.Time) {
+			t.Errorf("expected event to survive a round trip\nexp:  %+v\nrecv: %+v", exp, recv)
+		}
+
+		if p, ok := recv.Payload.(BreakerPayload); !ok || p.To != BreakerOpen {
+			t.Errorf("expected a typed payload, but got: %#v", recv.Payload)
+		}
+	}
+
+	if _, err := FromEnvelope(event.Envelope{Type: "worker.workload.added", Source: event.WorkerSource("worker0")}); err == nil {
+		t.Errorf("expected an error when unwrapping a worker envelope")
+	}
+}
+
+func TestEmitEnvelope(t *testing.T) {
+	signal := &envelopeSignaller{}
+	mgr := &Manager{id: "manager0", signal: signal}
+
+	ctx := event.WithCorrelationID(context.TODO(), "correlation0")
+	mgr.emit(ctx, NewWorkloadAddedEvent(mgr.id, &mockWorkload{id: "workload0"}))
+
+	if exp, recv := 1, len(signal.envelopes); exp != recv {
+		t.Fatalf("expected %d envelope(s), but got: %d", exp, recv)
+	}
+
+	e, env := signal.events[0], signal.envelopes[0]
+	if e.ID == "" || e.Time.IsZero() {
+		t.Errorf("expected the event to be stamped with an id and time, but got: %+v", e)
+	}
+
+	if e.ID != env.ID || e.CorrelationID != env.CorrelationID {
+		t.Errorf("expected the envelope to match the event\nevent:    %+v\nenvelope: %+v", e, env)
+	}
+
+	if exp, recv := "correlation0", env.CorrelationID; exp != recv {
+		t.Errorf("expected correlation id '%s', but got: '%s'", exp, recv)
+	}
+}
+
+func TestDistributorCorrelation(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+	mgr.distributor()
+
+	ids := map[string]bool{}
+	for _, e := range signal.events {
+		ids[e.CorrelationID] = true
+	}
+
+	if exp, recv := 1, len(ids); exp != recv || ids[""] {
+		t.Errorf("expected every event of a pass to share a correlation id, but got: %v", ids)
+	}
+}
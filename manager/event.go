@@ -1,16 +1,22 @@
 package manager
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 type Event struct {
-	Type       EventType      `json:"type"`
-	ManagerID  string         `json:"managerId"`
-	ResourceID string         `json:"resourceId"`
-	WorkerID   string         `json:"workerId,omitempty"`
-	Extra      map[string]any `json:"extra,omitempty"`
+	ID            string         `json:"id,omitempty"`
+	Type          EventType      `json:"type"`
+	ManagerID     string         `json:"managerId"`
+	ResourceID    string         `json:"resourceId"`
+	WorkerID      string         `json:"workerId,omitempty"`
+	Time          time.Time      `json:"time,omitzero"`
+	CorrelationID string         `json:"correlationId,omitempty"`
+	Extra         map[string]any `json:"extra,omitempty"`
+	Payload       any            `json:"payload,omitempty"`
 }
 
 var (
@@ -30,6 +36,25 @@ const (
 
 	EventWorkloadDistributed
 	EventWorkloadDistributedError
+
+	EventWorkerBreakerChanged
+
+	EventWorkloadUnloaded
+	EventWorkloadStatusChanged
+	EventWorkloadCleanupReset
+	EventWorkerOverloaded
+	EventDistributionStarted
+	EventDistributionFinished
+
+	EventWorkloadUnloadedError
+	EventRebalanceFinished
+
+	EventWorkerCordoned
+	EventWorkerUncordoned
+
+	EventWorkerRegistered
+
+	EventError
 )
 
 func (e EventType) String() string {
@@ -48,6 +73,32 @@ func (e EventType) String() string {
 		return "workload.distributed"
 	case EventWorkloadDistributedError:
 		return "workload.distributed.error"
+	case EventWorkerBreakerChanged:
+		return "worker.breaker.changed"
+	case EventWorkloadUnloaded:
+		return "workload.unloaded"
+	case EventWorkloadStatusChanged:
+		return "workload.status.changed"
+	case EventWorkloadCleanupReset:
+		return "workload.cleanup.reset"
+	case EventWorkerOverloaded:
+		return "worker.overloaded"
+	case EventDistributionStarted:
+		return "distribution.started"
+	case EventDistributionFinished:
+		return "distribution.finished"
+	case EventWorkloadUnloadedError:
+		return "workload.unloaded.error"
+	case EventRebalanceFinished:
+		return "rebalance.finished"
+	case EventWorkerCordoned:
+		return "worker.cordoned"
+	case EventWorkerUncordoned:
+		return "worker.uncordoned"
+	case EventWorkerRegistered:
+		return "worker.registered"
+	case EventError:
+		return "manager.error"
 	default:
 		return ""
 	}
@@ -74,10 +125,36 @@ func (e *EventType) UnmarshalJSON(val []byte) error {
 		*e = EventWorkloadDeleted
 	case `"workload.distributed"`:
 		*e = EventWorkloadDistributed
-	case "`worker.distribution.stats`":
+	case `"worker.distribution.stats"`:
 		*e = EventDistributionStats
 	case `"workload.distributed.error"`:
 		*e = EventWorkloadDistributedError
+	case `"worker.breaker.changed"`:
+		*e = EventWorkerBreakerChanged
+	case `"workload.unloaded"`:
+		*e = EventWorkloadUnloaded
+	case `"workload.status.changed"`:
+		*e = EventWorkloadStatusChanged
+	case `"workload.cleanup.reset"`:
+		*e = EventWorkloadCleanupReset
+	case `"worker.overloaded"`:
+		*e = EventWorkerOverloaded
+	case `"distribution.started"`:
+		*e = EventDistributionStarted
+	case `"distribution.finished"`:
+		*e = EventDistributionFinished
+	case `"workload.unloaded.error"`:
+		*e = EventWorkloadUnloadedError
+	case `"rebalance.finished"`:
+		*e = EventRebalanceFinished
+	case `"worker.cordoned"`:
+		*e = EventWorkerCordoned
+	case `"worker.uncordoned"`:
+		*e = EventWorkerUncordoned
+	case `"worker.registered"`:
+		*e = EventWorkerRegistered
+	case `"manager.error"`:
+		*e = EventError
 	default:
 		return ErrInvalidEvent
 	}
@@ -85,10 +162,149 @@ func (e *EventType) UnmarshalJSON(val []byte) error {
 	return nil
 }
 
+// Decodes the payload in to the type belonging to the event type
+func (e *Event) UnmarshalJSON(input []byte) error {
+	type event Event
+
+	tmp := &struct {
+		*event
+		Payload json.RawMessage `json:"payload,omitempty"`
+	}{
+		event: (*event)(e),
+	}
+
+	if err := json.Unmarshal(input, &tmp); err != nil {
+		return err
+	}
+
+	var err error
+	e.Payload, err = decodeEventPayload(e.Type, tmp.Payload)
+
+	return err
+}
+
+// Decodes a payload in to the type belonging to the event type
+func decodeEventPayload(t EventType, raw json.RawMessage) (any, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+
+	switch t {
+	case EventWorkloadDistributed:
+		return decodePayload[DistributedPayload](raw)
+	case EventWorkloadDistributedError:
+		return decodePayload[DistributionErrorPayload](raw)
+	case EventWorkerBreakerChanged:
+		return decodePayload[BreakerPayload](raw)
+	case EventWorkloadUnloaded:
+		return decodePayload[UnloadedPayload](raw)
+	case EventWorkloadUnloadedError:
+		return decodePayload[UnloadErrorPayload](raw)
+	case EventWorkloadStatusChanged:
+		return decodePayload[Transition](raw)
+	case EventWorkloadCleanupReset:
+		return decodePayload[CleanupResetPayload](raw)
+	case EventWorkerOverloaded:
+		return decodePayload[OverloadedPayload](raw)
+	case EventDistributionStarted:
+		return decodePayload[DistributionStartedPayload](raw)
+	case EventDistributionFinished:
+		return decodePayload[DistributionFinishedPayload](raw)
+	case EventRebalanceFinished:
+		return decodePayload[RebalanceFinishedPayload](raw)
+	case EventWorkerRegistered:
+		return decodePayload[RegisteredPayload](raw)
+	case EventError:
+		return decodePayload[ErrorPayload](raw)
+	default:
+		return decodePayload[map[string]any](raw)
+	}
+}
+
+func decodePayload[T any](raw json.RawMessage) (any, error) {
+	var p T
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+type DistributedPayload struct {
+	Duration time.Duration `json:"duration"` // time spent in Load
+}
+
+type DistributionErrorPayload struct {
+	Error    string        `json:"error"`
+	Duration time.Duration `json:"duration"` // time spent in Load
+}
+
+type BreakerPayload struct {
+	From BreakerState `json:"from"`
+	To   BreakerState `json:"to"`
+}
+
+type UnloadedPayload struct {
+	Reason   string        `json:"reason"`
+	Duration time.Duration `json:"duration"` // time spent in Unload
+}
+
+type UnloadErrorPayload struct {
+	Reason   string        `json:"reason"`
+	Error    string        `json:"error"`
+	Duration time.Duration `json:"duration"` // time spent in Unload
+}
+
+type CleanupResetPayload struct {
+	From Status        `json:"from"`
+	To   Status        `json:"to"`
+	Age  time.Duration `json:"age"` // time spent in the previous status
+}
+
+type OverloadedPayload struct {
+	Workloads int `json:"workloads"`
+	Delta     int `json:"delta"`
+	MaxDelta  int `json:"maxDelta"`
+}
+
+type DistributionStartedPayload struct {
+	Workers   int `json:"workers"`
+	Workloads int `json:"workloads"`
+}
+
+type DistributionFinishedPayload struct {
+	Distributed int           `json:"distributed"`
+	Failed      int           `json:"failed"`
+	Unloaded    int           `json:"unloaded"`
+	Skipped     int           `json:"skipped"`
+	Duration    time.Duration `json:"duration"`
+}
+
+type RebalanceFinishedPayload struct {
+	Moved    int           `json:"moved"`
+	Duration time.Duration `json:"duration"`
+}
+
+type RegisteredPayload struct {
+	Generation uint64            `json:"generation"`
+	Rejoined   bool              `json:"rejoined"` // re-registered after a restart
+	Reclaimed  int               `json:"reclaimed"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Capacity   int               `json:"capacity,omitempty"`
+	Version    string            `json:"version,omitempty"`
+}
+
+type ErrorPayload struct {
+	Error string `json:"error"`
+
+	err error // handed to the signaller as is
+}
+
 func NewWorkerAddedEvent(managerId string, worker Worker) Event {
 	return Event{
 		Type:       EventWorkerAdded,
 		ManagerID:  managerId,
+		WorkerID:   worker.GetID(),
 		ResourceID: worker.GetID(),
 	}
 }
@@ -97,10 +313,46 @@ func NewWorkerDeletedEvent(managerId string, worker Worker) Event {
 	return Event{
 		Type:       EventWorkerDeleted,
 		ManagerID:  managerId,
+		WorkerID:   worker.GetID(),
+		ResourceID: worker.GetID(),
+	}
+}
+
+func NewWorkerCordonedEvent(managerId string, worker Worker) Event {
+	return Event{
+		Type:       EventWorkerCordoned,
+		ManagerID:  managerId,
+		WorkerID:   worker.GetID(),
+		ResourceID: worker.GetID(),
+	}
+}
+
+func NewWorkerUncordonedEvent(managerId string, worker Worker) Event {
+	return Event{
+		Type:       EventWorkerUncordoned,
+		ManagerID:  managerId,
+		WorkerID:   worker.GetID(),
 		ResourceID: worker.GetID(),
 	}
 }
 
+func NewWorkerRegisteredEvent(managerId string, reg Registration, s Session, rejoined bool) Event {
+	return Event{
+		Type:       EventWorkerRegistered,
+		ManagerID:  managerId,
+		WorkerID:   reg.ID,
+		ResourceID: reg.ID,
+		Payload: RegisteredPayload{
+			Generation: s.Generation,
+			Rejoined:   rejoined,
+			Reclaimed:  len(s.Workloads),
+			Labels:     reg.Labels,
+			Capacity:   reg.Capacity,
+			Version:    reg.Version,
+		},
+	}
+}
+
 func NewWorkloadAddedEvent(managerId string, workload Workload) Event {
 	return Event{
 		Type:       EventWorkloadAdded,
@@ -117,20 +369,112 @@ func NewWorkloadDeletedEvent(managerId string, workload Workload) Event {
 	}
 }
 
-func NewWorkloadDistributedEvent(managerId, workerId string, workload Workload) Event {
+func NewWorkloadDistributedEvent(managerId, workerId string, workload Workload, took time.Duration) Event {
 	return Event{
 		Type:       EventWorkloadDistributed,
 		ManagerID:  managerId,
 		WorkerID:   workerId,
 		ResourceID: workload.GetID(),
+		Payload:    DistributedPayload{Duration: took},
 	}
 }
 
-func NewWorkloadDistributedErrorEvent(managerId, workerId string, workload Workload) Event {
+func NewWorkloadDistributedErrorEvent(managerId, workerId string, workload Workload, err error, took time.Duration) Event {
 	return Event{
 		Type:       EventWorkloadDistributedError,
 		ManagerID:  managerId,
 		WorkerID:   workerId,
 		ResourceID: workload.GetID(),
+		Payload:    DistributionErrorPayload{Error: err.Error(), Duration: took},
+	}
+}
+
+func NewWorkerBreakerEvent(managerId, workerId string, from, to BreakerState) Event {
+	return Event{
+		Type:       EventWorkerBreakerChanged,
+		ManagerID:  managerId,
+		WorkerID:   workerId,
+		ResourceID: workerId,
+		Payload:    BreakerPayload{From: from, To: to},
+	}
+}
+
+func NewWorkloadUnloadedEvent(managerId, workerId string, workload Workload, reason string, took time.Duration) Event {
+	return Event{
+		Type:       EventWorkloadUnloaded,
+		ManagerID:  managerId,
+		WorkerID:   workerId,
+		ResourceID: workload.GetID(),
+		Payload:    UnloadedPayload{Reason: reason, Duration: took},
+	}
+}
+
+func NewWorkloadUnloadedErrorEvent(managerId, workerId string, workload Workload, reason string, err error, took time.Duration) Event {
+	return Event{
+		Type:       EventWorkloadUnloadedError,
+		ManagerID:  managerId,
+		WorkerID:   workerId,
+		ResourceID: workload.GetID(),
+		Payload:    UnloadErrorPayload{Reason: reason, Error: err.Error(), Duration: took},
+	}
+}
+
+func NewWorkloadStatusChangedEvent(managerId string, workload Workload, t Transition) Event {
+	return Event{
+		Type:       EventWorkloadStatusChanged,
+		ManagerID:  managerId,
+		ResourceID: workload.GetID(),
+		Payload:    t,
+	}
+}
+
+func NewWorkloadCleanupResetEvent(managerId string, workload Workload, from, to Status, age time.Duration) Event {
+	return Event{
+		Type:       EventWorkloadCleanupReset,
+		ManagerID:  managerId,
+		ResourceID: workload.GetID(),
+		Payload:    CleanupResetPayload{From: from, To: to, Age: age},
+	}
+}
+
+func NewWorkerOverloadedEvent(managerId, workerId string, workloads, delta, maxDelta int) Event {
+	return Event{
+		Type:       EventWorkerOverloaded,
+		ManagerID:  managerId,
+		WorkerID:   workerId,
+		ResourceID: workerId,
+		Payload:    OverloadedPayload{Workloads: workloads, Delta: delta, MaxDelta: maxDelta},
+	}
+}
+
+func NewDistributionStartedEvent(managerId string, workers, workloads int) Event {
+	return Event{
+		Type:      EventDistributionStarted,
+		ManagerID: managerId,
+		Payload:   DistributionStartedPayload{Workers: workers, Workloads: workloads},
+	}
+}
+
+func NewDistributionFinishedEvent(managerId string, p DistributionFinishedPayload) Event {
+	return Event{
+		Type:      EventDistributionFinished,
+		ManagerID: managerId,
+		Payload:   p,
+	}
+}
+
+func NewRebalanceFinishedEvent(managerId string, moved int, took time.Duration) Event {
+	return Event{
+		Type:      EventRebalanceFinished,
+		ManagerID: managerId,
+		Payload:   RebalanceFinishedPayload{Moved: moved, Duration: took},
+	}
+}
+
+func NewErrorEvent(managerId string, err error) Event {
+	return Event{
+		Type:      EventError,
+		ManagerID: managerId,
+		Payload:   ErrorPayload{Error: err.Error(), err: err},
 	}
 }
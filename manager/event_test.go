@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestEventTypeSerialize(t *testing.T) {
@@ -15,6 +17,19 @@ func TestEventTypeSerialize(t *testing.T) {
 		EventWorkloadDeleted:          []byte(`"workload.deleted"`),
 		EventWorkloadDistributed:      []byte(`"workload.distributed"`),
 		EventWorkloadDistributedError: []byte(`"workload.distributed.error"`),
+		EventWorkerBreakerChanged:     []byte(`"worker.breaker.changed"`),
+		EventDistributionStats:        []byte(`"worker.distribution.stats"`),
+		EventWorkloadUnloaded:         []byte(`"workload.unloaded"`),
+		EventWorkloadStatusChanged:    []byte(`"workload.status.changed"`),
+		EventWorkloadCleanupReset:     []byte(`"workload.cleanup.reset"`),
+		EventWorkerOverloaded:         []byte(`"worker.overloaded"`),
+		EventDistributionStarted:      []byte(`"distribution.started"`),
+		EventDistributionFinished:     []byte(`"distribution.finished"`),
+		EventWorkloadUnloadedError:    []byte(`"workload.unloaded.error"`),
+		EventRebalanceFinished:        []byte(`"rebalance.finished"`),
+		EventWorkerCordoned:           []byte(`"worker.cordoned"`),
+		EventWorkerUncordoned:         []byte(`"worker.uncordoned"`),
+		EventError:                    []byte(`"manager.error"`),
 	}
 
 	for input, exp := range cases {
@@ -48,6 +63,18 @@ func TestEventTypeDeserialize(t *testing.T) {
 		`"workload.deleted"`:           EventWorkloadDeleted,
 		`"workload.distributed"`:       EventWorkloadDistributed,
 		`"workload.distributed.error"`: EventWorkloadDistributedError,
+		`"worker.breaker.changed"`:     EventWorkerBreakerChanged,
+		`"worker.distribution.stats"`:  EventDistributionStats,
+		`"workload.unloaded"`:          EventWorkloadUnloaded,
+		`"workload.status.changed"`:    EventWorkloadStatusChanged,
+		`"workload.cleanup.reset"`:     EventWorkloadCleanupReset,
+		`"worker.overloaded"`:          EventWorkerOverloaded,
+		`"distribution.started"`:       EventDistributionStarted,
+		`"distribution.finished"`:      EventDistributionFinished,
+		`"workload.unloaded.error"`:    EventWorkloadUnloadedError,
+		`"rebalance.finished"`:         EventRebalanceFinished,
+		`"worker.cordoned"`:            EventWorkerCordoned,
+		`"worker.uncordoned"`:          EventWorkerUncordoned,
 	}
 
 	for input, exp := range cases {
@@ -73,3 +100,55 @@ func TestEventTypeDeserializeError(t *testing.T) {
 		t.Fatalf("expected an ErrInvalidEvent, but got: %v", err)
 	}
 }
+
+func TestEventRoundTrip(t *testing.T) {
+	wl := &mockWorkload{id: "workload0"}
+	w := &mockWorker{id: "worker0"}
+
+	cases := []Event{
+		NewWorkerAddedEvent("manager0", w),
+		NewWorkloadDistributedEvent("manager0", w.GetID(), wl, time.Second),
+		NewWorkloadDistributedErrorEvent("manager0", w.GetID(), wl, errors.New("unreachable"), time.Second),
+		NewWorkerBreakerEvent("manager0", w.GetID(), BreakerClosed, BreakerOpen),
+		NewWorkloadUnloadedEvent("manager0", w.GetID(), wl, "rebalance", time.Second),
+		NewWorkloadUnloadedErrorEvent("manager0", w.GetID(), wl, "unwanted", errors.New("unreachable"), time.Second),
+		NewRebalanceFinishedEvent("manager0", 3, time.Second),
+		NewWorkloadStatusChangedEvent("manager0", wl, Transition{
+			From:      StatusDistributing,
+			To:        StatusRunning,
+			Reason:    "distributed to worker0",
+			Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		}),
+		NewWorkloadCleanupResetEvent("manager0", wl, StatusErr, StatusInit, time.Minute),
+		NewWorkerOverloadedEvent("manager0", w.GetID(), 20, 8, 5),
+		NewDistributionStartedEvent("manager0", 2, 20),
+		NewDistributionFinishedEvent("manager0", DistributionFinishedPayload{
+			Distributed: 18,
+			Failed:      1,
+			Unloaded:    3,
+			Skipped:     1,
+			Duration:    1500 * time.Millisecond,
+		}),
+		{
+			Type:      EventDistributionStats,
+			ManagerID: "manager0",
+			Extra:     map[string]any{"workers": float64(2)},
+		},
+	}
+
+	for _, exp := range cases {
+		b, err := json.Marshal(exp)
+		if err != nil {
+			t.Fatalf("unexpected error when serializing '%s' event: %v", exp.Type, err)
+		}
+
+		var recv Event
+		if err := json.Unmarshal(b, &recv); err != nil {
+			t.Fatalf("unexpected error when deserializing '%s' event: %v", exp.Type, err)
+		}
+
+		if !reflect.DeepEqual(exp, recv) {
+			t.Errorf("expected '%s' event to survive a round trip\nexp:  %+v\nrecv: %+v", exp.Type, exp, recv)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Default amount of transitions kept per workload
+const defaultHistorySize = 32
+
+type Transition struct {
+	From      Status    `json:"from"`
+	To        Status    `json:"to"`
+	Reason    string    `json:"reason"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Moves a workload to a new status if the transition is allowed,
+// and records it in the workload's history. The caller is
+// responsible for persisting the workload afterwards.
+func (m *Manager) transition(ctx context.Context, wl Workload, to Status, reason string) error {
+	from := wl.GetStatus()
+	if from == to {
+		return nil
+	}
+
+	if !from.CanTransition(to) {
+		return fmt.Errorf("%w: '%s' from %s to %s", ErrInvalidTransition, wl.GetID(), from, to)
+	}
+
+	wl.SetStatus(to)
+
+	t := Transition{
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		Timestamp: wl.LastStatusChange(),
+	}
+
+	m.record(wl.GetID(), t)
+	m.emit(ctx, NewWorkloadStatusChangedEvent(m.id, wl, t))
+
+	return nil
+}
+
+// Appends a transition to a workload's history, dropping the
+// oldest entries when the history is full
+func (m *Manager) record(id string, t Transition) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	if m.history == nil {
+		m.history = map[string][]Transition{}
+	}
+
+	size := m.historySize
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+
+	h := append(m.history[id], t)
+	if len(h) > size {
+		h = h[len(h)-size:]
+	}
+
+	m.history[id] = h
+}
+
+// Returns the latest transition of a workload, if any is recorded
+func (m *Manager) lastTransition(id string) (Transition, bool) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	h := m.history[id]
+	if len(h) == 0 {
+		return Transition{}, false
+	}
+
+	return h[len(h)-1], true
+}
+
+func (m *Manager) forget(id string) {
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	delete(m.history, id)
+}
+
+// Returns the recorded status transitions for a workload, oldest first
+func (m *Manager) WorkloadHistory(ctx context.Context, id string) ([]Transition, error) {
+	if _, err := m.GetWorkload(ctx, id); err != nil {
+		return nil, err
+	}
+
+	m.historyMu.Lock()
+	defer m.historyMu.Unlock()
+
+	h := make([]Transition, len(m.history[id]))
+	copy(h, m.history[id])
+
+	return h, nil
+}
+
+// Moves a workload to a new status, rejecting transitions which
+// are not allowed by the workload status state machine
+func (m *Manager) SetWorkloadStatus(ctx context.Context, wl Workload, s Status, reason string) error {
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	if err := m.transition(ctx, wl, s, reason); err != nil {
+		return err
+	}
+
+	return m.state.UpdateWorkload(ctx, wl)
+}
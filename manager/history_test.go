@@ -0,0 +1,99 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTransition(t *testing.T) {
+	mgr := &Manager{
+		state:  NewMemoryStore(),
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+	wl := &mockWorkload{id: "workload0"}
+
+	if err := mgr.transition(context.TODO(), wl, StatusDistributing, "test"); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if exp, recv := StatusDistributing, wl.GetStatus(); exp != recv {
+		t.Fatalf("expected status to be '%s', but got: %s", exp, recv)
+	}
+
+	err := mgr.transition(context.TODO(), wl, StatusDown, "test")
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected an ErrInvalidTransition, but got: %v", err)
+	}
+
+	if exp, recv := StatusDistributing, wl.GetStatus(); exp != recv {
+		t.Fatalf("expected status to remain '%s' after an invalid transition, but got: %s", exp, recv)
+	}
+
+	h := mgr.history[wl.GetID()]
+	if exp, recv := 1, len(h); exp != recv {
+		t.Fatalf("expected %d transition(s) in history, but got: %d", exp, recv)
+	}
+
+	if h[0].From != StatusInit || h[0].To != StatusDistributing || h[0].Reason != "test" {
+		t.Errorf("unexpected transition recorded: %+v", h[0])
+	}
+}
+
+func TestHistorySize(t *testing.T) {
+	mgr := &Manager{
+		state:       NewMemoryStore(),
+		ctx:         context.TODO(),
+		signal:      &mockSignaller{},
+		historySize: 3,
+	}
+	wl := &mockWorkload{id: "workload0"}
+
+	for range 5 {
+		mgr.transition(context.TODO(), wl, StatusRunning, "test")
+		mgr.transition(context.TODO(), wl, StatusErr, "test")
+		mgr.transition(context.TODO(), wl, StatusInit, "test")
+	}
+
+	h := mgr.history[wl.GetID()]
+	if exp, recv := 3, len(h); exp != recv {
+		t.Fatalf("expected history to be capped at %d, but got: %d", exp, recv)
+	}
+
+	if exp, recv := StatusInit, h[len(h)-1].To; exp != recv {
+		t.Errorf("expected the latest transition to be kept, but got: %s", recv)
+	}
+}
+
+func TestWorkloadHistory(t *testing.T) {
+	wl := &mockWorkload{id: "workload0"}
+	mgr := &Manager{
+		state: &MemoryStore{
+			workloads: map[string]Workload{wl.GetID(): wl},
+		},
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	if err := mgr.SetWorkloadStatus(context.TODO(), wl, StatusRunning, "manual"); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if err := mgr.SetWorkloadStatus(context.TODO(), wl, StatusDistributing, "manual"); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected an ErrInvalidTransition, but got: %v", err)
+	}
+
+	h, err := mgr.WorkloadHistory(context.TODO(), wl.GetID())
+	if err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if exp, recv := 1, len(h); exp != recv {
+		t.Fatalf("expected %d transition(s), but got: %d", exp, recv)
+	}
+
+	if _, err := mgr.WorkloadHistory(context.TODO(), "unknown"); !errors.Is(err, ErrWorkloadNotFound) {
+		t.Errorf("expected an ErrWorkloadNotFound, but got: %v", err)
+	}
+}
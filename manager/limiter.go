@@ -0,0 +1,157 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Bounds the amount of concurrent Load and Unload calls,
+// both in total and towards a single worker
+type limiter struct {
+	global chan struct{}
+
+	mu        sync.Mutex
+	perWorker map[string]chan struct{}
+	size      int
+}
+
+// Counters for the worker calls made during a single job run
+type callStats struct {
+	throttled atomic.Int64 // calls which had to wait for a free slot
+	timedOut  atomic.Int64 // calls which exceeded their timeout
+	skipped   atomic.Int64 // calls which were never made, e.g. past the deadline
+}
+
+// Creates a limiter, a limit of 0 leaves that dimension unbounded
+func newLimiter(global, perWorker int) *limiter {
+	l := &limiter{
+		perWorker: map[string]chan struct{}{},
+		size:      perWorker,
+	}
+
+	if global > 0 {
+		l.global = make(chan struct{}, global)
+	}
+
+	return l
+}
+
+func (l *limiter) worker(id string) chan struct{} {
+	if l.size <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	sem, ok := l.perWorker[id]
+	if !ok {
+		sem = make(chan struct{}, l.size)
+		l.perWorker[id] = sem
+	}
+
+	return sem
+}
+
+// Returns the max amount of calls made to a single worker at once,
+// 0 when it's unbounded
+func (l *limiter) width() int {
+	if l == nil {
+		return 0
+	}
+
+	n := l.size
+	if g := cap(l.global); g > 0 && (n <= 0 || g < n) {
+		n = g
+	}
+
+	return n
+}
+
+// Drops the call slots of a worker which is gone. Calls still holding
+// one hand it back to the dropped slots.
+func (l *limiter) forget(id string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.perWorker, id)
+}
+
+// Waits for a free call slot towards a worker, the returned function
+// hands the slot back. A nil limiter never blocks.
+func (l *limiter) acquire(ctx context.Context, id string, stats *callStats) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		stats.skip()
+		return nil, err
+	}
+
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// the worker slot is taken first, so calls waiting on a saturated
+	// worker don't hold global slots calls to other workers could use
+	sems := make([]chan struct{}, 0, 2)
+	for _, sem := range []chan struct{}{l.worker(id), l.global} {
+		if sem != nil {
+			sems = append(sems, sem)
+		}
+	}
+
+	release := func(held []chan struct{}) {
+		for i := len(held) - 1; i >= 0; i-- {
+			<-held[i]
+		}
+	}
+
+	throttled := false
+	for i, sem := range sems {
+		select {
+		case sem <- struct{}{}:
+			continue
+		default:
+		}
+
+		throttled = true
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			release(sems[:i])
+			stats.throttle()
+			stats.skip()
+			return nil, ctx.Err()
+		}
+	}
+
+	if throttled {
+		stats.throttle()
+	}
+
+	return func() { release(sems) }, nil
+}
+
+func (s *callStats) throttle() {
+	if s != nil {
+		s.throttled.Add(1)
+	}
+}
+
+func (s *callStats) skip() {
+	if s != nil {
+		s.skipped.Add(1)
+	}
+}
+
+// Counts the call as timed out if it exceeded a deadline
+func (s *callStats) observe(err error) {
+	if s != nil && errors.Is(err, context.DeadlineExceeded) {
+		s.timedOut.Add(1)
+	}
+}
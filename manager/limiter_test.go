@@ -0,0 +1,326 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiterGlobal(t *testing.T) {
+	l := newLimiter(2, 0)
+	stats := &callStats{}
+
+	var inflight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := range 6 {
+		wg.Go(func() {
+			release, err := l.acquire(context.TODO(), fmt.Sprintf("worker%d", i), stats)
+			if err != nil {
+				t.Errorf("unexpected error when acquiring a slot: %v", err)
+				return
+			}
+			defer release()
+
+			n := inflight.Add(1)
+			defer inflight.Add(-1)
+
+			if n > peak.Load() {
+				peak.Store(n)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+		})
+	}
+	wg.Wait()
+
+	if recv := peak.Load(); recv > 2 {
+		t.Errorf("expected no more than 2 concurrent calls, but got: %d", recv)
+	}
+
+	if stats.throttled.Load() == 0 {
+		t.Errorf("expected calls to be throttled, but none were")
+	}
+}
+
+func TestLimiterPerWorker(t *testing.T) {
+	l := newLimiter(0, 1)
+
+	release, err := l.acquire(context.TODO(), "worker0", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when acquiring a slot: %v", err)
+	}
+	defer release()
+
+	// a busy worker must not hold back calls towards other workers
+	other, err := l.acquire(context.TODO(), "worker1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when acquiring a slot: %v", err)
+	}
+	other()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	stats := &callStats{}
+	if _, err := l.acquire(ctx, "worker0", stats); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, but got: %v", err)
+	}
+
+	if exp, recv := int64(1), stats.skipped.Load(); exp != recv {
+		t.Errorf("expected %d skipped call(s), but got: %d", exp, recv)
+	}
+}
+
+func TestLimiterSaturatedWorker(t *testing.T) {
+	l := newLimiter(2, 1)
+
+	release, err := l.acquire(context.TODO(), "worker0", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when acquiring a slot: %v", err)
+	}
+
+	// calls waiting on worker0 must not hold global slots
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Go(func() {
+			if release, err := l.acquire(context.TODO(), "worker0", nil); err == nil {
+				release()
+			}
+		})
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+
+	other, err := l.acquire(ctx, "worker1", nil)
+	if err != nil {
+		t.Fatalf("expected a call to another worker to go through, but got: %v", err)
+	}
+
+	other()
+	release()
+	wg.Wait()
+}
+
+func TestNilLimiter(t *testing.T) {
+	var l *limiter
+
+	release, err := l.acquire(context.TODO(), "worker0", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when acquiring from a nil limiter: %v", err)
+	}
+	release()
+}
+
+func TestLimiterForget(t *testing.T) {
+	l := newLimiter(0, 1)
+
+	release, err := l.acquire(context.TODO(), "worker0", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when acquiring a slot: %v", err)
+	}
+
+	l.forget("worker0")
+
+	if exp, recv := 0, len(l.perWorker); exp != recv {
+		t.Errorf("expected %d worker(s) with slots, but got: %d", exp, recv)
+	}
+
+	// a worker joining again with the same id starts with free slots
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+
+	other, err := l.acquire(ctx, "worker0", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when acquiring a slot: %v", err)
+	}
+
+	other()
+	release()
+}
+
+func TestPerWorker(t *testing.T) {
+	mgr := &Manager{calls: newLimiter(10, 2)}
+
+	queues := map[string][]string{}
+	for i := range 20 {
+		for _, w := range []string{"worker0", "worker1"} {
+			queues[w] = append(queues[w], fmt.Sprintf("workload%d", i))
+		}
+	}
+
+	// calls to worker0 block until worker1 is done, which it only
+	// gets to when worker0 doesn't hold it back
+	var mu sync.Mutex
+	inflight, peak, calls := map[string]int{}, map[string]int{}, map[string]int{}
+	worker1 := make(chan struct{})
+
+	mgr.perWorker(queues, func(w, wl string) {
+		mu.Lock()
+		inflight[w]++
+		peak[w] = max(peak[w], inflight[w])
+		calls[w]++
+		if w == "worker1" && calls[w] == len(queues[w]) {
+			close(worker1)
+		}
+		mu.Unlock()
+
+		if w == "worker0" {
+			select {
+			case <-worker1:
+			case <-time.After(time.Second):
+				t.Errorf("expected worker1 to be served while worker0 is busy")
+			}
+		}
+
+		mu.Lock()
+		inflight[w]--
+		mu.Unlock()
+	})
+
+	for _, w := range []string{"worker0", "worker1"} {
+		if exp, recv := 20, calls[w]; exp != recv {
+			t.Errorf("expected %d call(s) to %s, but got: %d", exp, w, recv)
+		}
+
+		if recv := peak[w]; recv > 2 {
+			t.Errorf("expected no more than 2 concurrent calls to %s, but got: %d", w, recv)
+		}
+	}
+}
+
+type statsSignaller struct {
+	recordingSignaller
+}
+
+func (s *statsSignaller) stats() map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.events {
+		if e.Type == EventDistributionStats {
+			return e.Extra
+		}
+	}
+	return nil
+}
+
+func TestDistributorConcurrency(t *testing.T) {
+	w := &mockWorker{id: "worker0", delay: 5 * time.Millisecond}
+	w1 := &mockWorker{id: "worker1", delay: 5 * time.Millisecond}
+	state := &MemoryStore{
+		workers:      map[string]Worker{w.GetID(): w, w1.GetID(): w1},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	for i := range 10 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id}
+	}
+
+	signal := &statsSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+		calls:  newLimiter(3, 2),
+	}
+	mgr.distributor()
+
+	for _, w := range []*mockWorker{w, w1} {
+		if recv := w.peak.Load(); recv > 2 {
+			t.Errorf("expected no more than 2 concurrent loads on %s, but got: %d", w.id, recv)
+		}
+	}
+
+	if exp, recv := 10, len(state.associations); exp != recv {
+		t.Errorf("expected %d workloads to be placed, but got: %d", exp, recv)
+	}
+
+	stats := signal.stats()
+	if stats == nil {
+		t.Fatalf("expected a distribution stats event, but got none")
+	}
+
+	// both workers take 2 calls at once, which the global limit of 3 throttles
+	if throttled, _ := stats["throttled"].(int64); throttled == 0 {
+		t.Errorf("expected throttled calls in stats, but got: %v", stats["throttled"])
+	}
+}
+
+func TestDistributionDeadline(t *testing.T) {
+	w := &mockWorker{id: "worker0", delay: 20 * time.Millisecond}
+	state := &MemoryStore{
+		workers:      map[string]Worker{w.GetID(): w},
+		workloads:    map[string]Workload{},
+		associations: map[string]string{},
+	}
+
+	for i := range 4 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id}
+	}
+
+	signal := &statsSignaller{}
+	mgr := &Manager{
+		state:                state,
+		ctx:                  context.TODO(),
+		signal:               signal,
+		calls:                newLimiter(0, 1),
+		distributionDeadline: 10 * time.Millisecond,
+	}
+	mgr.distributor()
+
+	// only the first call is made before the deadline, the rest are
+	// left untouched for the next pass
+	if exp, recv := 1, len(state.associations); exp != recv {
+		t.Errorf("expected %d workload(s) to be placed before the deadline, but got: %d", exp, recv)
+	}
+
+	pending := 0
+	for _, wl := range state.workloads {
+		if wl.GetStatus() == StatusInit {
+			pending++
+		}
+	}
+
+	if exp, recv := 3, pending; exp != recv {
+		t.Errorf("expected %d workloads to still be waiting for placement, but got: %d", exp, recv)
+	}
+
+	if skipped, _ := signal.stats()["skipped"].(int64); skipped != 3 {
+		t.Errorf("expected 3 skipped calls in stats, but got: %v", signal.stats()["skipped"])
+	}
+}
+
+func TestDistributorTimedOutStats(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0", delay: 50 * time.Millisecond},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &statsSignaller{}
+	mgr := &Manager{
+		state:       state,
+		ctx:         context.TODO(),
+		signal:      signal,
+		loadTimeout: 5 * time.Millisecond,
+	}
+	mgr.distributor()
+
+	if timedOut, _ := signal.stats()["timedOut"].(int64); timedOut != 1 {
+		t.Errorf("expected 1 timed out call in stats, but got: %v", signal.stats()["timedOut"])
+	}
+}
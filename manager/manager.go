@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Manager struct {
@@ -14,8 +15,10 @@ type Manager struct {
 	ctx       context.Context
 	scheduler gocron.Scheduler
 
-	signal Signals
-	state  StateStorage
+	signal     Signals
+	signalOnce sync.Once // registers the signaller on the bus
+	state      StateStorage
+	bus        bus
 
 	// used to guarantee exclusivity between dist and rebalance
 	mainJobMu sync.Mutex
@@ -35,6 +38,49 @@ type Manager struct {
 	// distributionTimeout time.Duration
 
 	maxDelta int // Max allowed delta for workers' distributed workloads
+
+	loadTimeout   time.Duration // Max time a single Worker.Load call may take
+	unloadTimeout time.Duration // Max time a single Worker.Unload call may take
+
+	calls                *limiter
+	maxCalls             int           // Max concurrent Load/Unload calls in total
+	maxWorkerCalls       int           // Max concurrent Load/Unload calls towards a single worker
+	distributionDeadline time.Duration // Max time a distribution pass may start new calls
+
+	failuresMu  sync.Mutex
+	failures    map[string]*failure
+	retryBase   time.Duration // Initial backoff after a failed placement attempt
+	retryMax    time.Duration // Max backoff between placement attempts
+	maxAttempts int           // Failed placement attempts before a workload is quarantined
+
+	operationsMu sync.Mutex
+	operations   map[string]string // idempotency keys of pending loads and unloads
+
+	breakersMu      sync.Mutex
+	breakers        map[string]*breaker
+	breakerRatio    float64       // Failure ratio which opens a worker's breaker, 0 disables breakers
+	breakerWindow   int           // Amount of calls the failure ratio is calculated over
+	breakerCooldown time.Duration // Time an open breaker waits before probing the worker
+
+	historyMu   sync.Mutex
+	history     map[string][]Transition
+	historySize int // Max amount of status transitions kept per workload
+
+	cordonMu sync.Mutex
+	cordoned map[string]bool // workers left out of placement
+
+	registrationsMu sync.Mutex
+	registrations   map[string]registration // workers which registered themselves
+
+	started         time.Time
+	backfilled      chan struct{} // closed when the startup backfill is done
+	backfillTimeout time.Duration // Max time distribution is held back by the backfill
+
+	taskWait time.Duration // Max time RunTask waits for a workload being moved
+
+	tracerProvider trace.TracerProvider
+	originsMu      sync.Mutex
+	origins        map[string]trace.SpanContext // span each workload was added in
 }
 
 type Signals interface {
@@ -53,6 +99,8 @@ type StateStorage interface {
 
 	GetAllWorkloads(context.Context) ([]Workload, error)
 	GetWorkload(context.Context, string) (Workload, error)
+	// Adds a new workload, returning ErrWorkloadExists if one with
+	// the same id is already stored
 	AddWorkload(context.Context, Workload) error
 	UpdateWorkload(context.Context, Workload) error
 	DeleteWorkload(context.Context, Workload) error
@@ -78,6 +126,26 @@ func New(ctx context.Context, opts ...Option) (*Manager, error) {
 		cleanupMaxTime:       5 * time.Minute,
 
 		maxDelta: 5,
+
+		loadTimeout:   30 * time.Second,
+		unloadTimeout: 30 * time.Second,
+
+		maxCalls:       100,
+		maxWorkerCalls: 10,
+
+		retryBase:   10 * time.Second,
+		retryMax:    10 * time.Minute,
+		maxAttempts: 5,
+
+		breakerRatio:    0.5,
+		breakerWindow:   10,
+		breakerCooldown: time.Minute,
+
+		historySize: defaultHistorySize,
+
+		backfillTimeout: 30 * time.Second,
+
+		taskWait: 30 * time.Second,
 	}
 
 	for _, opt := range opts {
@@ -92,6 +160,18 @@ func New(ctx context.Context, opts ...Option) (*Manager, error) {
 		mgr.state = NewMemoryStore()
 	}
 
+	mgr.calls = newLimiter(mgr.maxCalls, mgr.maxWorkerCalls)
+
+	if mgr.tracerProvider != nil {
+		mgr.state = &tracedState{next: mgr.state, tracer: mgr.tracer()}
+	}
+
+	mgr.started = time.Now()
+	if mgr.backfillTimeout > 0 {
+		mgr.backfilled = make(chan struct{})
+		go mgr.backfill()
+	}
+
 	var err error
 	mgr.scheduler, err = gocron.NewScheduler(
 		gocron.WithLimitConcurrentJobs(10, gocron.LimitModeReschedule),
@@ -136,5 +216,7 @@ func New(ctx context.Context, opts ...Option) (*Manager, error) {
 
 func (m *Manager) Stop() error {
 	m.ctx.Done()
+	defer m.bus.close()
+
 	return m.scheduler.Shutdown()
 }
@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"sync"
 	"testing"
 )
 
@@ -10,6 +11,39 @@ type mockSignaller struct{}
 func (s *mockSignaller) Event(Event) {}
 func (s *mockSignaller) Error(error) {}
 
+type recordingSignaller struct {
+	mu     sync.Mutex
+	events []Event
+	errors []error
+}
+
+func (s *recordingSignaller) Event(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, e)
+}
+
+func (s *recordingSignaller) Error(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = append(s.errors, err)
+}
+
+func (s *recordingSignaller) count(t EventType) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, e := range s.events {
+		if e.Type == t {
+			n++
+		}
+	}
+	return n
+}
+
 func TestManager(t *testing.T) {
 	mgr, err := New(context.Background())
 	if err != nil {
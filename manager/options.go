@@ -2,6 +2,8 @@ package manager
 
 import (
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Option func(*Manager)
@@ -63,3 +65,103 @@ func WithMaxDelta(d int) Option {
 		m.maxDelta = d
 	}
 }
+
+// Set the max time a single Worker.Load call may take, default: 30 seconds
+func WithLoadTimeout(t time.Duration) Option {
+	return func(m *Manager) {
+		m.loadTimeout = t
+	}
+}
+
+// Set the max time a single Worker.Unload call may take, default: 30 seconds
+func WithUnloadTimeout(t time.Duration) Option {
+	return func(m *Manager) {
+		m.unloadTimeout = t
+	}
+}
+
+// Set the max amount of concurrent Load/Unload calls, 0 is unbounded. Default: 100
+func WithMaxConcurrentCalls(n int) Option {
+	return func(m *Manager) {
+		m.maxCalls = n
+	}
+}
+
+// Set the max amount of concurrent Load/Unload calls towards a single
+// worker, 0 is unbounded. Default: 10
+func WithMaxConcurrentWorkerCalls(n int) Option {
+	return func(m *Manager) {
+		m.maxWorkerCalls = n
+	}
+}
+
+// Set a deadline for a distribution pass. Calls not started before the
+// deadline are skipped until the next pass, calls in flight are bound by
+// their own timeout. Default: no deadline
+func WithDistributionDeadline(t time.Duration) Option {
+	return func(m *Manager) {
+		m.distributionDeadline = t
+	}
+}
+
+// Set the backoff between failed placement attempts, it doubles for
+// every failed attempt up to max. Default: 10 seconds, max 10 minutes
+func WithRetryBackoff(base, max time.Duration) Option {
+	return func(m *Manager) {
+		m.retryBase = base
+		m.retryMax = max
+	}
+}
+
+// Set the amount of failed placement attempts before a workload is
+// quarantined, 0 retries forever. Default: 5
+func WithMaxAttempts(n int) Option {
+	return func(m *Manager) {
+		m.maxAttempts = n
+	}
+}
+
+// Configure the per worker circuit breakers. A worker's breaker opens when
+// the failure ratio of its last window calls reaches ratio, and probes the
+// worker after cooldown. A ratio of 0 disables the breakers.
+// Default: ratio 0.5, window 10, cooldown 1 minute
+func WithCircuitBreaker(ratio float64, window int, cooldown time.Duration) Option {
+	return func(m *Manager) {
+		m.breakerRatio = ratio
+		m.breakerWindow = max(window, 1)
+		m.breakerCooldown = cooldown
+	}
+}
+
+// Set the amount of status transitions kept per workload, default: 32
+func WithHistorySize(n int) Option {
+	return func(m *Manager) {
+		m.historySize = n
+	}
+}
+
+// Set the tracer provider used for spans around jobs, state and
+// worker calls, default: none
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(m *Manager) {
+		m.tracerProvider = tp
+	}
+}
+
+// Set the max time the first distribution pass waits for the workloads
+// of the workers already in state to be backfilled on startup, and the
+// max time a worker added later is asked for its workloads. The backfill
+// is on by default, 0 disables it. Default: 30 seconds
+func WithBackfillTimeout(t time.Duration) Option {
+	return func(m *Manager) {
+		m.backfillTimeout = t
+	}
+}
+
+// Set the max time RunTask waits for a workload which is being moved
+// between workers, default: 30 seconds
+func WithTaskWait(t time.Duration) Option {
+	return func(m *Manager) {
+		m.taskWait = t
+	}
+}
@@ -3,6 +3,8 @@ package manager
 import (
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestWithManagerID(t *testing.T) {
@@ -61,3 +63,90 @@ func TestWithCleanupMaxTime(t *testing.T) {
 		t.Errorf("expected rebalance interval to be '%s', but got '%s'", exp, recv)
 	}
 }
+
+func TestWithHistorySize(t *testing.T) {
+	mgr := &Manager{}
+	WithHistorySize(10)(mgr)
+
+	if exp, recv := 10, mgr.historySize; exp != recv {
+		t.Errorf("expected history size to be %d, but got %d", exp, recv)
+	}
+}
+
+func TestWithLoadTimeout(t *testing.T) {
+	mgr := &Manager{}
+	WithLoadTimeout(time.Second)(mgr)
+	WithUnloadTimeout(2 * time.Second)(mgr)
+
+	if exp, recv := time.Second, mgr.loadTimeout; exp != recv {
+		t.Errorf("expected load timeout to be '%s', but got '%s'", exp, recv)
+	}
+
+	if exp, recv := 2*time.Second, mgr.unloadTimeout; exp != recv {
+		t.Errorf("expected unload timeout to be '%s', but got '%s'", exp, recv)
+	}
+}
+
+func TestWithRetryBackoff(t *testing.T) {
+	mgr := &Manager{}
+	WithRetryBackoff(time.Second, time.Minute)(mgr)
+	WithMaxAttempts(3)(mgr)
+
+	if exp, recv := time.Second, mgr.retryBase; exp != recv {
+		t.Errorf("expected retry base to be '%s', but got '%s'", exp, recv)
+	}
+
+	if exp, recv := time.Minute, mgr.retryMax; exp != recv {
+		t.Errorf("expected retry max to be '%s', but got '%s'", exp, recv)
+	}
+
+	if exp, recv := 3, mgr.maxAttempts; exp != recv {
+		t.Errorf("expected max attempts to be %d, but got %d", exp, recv)
+	}
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	mgr := &Manager{}
+	WithCircuitBreaker(0.25, 0, time.Minute)(mgr)
+
+	if exp, recv := 0.25, mgr.breakerRatio; exp != recv {
+		t.Errorf("expected breaker ratio to be %f, but got %f", exp, recv)
+	}
+
+	if exp, recv := 1, mgr.breakerWindow; exp != recv {
+		t.Errorf("expected breaker window to be at least %d, but got %d", exp, recv)
+	}
+
+	if exp, recv := time.Minute, mgr.breakerCooldown; exp != recv {
+		t.Errorf("expected breaker cooldown to be '%s', but got '%s'", exp, recv)
+	}
+}
+
+func TestWithConcurrencyLimits(t *testing.T) {
+	mgr := &Manager{}
+	WithMaxConcurrentCalls(50)(mgr)
+	WithMaxConcurrentWorkerCalls(5)(mgr)
+	WithDistributionDeadline(time.Minute)(mgr)
+
+	if exp, recv := 50, mgr.maxCalls; exp != recv {
+		t.Errorf("expected max calls to be %d, but got %d", exp, recv)
+	}
+
+	if exp, recv := 5, mgr.maxWorkerCalls; exp != recv {
+		t.Errorf("expected max worker calls to be %d, but got %d", exp, recv)
+	}
+
+	if exp, recv := time.Minute, mgr.distributionDeadline; exp != recv {
+		t.Errorf("expected distribution deadline to be '%s', but got '%s'", exp, recv)
+	}
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	tp := noop.NewTracerProvider()
+	mgr := &Manager{}
+	WithTracerProvider(tp)(mgr)
+
+	if mgr.tracerProvider != tp {
+		t.Errorf("expected tracer provider to be set")
+	}
+}
@@ -0,0 +1,87 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+)
+
+// The outcome of a distribution pass, computed without calling any worker
+type Plan struct {
+	// Workloads which would be loaded, by workload id
+	Loads map[string]string `json:"loads"`
+	// Unwanted workloads which would be unloaded, by worker id
+	Unloads map[string][]string `json:"unloads"`
+	// Workloads waiting for placement without any available worker
+	Unplaced []string `json:"unplaced"`
+}
+
+// Computes what a distribution pass would do right now, without
+// changing any state. Failed and unplaced workloads the pass would
+// put back in line are planned as loads. Waits for any running distribution or
+// rebalance pass to finish first.
+func (m *Manager) Plan(ctx context.Context) (Plan, error) {
+	m.mainJobMu.Lock()
+	defer m.mainJobMu.Unlock()
+
+	plan := Plan{
+		Loads:    map[string]string{},
+		Unloads:  map[string][]string{},
+		Unplaced: []string{},
+	}
+
+	workloads, err := m.state.GetAllWorkloads(ctx)
+	if err != nil {
+		return plan, fmt.Errorf("failed to get workloads: %w", err)
+	}
+
+	wanted := make(map[string]Workload, len(workloads))
+	for _, wl := range workloads {
+		wanted[wl.GetID()] = wl
+	}
+
+	workers, err := m.state.GetAllWorkers(ctx)
+	if err != nil {
+		return plan, fmt.Errorf("failed to get workers: %w", err)
+	}
+
+	caps := m.capacities()
+	placed := map[string]bool{}
+	candidates, probes := map[string]int{}, map[string]bool{}
+	for _, w := range workers {
+		assocs, err := m.state.GetAssociations(ctx, w)
+		if err != nil {
+			return plan, fmt.Errorf("failed to get worker associations: %w", err)
+		}
+
+		load := 0
+		for _, wl := range assocs {
+			if _, ok := wanted[wl.GetID()]; !ok {
+				plan.Unloads[w.GetID()] = append(plan.Unloads[w.GetID()], wl.GetID())
+				continue
+			}
+
+			placed[wl.GetID()] = true
+			load++
+		}
+
+		if m.Cordoned(w.GetID()) || full(caps, w.GetID(), load) {
+			continue
+		}
+
+		allowed, probe := m.peek(w.GetID())
+		if !allowed {
+			continue
+		}
+
+		candidates[w.GetID()] = load
+		probes[w.GetID()] = probe
+	}
+
+	distribute := m.pending(ctx, workloads, placed, false)
+
+	loads, _, unplaced := place(distribute, candidates, probes, caps)
+	plan.Loads = loads
+	plan.Unplaced = append(plan.Unplaced, unplaced...)
+
+	return plan, nil
+}
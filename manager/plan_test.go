@@ -0,0 +1,120 @@
+package manager
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlan(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+			"worker1": &mockWorker{id: "worker1"},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0", status: StatusRunning},
+			"workload1": &mockWorkload{id: "workload1"},
+			"workload2": &mockWorkload{id: "workload2"},
+		},
+		associations: map[string]string{
+			"workload0": "worker1",
+		},
+	}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	if err := mgr.Cordon(context.TODO(), "worker0"); err != nil {
+		t.Fatalf("unexpected error when cordoning worker: %v", err)
+	}
+
+	plan, err := mgr.Plan(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error when planning: %v", err)
+	}
+
+	if exp, recv := 2, len(plan.Loads); exp != recv {
+		t.Fatalf("expected %d planned load(s), but got: %d", exp, recv)
+	}
+
+	for wl, w := range plan.Loads {
+		if w != "worker1" {
+			t.Errorf("expected '%s' to be planned on 'worker1', but got: '%s'", wl, w)
+		}
+	}
+
+	if exp, recv := 1, len(state.associations); exp != recv {
+		t.Errorf("expected the plan not to touch the state, but got %d association(s)", recv)
+	}
+
+	if exp, recv := StatusInit, state.workloads["workload1"].GetStatus(); exp != recv {
+		t.Errorf("expected status %s, but got: %s", exp, recv)
+	}
+
+	if err := mgr.Cordon(context.TODO(), "worker1"); err != nil {
+		t.Fatalf("unexpected error when cordoning worker: %v", err)
+	}
+
+	plan, err = mgr.Plan(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error when planning: %v", err)
+	}
+
+	if exp, recv := []string{"workload1", "workload2"}, plan.Unplaced; !slices.Equal(exp, recv) {
+		t.Errorf("expected unplaced %v, but got: %v", exp, recv)
+	}
+}
+
+func TestPlanRequeues(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0"},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0", status: StatusErr},
+			"workload1": &mockWorkload{id: "workload1", status: StatusRunning},
+			"workload2": &mockWorkload{id: "workload2", status: StatusDown},
+			"workload3": &mockWorkload{id: "workload3", status: StatusErr},
+		},
+		associations: map[string]string{},
+	}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+		calls:  newLimiter(0, 0),
+		failures: map[string]*failure{
+			"workload0": {attempts: 1, next: time.Now().Add(-time.Second)},
+			"workload3": {attempts: 1, next: time.Now().Add(time.Hour)},
+		},
+	}
+
+	plan, err := mgr.Plan(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error when planning: %v", err)
+	}
+
+	// the failed workload past its backoff and the unplaced ones are
+	// put back in line by the pass, the one in backoff isn't
+	exp := []string{"workload0", "workload1", "workload2"}
+	if recv := slices.Sorted(maps.Keys(plan.Loads)); !slices.Equal(exp, recv) {
+		t.Errorf("expected planned loads of %v, but got: %v", exp, recv)
+	}
+
+	if exp, recv := StatusErr, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Errorf("expected the plan not to touch the state, but got status: %s", recv)
+	}
+
+	mgr.distributor()
+
+	if recv := slices.Sorted(maps.Keys(state.associations)); !slices.Equal(exp, recv) {
+		t.Errorf("expected the pass to load %v, but got: %v", exp, recv)
+	}
+}
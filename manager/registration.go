@@ -0,0 +1,176 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidRegistration = errors.New("invalid worker registration")
+	ErrStaleSession        = errors.New("stale worker session")
+)
+
+// Describes a worker joining the manager
+type Registration struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// Max amount of workloads placed on the worker, 0 for no limit
+	Capacity int    `json:"capacity,omitempty"`
+	Version  string `json:"version,omitempty"`
+	// Generation of the worker's previous session, presented to take
+	// over its id after a restart. 0 for a worker joining anew.
+	Generation uint64 `json:"generation,omitempty"`
+	// Workloads the worker is already running, assigned to it unless the
+	// manager has them placed elsewhere
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+// The session a worker was given when registering
+type Session struct {
+	WorkerID   string    `json:"workerId"`
+	Generation uint64    `json:"generation"`
+	Registered time.Time `json:"registered"`
+	// Workloads still associated with the worker from its previous
+	// session, which it should load again
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+type registration struct {
+	Registration
+	session Session
+}
+
+// Registers a worker, assigning it a new session generation. A worker
+// id which is already in use is only accepted from a worker presenting
+// the generation of the current session, i.e. the same worker after a
+// restart, which keeps the workloads associated with it. Any other
+// worker using the id gets ErrWorkerExists. Sessions are only known
+// in memory, so a worker in state without one rejoins as well. A
+// worker joining anew is assigned the workloads it reports running,
+// including the ones its deregistration left down, so they aren't
+// placed on another worker as well.
+func (m *Manager) Register(ctx context.Context, w Worker, reg Registration) (Session, error) {
+	if reg.ID == "" || reg.ID != w.GetID() {
+		return Session{}, fmt.Errorf("%w: worker id '%s' doesn't match '%s'", ErrInvalidRegistration, w.GetID(), reg.ID)
+	}
+
+	if reg.Capacity < 0 {
+		return Session{}, fmt.Errorf("%w: negative capacity", ErrInvalidRegistration)
+	}
+
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	m.registrationsMu.Lock()
+	defer m.registrationsMu.Unlock()
+
+	if m.registrations == nil {
+		m.registrations = map[string]registration{}
+	}
+
+	s := Session{WorkerID: reg.ID, Registered: time.Now()}
+	prev, known := m.registrations[reg.ID]
+
+	existing, err := m.state.GetWorker(ctx, reg.ID)
+	rejoined := err == nil
+
+	switch {
+	case errors.Is(err, ErrWorkerNotFound):
+	case err != nil:
+		return Session{}, err
+	case known && (reg.Generation == 0 || reg.Generation != prev.session.Generation):
+		return Session{}, fmt.Errorf("%w: '%s'", ErrWorkerExists, reg.ID)
+	default:
+		// without a registration record, i.e. after a manager restart
+		// or for a worker added with AddWorker, there's no session to
+		// check against, so the worker takes over the id
+		assocs, err := m.state.GetAssociations(ctx, existing)
+		if err != nil {
+			return Session{}, err
+		}
+
+		for _, wl := range assocs {
+			s.Workloads = append(s.Workloads, wl.GetID())
+		}
+
+		slices.Sort(s.Workloads)
+	}
+
+	// generations only move forward, also across manager restarts
+	s.Generation = max(uint64(s.Registered.UnixNano()), prev.session.Generation+1)
+
+	// a rejoining worker replaces the previous session's worker
+	if err := m.state.AddWorker(ctx, w); err != nil {
+		return Session{}, err
+	}
+
+	running := reg.Workloads
+
+	reg.Labels = maps.Clone(reg.Labels)
+	reg.Workloads = nil
+	m.registrations[reg.ID] = registration{Registration: reg, session: s}
+
+	if !rejoined {
+		m.emit(ctx, NewWorkerAddedEvent(m.id, w))
+		m.adopt(ctx, w, running)
+	}
+
+	m.emit(ctx, NewWorkerRegisteredEvent(m.id, reg, s, rejoined))
+
+	return s, nil
+}
+
+// Deletes a registered worker, as long as the session is current
+func (m *Manager) Deregister(ctx context.Context, id string, generation uint64) error {
+	m.registrationsMu.Lock()
+	reg, ok := m.registrations[id]
+	m.registrationsMu.Unlock()
+
+	if !ok || reg.session.Generation != generation {
+		return fmt.Errorf("%w: '%s'", ErrStaleSession, id)
+	}
+
+	w, err := m.GetWorker(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return m.DeleteWorker(ctx, w)
+}
+
+// Returns the registration of a worker, with the generation of its
+// current session. Workers added with AddWorker have none.
+func (m *Manager) WorkerRegistration(id string) (Registration, bool) {
+	m.registrationsMu.Lock()
+	defer m.registrationsMu.Unlock()
+
+	reg, ok := m.registrations[id]
+	if !ok {
+		return Registration{}, false
+	}
+
+	r := reg.Registration
+	r.Labels = maps.Clone(r.Labels)
+	r.Generation = reg.session.Generation
+
+	return r, true
+}
+
+// Returns the capacity of every worker which registered one
+func (m *Manager) capacities() map[string]int {
+	m.registrationsMu.Lock()
+	defer m.registrationsMu.Unlock()
+
+	caps := map[string]int{}
+	for id, reg := range m.registrations {
+		if reg.Capacity > 0 {
+			caps[id] = reg.Capacity
+		}
+	}
+
+	return caps
+}
@@ -0,0 +1,286 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	state := NewMemoryStore()
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+
+	reg := Registration{ID: "worker0", Labels: map[string]string{"region": "north"}, Capacity: 10, Version: "1.2.3"}
+
+	s, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, reg)
+	if err != nil {
+		t.Fatalf("unexpected error when registering: %v", err)
+	}
+
+	if s.Generation == 0 {
+		t.Errorf("expected a session generation")
+	}
+
+	recv, ok := mgr.WorkerRegistration("worker0")
+	if !ok || recv.Labels["region"] != "north" || recv.Capacity != 10 || recv.Generation != s.Generation {
+		t.Errorf("expected the registration to be kept, but got: %+v", recv)
+	}
+
+	// another worker claiming the id is turned away
+	if _, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, reg); !errors.Is(err, ErrWorkerExists) {
+		t.Errorf("expected '%v', but got: %v", ErrWorkerExists, err)
+	}
+
+	if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker0"}); !errors.Is(err, ErrWorkerExists) {
+		t.Errorf("expected '%v', but got: %v", ErrWorkerExists, err)
+	}
+
+	if _, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, Registration{ID: "worker1"}); !errors.Is(err, ErrInvalidRegistration) {
+		t.Errorf("expected '%v', but got: %v", ErrInvalidRegistration, err)
+	}
+
+	if exp, recv := 1, signal.count(EventWorkerAdded); exp != recv {
+		t.Errorf("expected %d added event(s), but got: %d", exp, recv)
+	}
+
+	if exp, recv := 1, signal.count(EventWorkerRegistered); exp != recv {
+		t.Errorf("expected %d registered event(s), but got: %d", exp, recv)
+	}
+}
+
+func TestRegisterRejoin(t *testing.T) {
+	state := NewMemoryStore()
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	s, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, Registration{ID: "worker0"})
+	if err != nil {
+		t.Fatalf("unexpected error when registering: %v", err)
+	}
+
+	for _, id := range []string{"workload1", "workload0"} {
+		wl := &mockWorkload{id: id, status: StatusRunning}
+		state.workloads[id] = wl
+		state.associations[id] = "worker0"
+	}
+
+	// the same worker after a restart, with a new connection
+	restarted := &mockWorker{id: "worker0"}
+	rejoined, err := mgr.Register(context.TODO(), restarted, Registration{ID: "worker0", Generation: s.Generation})
+	if err != nil {
+		t.Fatalf("unexpected error when re-registering: %v", err)
+	}
+
+	if rejoined.Generation <= s.Generation {
+		t.Errorf("expected a newer generation than %d, but got: %d", s.Generation, rejoined.Generation)
+	}
+
+	if exp, recv := []string{"workload0", "workload1"}, rejoined.Workloads; !slices.Equal(exp, recv) {
+		t.Errorf("expected reclaimed workloads %v, but got: %v", exp, recv)
+	}
+
+	if w, _ := mgr.GetWorker(context.TODO(), "worker0"); w != restarted {
+		t.Errorf("expected the restarted worker to replace the previous one")
+	}
+
+	// the previous session is stale now
+	if _, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, Registration{ID: "worker0", Generation: s.Generation}); !errors.Is(err, ErrWorkerExists) {
+		t.Errorf("expected '%v', but got: %v", ErrWorkerExists, err)
+	}
+
+	if err := mgr.Deregister(context.TODO(), "worker0", s.Generation); !errors.Is(err, ErrStaleSession) {
+		t.Errorf("expected '%v', but got: %v", ErrStaleSession, err)
+	}
+
+	if err := mgr.Deregister(context.TODO(), "worker0", rejoined.Generation); err != nil {
+		t.Fatalf("unexpected error when deregistering: %v", err)
+	}
+
+	if _, ok := mgr.WorkerRegistration("worker0"); ok {
+		t.Errorf("expected the registration to be removed")
+	}
+
+	if exp, recv := 0, len(state.associations); exp != recv {
+		t.Errorf("expected %d association(s) after deregistering, but got: %d", exp, recv)
+	}
+}
+
+func TestRegisterAfterDeregister(t *testing.T) {
+	state := NewMemoryStore()
+	state.workers["worker1"] = &mockWorker{id: "worker1", onLoad: func(wl Workload) {
+		t.Errorf("expected '%s' not to be placed elsewhere", wl.GetID())
+	}}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+		calls:  newLimiter(0, 0),
+	}
+
+	running := []string{"workload0", "workload1"}
+	s, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, Registration{ID: "worker0", Workloads: running})
+	if err != nil {
+		t.Fatalf("unexpected error when registering: %v", err)
+	}
+
+	// leaves its workloads down
+	if err := mgr.Deregister(context.TODO(), "worker0", s.Generation); err != nil {
+		t.Fatalf("unexpected error when deregistering: %v", err)
+	}
+
+	if _, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, Registration{ID: "worker0", Workloads: running}); err != nil {
+		t.Fatalf("unexpected error when re-registering: %v", err)
+	}
+
+	mgr.distributor()
+
+	for _, id := range running {
+		if exp, recv := "worker0", state.associations[id]; exp != recv {
+			t.Errorf("expected '%s' to be associated back to '%s', but got: '%s'", id, exp, recv)
+		}
+
+		if exp, recv := StatusRunning, state.workloads[id].GetStatus(); exp != recv {
+			t.Errorf("expected '%s' to be %s, but got: %s", id, exp, recv)
+		}
+	}
+}
+
+func TestRegisterAfterRestart(t *testing.T) {
+	// the state outlives the manager, the registrations don't
+	state := NewMemoryStore()
+	state.workers["worker0"] = &mockWorker{id: "worker0"}
+	state.workloads["workload0"] = &mockWorkload{id: "workload0", status: StatusRunning}
+	state.associations["workload0"] = "worker0"
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: signal,
+	}
+
+	s, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, Registration{ID: "worker0", Generation: 42})
+	if err != nil {
+		t.Fatalf("unexpected error when re-registering after a restart: %v", err)
+	}
+
+	if exp, recv := []string{"workload0"}, s.Workloads; !slices.Equal(exp, recv) {
+		t.Errorf("expected reclaimed workloads %v, but got: %v", exp, recv)
+	}
+
+	if exp, recv := 0, signal.count(EventWorkerAdded); exp != recv {
+		t.Errorf("expected %d added event(s) for a rejoining worker, but got: %d", exp, recv)
+	}
+
+	// from here on the session is known again
+	if _, err := mgr.Register(context.TODO(), &mockWorker{id: "worker0"}, Registration{ID: "worker0", Generation: 42}); !errors.Is(err, ErrWorkerExists) {
+		t.Errorf("expected '%v', but got: %v", ErrWorkerExists, err)
+	}
+
+	// workers added with AddWorker can register as well
+	if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker1"}); err != nil {
+		t.Fatalf("unexpected error when adding worker: %v", err)
+	}
+
+	if _, err := mgr.Register(context.TODO(), &mockWorker{id: "worker1"}, Registration{ID: "worker1"}); err != nil {
+		t.Errorf("unexpected error when registering an added worker: %v", err)
+	}
+}
+
+func TestDistributorCapacity(t *testing.T) {
+	state := NewMemoryStore()
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	for id, capacity := range map[string]int{"worker0": 1, "worker1": 2} {
+		if _, err := mgr.Register(context.TODO(), &mockWorker{id: id}, Registration{ID: id, Capacity: capacity}); err != nil {
+			t.Fatalf("unexpected error when registering: %v", err)
+		}
+	}
+
+	for i := range 4 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id}
+	}
+
+	mgr.Distribute()
+
+	load := map[string]int{}
+	for _, w := range state.associations {
+		load[w]++
+	}
+
+	if load["worker0"] != 1 || load["worker1"] != 2 {
+		t.Errorf("expected placement within capacity, but got: %v", load)
+	}
+
+	plan, err := mgr.Plan(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error when planning: %v", err)
+	}
+
+	if exp, recv := 1, len(plan.Unplaced); exp != recv {
+		t.Errorf("expected %d unplaced workload(s), but got: %d", exp, recv)
+	}
+}
+
+func TestRebalancerCapacity(t *testing.T) {
+	state := NewMemoryStore()
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   &mockSignaller{},
+		calls:    newLimiter(0, 0),
+		maxDelta: 1,
+	}
+
+	for id, capacity := range map[string]int{"worker0": 2, "worker1": 3} {
+		if _, err := mgr.Register(context.TODO(), &mockWorker{id: id}, Registration{ID: id, Capacity: capacity}); err != nil {
+			t.Fatalf("unexpected error when registering: %v", err)
+		}
+	}
+
+	// worker0 is over its capacity, e.g. after it registered a lower one
+	for i := range 6 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id, status: StatusRunning}
+		state.associations[id] = "worker0"
+	}
+
+	mgr.Rebalance()
+
+	load := map[string]int{}
+	for _, w := range state.associations {
+		load[w]++
+	}
+
+	// only as many workloads are moved as worker1 has room for
+	if exp, recv := 3, load["worker0"]; exp != recv {
+		t.Errorf("expected worker over capacity to shed load down to %d workload(s), but got: %d", exp, recv)
+	}
+
+	mgr.Distribute()
+
+	load = map[string]int{}
+	for _, w := range state.associations {
+		load[w]++
+	}
+
+	if load["worker0"] != 3 || load["worker1"] != 3 {
+		t.Errorf("expected the moved workloads on worker1, but got: %v", load)
+	}
+}
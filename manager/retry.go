@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+var ErrNotQuarantined = errors.New("workload is not quarantined")
+
+type failure struct {
+	attempts int
+	next     time.Time
+}
+
+// Exponential backoff with jitter, the returned duration is
+// somewhere between half and the full backoff for the attempt
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+
+	if max > 0 && d > max {
+		d = max
+	}
+
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// Records a failed placement attempt for a workload, and reports
+// whether the workload has exhausted its attempts
+func (m *Manager) fail(id string) bool {
+	m.failuresMu.Lock()
+	defer m.failuresMu.Unlock()
+
+	if m.failures == nil {
+		m.failures = map[string]*failure{}
+	}
+
+	f, ok := m.failures[id]
+	if !ok {
+		f = &failure{}
+		m.failures[id] = f
+	}
+
+	f.attempts++
+	f.next = time.Now().Add(backoff(m.retryBase, m.retryMax, f.attempts))
+
+	return m.maxAttempts > 0 && f.attempts >= m.maxAttempts
+}
+
+// Clears the failure counter of a workload
+func (m *Manager) succeed(id string) {
+	m.failuresMu.Lock()
+	defer m.failuresMu.Unlock()
+
+	delete(m.failures, id)
+}
+
+// Reports whether a failed workload may be placed again. Workloads
+// which failed a placement attempt wait for their backoff, others
+// wait for the cleanup max time.
+func (m *Manager) retryDue(wl Workload) bool {
+	m.failuresMu.Lock()
+	f, ok := m.failures[wl.GetID()]
+	m.failuresMu.Unlock()
+
+	if ok {
+		return !time.Now().Before(f.next)
+	}
+
+	return time.Since(wl.LastStatusChange()) >= m.cleanupMaxTime
+}
+
+// Returns the amount of failed placement attempts of a workload
+// since it was last running
+func (m *Manager) Attempts(id string) int {
+	m.failuresMu.Lock()
+	defer m.failuresMu.Unlock()
+
+	if f, ok := m.failures[id]; ok {
+		return f.attempts
+	}
+
+	return 0
+}
+
+// Puts a quarantined workload back in line for distribution,
+// resetting its failure counter
+func (m *Manager) Requeue(ctx context.Context, id string) error {
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	wl, err := m.state.GetWorkload(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if wl.GetStatus() != StatusQuarantined {
+		return fmt.Errorf("%w: '%s' is %s", ErrNotQuarantined, id, wl.GetStatus())
+	}
+
+	if err := m.transition(ctx, wl, StatusInit, "requeued"); err != nil {
+		return err
+	}
+
+	m.succeed(id)
+
+	return m.state.UpdateWorkload(ctx, wl)
+}
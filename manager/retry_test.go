@@ -0,0 +1,165 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	cases := []struct {
+		Attempt int
+		Lo      time.Duration
+		Hi      time.Duration
+	}{
+		{Attempt: 1, Lo: 5 * time.Second, Hi: 10 * time.Second},
+		{Attempt: 2, Lo: 10 * time.Second, Hi: 20 * time.Second},
+		{Attempt: 3, Lo: 20 * time.Second, Hi: 40 * time.Second},
+		{Attempt: 10, Lo: 30 * time.Second, Hi: time.Minute},
+	}
+
+	for _, c := range cases {
+		for range 100 {
+			d := backoff(10*time.Second, time.Minute, c.Attempt)
+			if d < c.Lo || d > c.Hi {
+				t.Fatalf("expected backoff for attempt %d to be within [%s, %s], but got: %s", c.Attempt, c.Lo, c.Hi, d)
+			}
+		}
+	}
+
+	if exp, recv := time.Duration(0), backoff(0, time.Minute, 3); exp != recv {
+		t.Errorf("expected no backoff without a base, but got: %s", recv)
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &mockWorker{id: "worker0", loadErr: errors.New("unreachable")},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	signal := &recordingSignaller{}
+	mgr := &Manager{
+		state:       state,
+		ctx:         context.TODO(),
+		signal:      signal,
+		maxAttempts: 3,
+	}
+
+	// without a backoff every pass is a new attempt
+	for range 5 {
+		mgr.distributor()
+	}
+
+	wl := state.workloads["workload0"]
+	if exp, recv := StatusQuarantined, wl.GetStatus(); exp != recv {
+		t.Fatalf("expected workload to be '%s', but got: %s", exp, recv)
+	}
+
+	if exp, recv := 3, signal.count(EventWorkloadDistributedError); exp != recv {
+		t.Errorf("expected %d attempts before quarantine, but got: %d", exp, recv)
+	}
+
+	if exp, recv := 3, mgr.Attempts(wl.GetID()); exp != recv {
+		t.Errorf("expected %d recorded attempts, but got: %d", exp, recv)
+	}
+
+	// quarantined workloads are left alone by the cleanup job
+	mgr.cleanupMaxTime = 0
+	mgr.cleanup()
+
+	if exp, recv := StatusQuarantined, wl.GetStatus(); exp != recv {
+		t.Fatalf("expected workload to remain '%s' after cleanup, but got: %s", exp, recv)
+	}
+	// loads stuck until the cleanup times them out count as attempts
+	stuck := &mockWorkload{id: "workload1"}
+	state.workloads["workload1"] = stuck
+
+	for i := range 3 {
+		stuck.status = StatusDistributing
+		mgr.cleanup()
+
+		if exp, recv := i+1, mgr.Attempts("workload1"); exp != recv {
+			t.Errorf("expected %d recorded attempt(s) after a timed out load, but got: %d", exp, recv)
+		}
+	}
+
+	if exp, recv := StatusQuarantined, stuck.GetStatus(); exp != recv {
+		t.Errorf("expected workload to be '%s' after timed out loads, but got: %s", exp, recv)
+	}
+}
+
+func TestRetryAfterBackoff(t *testing.T) {
+	w := &mockWorker{id: "worker0", loadErr: errors.New("unreachable")}
+	state := &MemoryStore{
+		workers: map[string]Worker{w.GetID(): w},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	mgr := &Manager{
+		state:     state,
+		ctx:       context.TODO(),
+		signal:    &mockSignaller{},
+		retryBase: 20 * time.Millisecond,
+		retryMax:  20 * time.Millisecond,
+	}
+	mgr.distributor()
+
+	if exp, recv := StatusErr, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Fatalf("expected workload to be '%s', but got: %s", exp, recv)
+	}
+
+	w.loadErr = nil
+	time.Sleep(25 * time.Millisecond)
+	mgr.distributor()
+
+	if exp, recv := StatusRunning, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Fatalf("expected workload to be '%s' after retry, but got: %s", exp, recv)
+	}
+
+	if exp, recv := 0, mgr.Attempts("workload0"); exp != recv {
+		t.Errorf("expected attempts to be reset after success, but got: %d", recv)
+	}
+}
+
+func TestRequeue(t *testing.T) {
+	wl := &mockWorkload{id: "workload0", status: StatusQuarantined}
+	running := &mockWorkload{id: "workload1", status: StatusRunning}
+
+	mgr := &Manager{
+		state: &MemoryStore{
+			workloads: map[string]Workload{
+				wl.GetID():      wl,
+				running.GetID(): running,
+			},
+		},
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+	mgr.fail(wl.GetID())
+
+	if err := mgr.Requeue(context.TODO(), wl.GetID()); err != nil {
+		t.Fatalf("expected no error, but got: %v", err)
+	}
+
+	if exp, recv := StatusInit, wl.GetStatus(); exp != recv {
+		t.Errorf("expected requeued workload to be '%s', but got: %s", exp, recv)
+	}
+
+	if exp, recv := 0, mgr.Attempts(wl.GetID()); exp != recv {
+		t.Errorf("expected attempts to be reset, but got: %d", recv)
+	}
+
+	if err := mgr.Requeue(context.TODO(), running.GetID()); !errors.Is(err, ErrNotQuarantined) {
+		t.Errorf("expected an ErrNotQuarantined, but got: %v", err)
+	}
+}
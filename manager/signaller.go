@@ -23,11 +23,14 @@ func NewSlogSignaller(instance Slog) *SlogSignaller {
 func (s *SlogSignaller) Event(e Event) {
 	s.instance.Info(
 		"received event",
+		"id", e.ID,
 		"type", e.Type.String(),
 		"managerId", e.ManagerID,
 		"workerId", e.WorkerID,
 		"resourceId", e.ResourceID,
+		"correlationId", e.CorrelationID,
 		"extra", e.Extra,
+		"payload", e.Payload,
 	)
 }
 
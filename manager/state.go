@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 )
 
@@ -97,6 +98,10 @@ func (s *MemoryStore) AddWorkload(_ context.Context, wl Workload) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if _, ok := s.workloads[wl.GetID()]; ok {
+		return fmt.Errorf("%w: '%s'", ErrWorkloadExists, wl.GetID())
+	}
+
 	s.workloads[wl.GetID()] = wl
 	return nil
 }
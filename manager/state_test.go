@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"testing"
 )
 
@@ -189,6 +190,14 @@ func TestStateAddWorkload(t *testing.T) {
 	if exp, recv := "workload0", wl.GetID(); exp != recv {
 		t.Fatalf("expected to get '%s', but got: %s", exp, recv)
 	}
+
+	if err := state.AddWorkload(context.TODO(), &mockWorkload{id: "workload0"}); !errors.Is(err, ErrWorkloadExists) {
+		t.Errorf("expected '%v', but got: %v", ErrWorkloadExists, err)
+	}
+
+	if state.workloads["workload0"] != wl {
+		t.Errorf("expected the stored workload not to be overwritten")
+	}
 }
 
 func TestStateUpdateWorkload(t *testing.T) {
@@ -1,6 +1,10 @@
 package manager
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
 
 type Status uint8
 
@@ -10,8 +14,26 @@ const (
 	StatusRunning
 	StatusDown
 	StatusErr
+	StatusDraining
+	StatusUnloading
+	StatusQuarantined
 )
 
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// Allowed transitions between workload statuses, any
+// transition not listed here is rejected by the manager
+var transitions = map[Status][]Status{
+	StatusInit:         {StatusDistributing, StatusRunning},
+	StatusDistributing: {StatusRunning, StatusErr, StatusInit},
+	StatusRunning:      {StatusDown, StatusErr, StatusDraining, StatusUnloading},
+	StatusDown:         {StatusInit},
+	StatusErr:          {StatusInit, StatusQuarantined},
+	StatusDraining:     {StatusUnloading, StatusRunning, StatusDown, StatusErr},
+	StatusUnloading:    {StatusInit, StatusRunning, StatusErr},
+	StatusQuarantined:  {StatusInit},
+}
+
 func (s Status) String() string {
 	switch s {
 	case StatusInit:
@@ -24,11 +46,22 @@ func (s Status) String() string {
 		return "down"
 	case StatusErr:
 		return "error"
+	case StatusDraining:
+		return "draining"
+	case StatusUnloading:
+		return "unloading"
+	case StatusQuarantined:
+		return "quarantined"
 	default:
 		return "invalid"
 	}
 }
 
+// Reports whether a workload in this status is allowed to move to the given status
+func (s Status) CanTransition(to Status) bool {
+	return slices.Contains(transitions[s], to)
+}
+
 func (s Status) MarshalJSON() ([]byte, error) {
 	str := s.String()
 	if str == "invalid" {
@@ -50,6 +83,12 @@ func (s *Status) UnmarshalJSON(val []byte) error {
 		*s = StatusDown
 	case `"error"`:
 		*s = StatusErr
+	case `"draining"`:
+		*s = StatusDraining
+	case `"unloading"`:
+		*s = StatusUnloading
+	case `"quarantined"`:
+		*s = StatusQuarantined
 	default:
 		return fmt.Errorf("invalid state")
 	}
@@ -12,6 +12,9 @@ func TestString(t *testing.T) {
 		StatusRunning:      "running",
 		StatusDown:         "down",
 		StatusErr:          "error",
+		StatusDraining:     "draining",
+		StatusUnloading:    "unloading",
+		StatusQuarantined:  "quarantined",
 		Status(8):          "invalid",
 	}
 
@@ -29,6 +32,9 @@ func TestMarshalJSON(t *testing.T) {
 		StatusRunning:      []byte(`"running"`),
 		StatusDown:         []byte(`"down"`),
 		StatusErr:          []byte(`"error"`),
+		StatusDraining:     []byte(`"draining"`),
+		StatusUnloading:    []byte(`"unloading"`),
+		StatusQuarantined:  []byte(`"quarantined"`),
 		Status(8):          {},
 	}
 
@@ -54,6 +60,9 @@ func TestUnmarshalJSON(t *testing.T) {
 		`"running"`:      StatusRunning,
 		`"down"`:         StatusDown,
 		`"error"`:        StatusErr,
+		`"draining"`:     StatusDraining,
+		`"unloading"`:    StatusUnloading,
+		`"quarantined"`:  StatusQuarantined,
 	}
 
 	for input, exp := range cases {
@@ -75,3 +84,35 @@ func TestUnmarshalJSON(t *testing.T) {
 		t.Errorf("expected to receive an error when deserializing an invalid state")
 	}
 }
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		From Status
+		To   Status
+		Exp  bool
+	}{
+		{From: StatusInit, To: StatusDistributing, Exp: true},
+		{From: StatusDistributing, To: StatusRunning, Exp: true},
+		{From: StatusRunning, To: StatusDown, Exp: true},
+		{From: StatusRunning, To: StatusErr, Exp: true},
+		{From: StatusDown, To: StatusInit, Exp: true},
+		{From: StatusErr, To: StatusInit, Exp: true},
+		{From: StatusRunning, To: StatusDraining, Exp: true},
+		{From: StatusDraining, To: StatusUnloading, Exp: true},
+		{From: StatusUnloading, To: StatusInit, Exp: true},
+		{From: StatusInit, To: StatusDown, Exp: false},
+		{From: StatusErr, To: StatusRunning, Exp: false},
+		{From: StatusDown, To: StatusRunning, Exp: false},
+		{From: StatusInit, To: StatusUnloading, Exp: false},
+		{From: StatusErr, To: StatusQuarantined, Exp: true},
+		{From: StatusQuarantined, To: StatusInit, Exp: true},
+		{From: StatusQuarantined, To: StatusDistributing, Exp: false},
+		{From: Status(8), To: StatusInit, Exp: false},
+	}
+
+	for _, c := range cases {
+		if recv := c.From.CanTransition(c.To); c.Exp != recv {
+			t.Errorf("expected transition from '%s' to '%s' to be %t, but got: %t", c.From, c.To, c.Exp, recv)
+		}
+	}
+}
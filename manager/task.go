@@ -0,0 +1,226 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/target"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+var (
+	ErrNotPlaced       = errors.New("workload is not placed on a worker")
+	ErrWorkerDown      = errors.New("worker is down")
+	ErrWorkerBusy      = errors.New("worker is busy")
+	ErrUnreachable     = errors.New("worker is unreachable")
+	ErrTaskTimeout     = errors.New("task timed out")
+	ErrTaskUnsupported = errors.New("worker doesn't run tasks")
+
+	// returned while a workload is being moved between workers
+	errMoving = errors.New("workload is being moved")
+)
+
+// Implemented by workers which can run tasks on their workloads
+type TaskWorker interface {
+	RunTask(ctx context.Context, workloadID string, task *worker.Task) (worker.Result, error)
+}
+
+// Interval RunTask starts polling a moving workload at, doubling up to a second
+const taskPollInterval = 50 * time.Millisecond
+
+// Runs a task on the worker a workload is placed on. A workload being
+// moved between workers, or unloaded and waiting to be placed again, is
+// waited for, up to the task wait, as long as a worker can take it.
+// Errors are ErrWorkloadNotFound, ErrNotPlaced, ErrWorkerDown,
+// ErrWorkerBusy, ErrUnreachable, ErrTaskUnsupported or ErrTaskTimeout,
+// other errors of the worker are returned wrapped, errors of the task
+// itself are set on the result. ErrWorkerBusy also matches
+// worker.ErrQueueFull. Transports wrap failures to reach a worker in
+// ErrUnreachable.
+func (m *Manager) RunTask(ctx context.Context, workloadID string, task *worker.Task) (worker.Result, error) {
+	deadline := time.Now().Add(m.taskWait)
+	interval := taskPollInterval
+
+	for {
+		w, err := m.taskWorker(ctx, workloadID)
+		if err == nil {
+			var res worker.Result
+			res, err = w.(TaskWorker).RunTask(ctx, workloadID, task)
+
+			switch {
+			case err == nil, res.Error != nil:
+				return res, err
+			case errors.Is(err, context.DeadlineExceeded), errors.Is(err, worker.ErrTaskTimeout):
+				return res, fmt.Errorf("%w: '%s' on '%s'", ErrTaskTimeout, workloadID, w.GetID())
+			case ctx.Err() != nil:
+				return res, ctx.Err()
+			case errors.Is(err, worker.ErrWorkloadNotFound):
+				// moved away from the worker since it was looked up
+				err = errMoving
+			case errors.Is(err, worker.ErrQueueFull):
+				return res, fmt.Errorf("%w: '%s': %w", ErrWorkerBusy, w.GetID(), err)
+			case errors.Is(err, ErrUnreachable):
+				return res, fmt.Errorf("failed to run task on '%s': %w", w.GetID(), err)
+			default:
+				return res, fmt.Errorf("task on '%s' failed on '%s': %w", workloadID, w.GetID(), err)
+			}
+		}
+
+		if !errors.Is(err, errMoving) {
+			return worker.Result{}, err
+		}
+
+		if time.Now().After(deadline) {
+			return worker.Result{}, fmt.Errorf("%w: '%s' is still being moved", ErrNotPlaced, workloadID)
+		}
+
+		select {
+		case <-time.After(interval):
+			interval = min(2*interval, time.Second)
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return worker.Result{}, fmt.Errorf("%w: waiting for '%s' to be placed", ErrTaskTimeout, workloadID)
+			}
+
+			return worker.Result{}, ctx.Err()
+		}
+	}
+}
+
+// Looks up the worker a workload runs on
+func (m *Manager) taskWorker(ctx context.Context, workloadID string) (Worker, error) {
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	wl, err := m.state.GetWorkload(ctx, workloadID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s := wl.GetStatus(); s {
+	case StatusRunning:
+	case StatusDistributing, StatusDraining, StatusUnloading:
+		return nil, errMoving
+	case StatusInit:
+		if m.requeued(wl.GetID()) && m.schedulable(ctx) {
+			return nil, errMoving
+		}
+
+		return nil, fmt.Errorf("%w: '%s' is %s", ErrNotPlaced, workloadID, s)
+	case StatusDown:
+		return nil, fmt.Errorf("%w: '%s' is %s", ErrWorkerDown, workloadID, s)
+	default:
+		return nil, fmt.Errorf("%w: '%s' is %s", ErrNotPlaced, workloadID, s)
+	}
+
+	w, err := m.state.GetAssociation(ctx, wl)
+	if errors.Is(err, ErrMissingAssociation) || errors.Is(err, ErrWorkerNotFound) {
+		return nil, errMoving
+	} else if err != nil {
+		return nil, err
+	}
+
+	if m.BreakerState(w.GetID()) == BreakerOpen {
+		return nil, fmt.Errorf("%w: '%s' has an open breaker", ErrWorkerDown, w.GetID())
+	}
+
+	if _, ok := w.(TaskWorker); !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrTaskUnsupported, w.GetID())
+	}
+
+	return w, nil
+}
+
+// Runs a task on every workload matching the target, routed to the
+// workers they're placed on, see worker.FanOut. Workloads are matched
+// by id, the labels of the worker they're placed on and their info when
+// they implement InfoWorkload, like the worker does. Returns
+// target.ErrNoMatch when no workload matches.
+func (m *Manager) RunTaskOn(ctx context.Context, t target.Target, task *worker.Task, opts ...worker.FanOutOption) (worker.FanOutResult, error) {
+	ids, err := m.Targets(ctx, t)
+	if err != nil {
+		return worker.FanOutResult{}, err
+	}
+
+	return worker.FanOut(ctx, ids, func(ctx context.Context, id string) (worker.Result, error) {
+		return m.RunTask(ctx, id, task)
+	}, opts...), nil
+}
+
+// Reports whether a workload waiting for distribution was placed before,
+// i.e. it was unloaded to be moved, or failed and is retried
+func (m *Manager) requeued(id string) bool {
+	if t, ok := m.lastTransition(id); ok && t.From == StatusUnloading {
+		return true
+	}
+
+	return m.Attempts(id) > 0
+}
+
+// Reports whether any worker can take a workload waiting for
+// distribution, i.e. is uncordoned and hasn't got an open breaker
+func (m *Manager) schedulable(ctx context.Context) bool {
+	workers, err := m.state.GetAllWorkers(ctx)
+	if err != nil {
+		return false
+	}
+
+	for _, w := range workers {
+		if m.Cordoned(w.GetID()) {
+			continue
+		}
+
+		// a worker past its cooldown, or probing, is admitted again
+		if allowed, probe := m.peek(w.GetID()); allowed || probe {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the sorted ids of the workloads matching a target, the way
+// RunTaskOn matches them. Returns target.ErrNoMatch when no workload
+// matches.
+func (m *Manager) Targets(ctx context.Context, t target.Target) ([]string, error) {
+	if t == nil {
+		return nil, fmt.Errorf("%w: missing target", target.ErrInvalidTarget)
+	}
+
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	wls, err := m.state.GetAllWorkloads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(wls))
+	for _, wl := range wls {
+		c := target.Candidate{Name: wl.GetID()}
+		if iw, ok := wl.(InfoWorkload); ok {
+			c.Info = iw.Info()
+		}
+
+		if w, err := m.state.GetAssociation(ctx, wl); err == nil {
+			if reg, ok := m.WorkerRegistration(w.GetID()); ok {
+				c.Labels = reg.Labels
+			}
+		}
+
+		if t.Match(c) {
+			ids = append(ids, wl.GetID())
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: '%s'", target.ErrNoMatch, t)
+	}
+
+	slices.Sort(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,355 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/target"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+type taskingWorker struct {
+	mockWorker
+	delay time.Duration
+	err   error
+}
+
+func (w *taskingWorker) RunTask(ctx context.Context, workloadID string, task *worker.Task) (worker.Result, error) {
+	select {
+	case <-time.After(w.delay):
+	case <-ctx.Done():
+		return worker.Result{}, ctx.Err()
+	}
+
+	return worker.Result{WorkerID: w.id, Hostname: workloadID, Command: task.Command}, w.err
+}
+
+var errTaskFailed = errors.New("invalid input")
+
+func TestRunTask(t *testing.T) {
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &taskingWorker{mockWorker: mockWorker{id: "worker0"}},
+			"worker1": &mockWorker{id: "worker1"},
+			"worker2": &taskingWorker{mockWorker: mockWorker{id: "worker2"}, err: fmt.Errorf("%w: connection refused", ErrUnreachable)},
+			"worker3": &taskingWorker{mockWorker: mockWorker{id: "worker3"}, delay: time.Second},
+			"worker4": &taskingWorker{mockWorker: mockWorker{id: "worker4"}, err: fmt.Errorf("%w: 'workload6'", worker.ErrQueueFull)},
+			"worker5": &taskingWorker{mockWorker: mockWorker{id: "worker5"}, err: errTaskFailed},
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0", status: StatusRunning},
+			"workload1": &mockWorkload{id: "workload1", status: StatusRunning},
+			"workload2": &mockWorkload{id: "workload2", status: StatusRunning},
+			"workload3": &mockWorkload{id: "workload3", status: StatusRunning},
+			"workload4": &mockWorkload{id: "workload4"},
+			"workload5": &mockWorkload{id: "workload5", status: StatusDown},
+			"workload6": &mockWorkload{id: "workload6", status: StatusRunning},
+			"workload7": &mockWorkload{id: "workload7", status: StatusRunning},
+		},
+		associations: map[string]string{
+			"workload0": "worker0",
+			"workload1": "worker1",
+			"workload2": "worker2",
+			"workload3": "worker3",
+			"workload6": "worker4",
+			"workload7": "worker5",
+		},
+	}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	res, err := mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "ping"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := "worker0", res.WorkerID; exp != recv {
+		t.Errorf("expected the task to run on '%s', but got: '%s'", exp, recv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+
+	cases := []struct {
+		ctx      context.Context
+		workload string
+		exp      error
+	}{
+		{context.TODO(), "workload9", ErrWorkloadNotFound},
+		{context.TODO(), "workload1", ErrTaskUnsupported},
+		{context.TODO(), "workload2", ErrUnreachable},
+		{ctx, "workload3", ErrTaskTimeout},
+		{context.TODO(), "workload4", ErrNotPlaced},
+		{context.TODO(), "workload5", ErrWorkerDown},
+		{context.TODO(), "workload6", ErrWorkerBusy},
+		{context.TODO(), "workload6", worker.ErrQueueFull},
+		{context.TODO(), "workload7", errTaskFailed},
+	}
+
+	for _, c := range cases {
+		if _, err := mgr.RunTask(c.ctx, c.workload, &worker.Task{Command: "ping"}); !errors.Is(err, c.exp) {
+			t.Errorf("expected '%v' for '%s', but got: %v", c.exp, c.workload, err)
+		}
+	}
+
+	// failures of the task aren't mistaken for a worker which is down
+	if _, err := mgr.RunTask(context.TODO(), "workload7", &worker.Task{Command: "ping"}); errors.Is(err, ErrWorkerDown) || errors.Is(err, ErrUnreachable) {
+		t.Errorf("expected the error of the task, but got: %v", err)
+	}
+}
+
+func TestRunTaskMoving(t *testing.T) {
+	wl := &mockWorkload{id: "workload0", status: StatusDistributing}
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			"worker0": &taskingWorker{mockWorker: mockWorker{id: "worker0"}},
+		},
+		workloads:    map[string]Workload{"workload0": wl},
+		associations: map[string]string{},
+	}
+
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   &mockSignaller{},
+		taskWait: 50 * time.Millisecond,
+	}
+
+	if _, err := mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "ping"}); !errors.Is(err, ErrNotPlaced) {
+		t.Errorf("expected '%v' after waiting, but got: %v", ErrNotPlaced, err)
+	}
+
+	mgr.taskWait = 5 * time.Second
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = state.UpdateWorkload(context.TODO(), &mockWorkload{id: "workload0", status: StatusRunning})
+		_ = state.Associate(context.TODO(), wl, state.workers["worker0"])
+	}()
+
+	res, err := mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "ping"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task on a moving workload: %v", err)
+	}
+
+	if exp, recv := "worker0", res.WorkerID; exp != recv {
+		t.Errorf("expected the task to run on '%s', but got: '%s'", exp, recv)
+	}
+}
+
+func TestRunTaskRebalance(t *testing.T) {
+	state := &lockingStore{MemoryStore: NewMemoryStore()}
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   &mockSignaller{},
+		calls:    newLimiter(0, 0),
+		maxDelta: 1,
+		taskWait: 2 * time.Second,
+	}
+
+	w0, w1 := &taskingWorker{mockWorker: mockWorker{id: "worker0"}}, &taskingWorker{mockWorker: mockWorker{id: "worker1"}}
+	for _, w := range []Worker{w0, w1} {
+		if err := mgr.AddWorker(context.TODO(), w); err != nil {
+			t.Fatalf("unexpected error when adding worker: %v", err)
+		}
+	}
+
+	for i := range 4 {
+		id := fmt.Sprintf("workload%d", i)
+		state.workloads[id] = &mockWorkload{id: id, status: StatusRunning}
+		state.associations[id] = "worker0"
+	}
+
+	// unloaded from worker0, waiting to be placed on worker1
+	mgr.Rebalance()
+
+	var moved string
+	for id, wl := range state.workloads {
+		if wl.GetStatus() == StatusInit {
+			moved = id
+		}
+	}
+
+	if moved == "" {
+		t.Fatalf("expected the rebalance to move a workload")
+	}
+
+	type call struct {
+		res worker.Result
+		err error
+	}
+
+	ch := make(chan call, 1)
+	go func() {
+		res, err := mgr.RunTask(context.TODO(), moved, &worker.Task{Command: "ping"})
+		ch <- call{res, err}
+	}()
+
+	// placed on worker1 while the task waits, like the next distribution does
+	time.Sleep(100 * time.Millisecond)
+	mgr.Assign(w1, state.workloads[moved])
+
+	c := <-ch
+	if c.err != nil {
+		t.Fatalf("unexpected error when running task on a moving workload: %v", c.err)
+	}
+
+	if exp, recv := "worker1", c.res.WorkerID; exp != recv {
+		t.Errorf("expected the task to run on '%s', but got: '%s'", exp, recv)
+	}
+
+	// a workload never placed isn't waited for
+	state.workloads["workload9"] = &mockWorkload{id: "workload9"}
+
+	start := time.Now()
+	if _, err := mgr.RunTask(context.TODO(), "workload9", &worker.Task{Command: "ping"}); !errors.Is(err, ErrNotPlaced) {
+		t.Errorf("expected '%v', but got: %v", ErrNotPlaced, err)
+	}
+
+	if recv := time.Since(start); recv > time.Second {
+		t.Errorf("expected an unplaced workload to fail at once, but took: %s", recv)
+	}
+}
+
+func TestRunTaskDrained(t *testing.T) {
+	state := &lockingStore{MemoryStore: NewMemoryStore()}
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   &mockSignaller{},
+		calls:    newLimiter(0, 0),
+		taskWait: 5 * time.Second,
+	}
+
+	if err := mgr.AddWorker(context.TODO(), &taskingWorker{mockWorker: mockWorker{id: "worker0"}}); err != nil {
+		t.Fatalf("unexpected error when adding worker: %v", err)
+	}
+
+	state.workloads["workload0"] = &mockWorkload{id: "workload0", status: StatusRunning}
+	state.associations["workload0"] = "worker0"
+
+	// requeued, but no worker is left to take it
+	if err := mgr.Drain(context.TODO(), "worker0"); err != nil {
+		t.Fatalf("unexpected error when draining: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "ping"}); !errors.Is(err, ErrNotPlaced) {
+		t.Errorf("expected '%v', but got: %v", ErrNotPlaced, err)
+	}
+
+	if recv := time.Since(start); recv > time.Second {
+		t.Errorf("expected a workload without a worker to take it to fail at once, but took: %s", recv)
+	}
+}
+
+func TestRunTaskOn(t *testing.T) {
+	state := NewMemoryStore()
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   &mockSignaller{},
+		taskWait: 10 * time.Millisecond,
+	}
+
+	for i, region := range []string{"north", "south"} {
+		id := fmt.Sprintf("worker%d", i)
+		reg := Registration{ID: id, Labels: map[string]string{"region": region}}
+
+		w := &taskingWorker{mockWorker: mockWorker{id: id}}
+		if _, err := mgr.Register(context.TODO(), w, reg); err != nil {
+			t.Fatalf("unexpected error when registering: %v", err)
+		}
+
+		for j := range 2 {
+			mgr.Assign(w, &mockWorkload{id: fmt.Sprintf("%s-device%d", region, j)})
+		}
+	}
+
+	// not placed, so failing
+	if err := mgr.AddWorkload(context.TODO(), &mockWorkload{id: "north-device9"}); err != nil {
+		t.Fatalf("unexpected error when adding workload: %v", err)
+	}
+
+	tgt, err := target.Parse("label:region=north or north-*")
+	if err != nil {
+		t.Fatalf("unexpected error when parsing target: %v", err)
+	}
+
+	res, err := mgr.RunTaskOn(context.TODO(), tgt, &worker.Task{Command: "ping"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := []string{"north-device0", "north-device1"}, res.Succeeded; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to succeed, but got: %v", exp, recv)
+	}
+
+	if exp, recv := []string{"north-device9"}, res.Failed; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to fail, but got: %v", exp, recv)
+	}
+
+	if err := res.Results["north-device9"].Error; !errors.Is(err, ErrNotPlaced) {
+		t.Errorf("expected '%v', but got: %v", ErrNotPlaced, err)
+	}
+
+	if exp, recv := "worker0", res.Results["north-device0"].WorkerID; exp != recv {
+		t.Errorf("expected the task to run on '%s', but got: '%s'", exp, recv)
+	}
+
+	if _, err := mgr.RunTaskOn(context.TODO(), target.List("east-device0"), &worker.Task{Command: "ping"}); !errors.Is(err, target.ErrNoMatch) {
+		t.Errorf("expected '%v', but got: %v", target.ErrNoMatch, err)
+	}
+}
+
+type infoWorkload struct {
+	mockWorkload
+	info map[string]any
+}
+
+func (wl *infoWorkload) Info() map[string]any {
+	return wl.info
+}
+
+func TestRunTaskOnInfo(t *testing.T) {
+	state := NewMemoryStore()
+	mgr := &Manager{
+		state:    state,
+		ctx:      context.TODO(),
+		signal:   &mockSignaller{},
+		taskWait: 10 * time.Millisecond,
+	}
+
+	w := &taskingWorker{mockWorker: mockWorker{id: "worker0"}}
+	if _, err := mgr.Register(context.TODO(), w, Registration{ID: "worker0", Labels: map[string]string{"region": "north"}}); err != nil {
+		t.Fatalf("unexpected error when registering: %v", err)
+	}
+
+	for i, vendor := range []string{"cisco", "juniper"} {
+		mgr.Assign(w, &infoWorkload{mockWorkload: mockWorkload{id: fmt.Sprintf("device%d", i)}, info: map[string]any{"vendor": vendor}})
+	}
+
+	// the same expression the worker matches on
+	tgt, err := target.Parse("label:region=north and label:vendor=cisco")
+	if err != nil {
+		t.Fatalf("unexpected error when parsing target: %v", err)
+	}
+
+	res, err := mgr.RunTaskOn(context.TODO(), tgt, &worker.Task{Command: "ping"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := []string{"device0"}, res.Succeeded; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to succeed, but got: %v", exp, recv)
+	}
+}
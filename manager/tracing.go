@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "github.com/Telenor-NMS-SE/ottomato/manager"
+
+// Attribute keys shared by the manager's spans
+const (
+	attrManagerID  = attribute.Key("ottomato.manager.id")
+	attrWorkerID   = attribute.Key("ottomato.worker.id")
+	attrWorkloadID = attribute.Key("ottomato.workload.id")
+)
+
+func (m *Manager) tracer() trace.Tracer {
+	if m.tracerProvider == nil {
+		return noop.NewTracerProvider().Tracer(instrumentationName)
+	}
+
+	return m.tracerProvider.Tracer(instrumentationName)
+}
+
+// Starts a span around one of the manager's jobs
+func (m *Manager) startJob(ctx context.Context, name string) (context.Context, trace.Span) {
+	return m.tracer().Start(ctx, "manager."+name, trace.WithAttributes(attrManagerID.String(m.id)))
+}
+
+// Starts a client span around a call towards a worker, linked to the
+// span the workload was added in
+func (m *Manager) startCall(ctx context.Context, op string, workerID, workloadID string) (context.Context, trace.Span) {
+	return m.tracer().Start(ctx, "worker."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithLinks(m.origin(workloadID)...),
+		trace.WithAttributes(
+			attrManagerID.String(m.id),
+			attrWorkerID.String(workerID),
+			attrWorkloadID.String(workloadID),
+		),
+	)
+}
+
+// Remembers the span a workload was added in, so later spans
+// concerning the workload can be linked to it
+func (m *Manager) remember(ctx context.Context, id string) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	m.originsMu.Lock()
+	defer m.originsMu.Unlock()
+
+	if m.origins == nil {
+		m.origins = map[string]trace.SpanContext{}
+	}
+
+	m.origins[id] = sc
+}
+
+func (m *Manager) origin(id string) []trace.Link {
+	m.originsMu.Lock()
+	defer m.originsMu.Unlock()
+
+	sc, ok := m.origins[id]
+	if !ok {
+		return nil
+	}
+
+	return []trace.Link{{SpanContext: sc}}
+}
+
+func (m *Manager) unremember(id string) {
+	m.originsMu.Lock()
+	defer m.originsMu.Unlock()
+
+	delete(m.origins, id)
+}
+
+// Ends a span, recording the error if there was one
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,136 @@
+package manager
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Wraps a StateStorage with a span around every call
+type tracedState struct {
+	next   StateStorage
+	tracer trace.Tracer
+}
+
+func (s *tracedState) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, "state."+name)
+}
+
+func (s *tracedState) Lock() {
+	s.next.Lock()
+}
+
+func (s *tracedState) Unlock() {
+	s.next.Unlock()
+}
+
+func (s *tracedState) GetAllWorkers(ctx context.Context) (workers []Worker, err error) {
+	ctx, span := s.start(ctx, "GetAllWorkers")
+	defer func() { endSpan(span, err) }()
+
+	return s.next.GetAllWorkers(ctx)
+}
+
+func (s *tracedState) GetWorker(ctx context.Context, id string) (w Worker, err error) {
+	ctx, span := s.start(ctx, "GetWorker")
+	span.SetAttributes(attrWorkerID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.GetWorker(ctx, id)
+}
+
+func (s *tracedState) AddWorker(ctx context.Context, w Worker) (err error) {
+	ctx, span := s.start(ctx, "AddWorker")
+	span.SetAttributes(attrWorkerID.String(w.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.AddWorker(ctx, w)
+}
+
+func (s *tracedState) DeleteWorker(ctx context.Context, w Worker) (err error) {
+	ctx, span := s.start(ctx, "DeleteWorker")
+	span.SetAttributes(attrWorkerID.String(w.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.DeleteWorker(ctx, w)
+}
+
+func (s *tracedState) GetAllWorkloads(ctx context.Context) (workloads []Workload, err error) {
+	ctx, span := s.start(ctx, "GetAllWorkloads")
+	defer func() { endSpan(span, err) }()
+
+	return s.next.GetAllWorkloads(ctx)
+}
+
+func (s *tracedState) GetWorkload(ctx context.Context, id string) (wl Workload, err error) {
+	ctx, span := s.start(ctx, "GetWorkload")
+	span.SetAttributes(attrWorkloadID.String(id))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.GetWorkload(ctx, id)
+}
+
+func (s *tracedState) AddWorkload(ctx context.Context, wl Workload) (err error) {
+	ctx, span := s.start(ctx, "AddWorkload")
+	span.SetAttributes(attrWorkloadID.String(wl.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.AddWorkload(ctx, wl)
+}
+
+func (s *tracedState) UpdateWorkload(ctx context.Context, wl Workload) (err error) {
+	ctx, span := s.start(ctx, "UpdateWorkload")
+	span.SetAttributes(attrWorkloadID.String(wl.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.UpdateWorkload(ctx, wl)
+}
+
+func (s *tracedState) DeleteWorkload(ctx context.Context, wl Workload) (err error) {
+	ctx, span := s.start(ctx, "DeleteWorkload")
+	span.SetAttributes(attrWorkloadID.String(wl.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.DeleteWorkload(ctx, wl)
+}
+
+func (s *tracedState) GetAssociation(ctx context.Context, wl Workload) (w Worker, err error) {
+	ctx, span := s.start(ctx, "GetAssociation")
+	span.SetAttributes(attrWorkloadID.String(wl.GetID()))
+	defer func() {
+		// a missing association is an answer, not a failure
+		if errors.Is(err, ErrMissingAssociation) {
+			span.End()
+			return
+		}
+
+		endSpan(span, err)
+	}()
+
+	return s.next.GetAssociation(ctx, wl)
+}
+
+func (s *tracedState) GetAssociations(ctx context.Context, w Worker) (workloads []Workload, err error) {
+	ctx, span := s.start(ctx, "GetAssociations")
+	span.SetAttributes(attrWorkerID.String(w.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.GetAssociations(ctx, w)
+}
+
+func (s *tracedState) Associate(ctx context.Context, wl Workload, w Worker) (err error) {
+	ctx, span := s.start(ctx, "Associate")
+	span.SetAttributes(attrWorkloadID.String(wl.GetID()), attrWorkerID.String(w.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Associate(ctx, wl, w)
+}
+
+func (s *tracedState) Disassociate(ctx context.Context, wl Workload, w Worker) (err error) {
+	ctx, span := s.start(ctx, "Disassociate")
+	span.SetAttributes(attrWorkloadID.String(wl.GetID()), attrWorkerID.String(w.GetID()))
+	defer func() { endSpan(span, err) }()
+
+	return s.next.Disassociate(ctx, wl, w)
+}
@@ -0,0 +1,119 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Records the span context a worker call was made with
+type spanWorker struct {
+	id  string
+	got trace.SpanContext
+}
+
+func (w *spanWorker) GetID() string {
+	return w.id
+}
+
+func (w *spanWorker) Load(ctx context.Context, req LoadRequest) error {
+	w.got = trace.SpanContextFromContext(ctx)
+	return nil
+}
+
+func (w *spanWorker) Unload(ctx context.Context, req UnloadRequest) error {
+	return nil
+}
+
+func findSpan(spans tracetest.SpanStubs, name string) (tracetest.SpanStub, bool) {
+	for _, s := range spans {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return tracetest.SpanStub{}, false
+}
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	w := &spanWorker{id: "worker0"}
+	state := NewMemoryStore()
+	state.workers[w.id] = w
+
+	mgr := &Manager{
+		ctx:            context.TODO(),
+		signal:         &mockSignaller{},
+		tracerProvider: tp,
+	}
+	mgr.state = &tracedState{next: state, tracer: mgr.tracer()}
+
+	if err := mgr.AddWorkload(context.TODO(), &mockWorkload{id: "workload0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	mgr.distributor()
+
+	spans := exporter.GetSpans()
+
+	added, ok := findSpan(spans, "manager.AddWorkload")
+	if !ok {
+		t.Fatalf("expected a manager.AddWorkload span, but got none")
+	}
+
+	stored, ok := findSpan(spans, "state.AddWorkload")
+	if !ok {
+		t.Fatalf("expected a state.AddWorkload span, but got none")
+	}
+
+	if exp, recv := added.SpanContext.SpanID(), stored.Parent.SpanID(); exp != recv {
+		t.Errorf("expected the state call to be a child of the manager call")
+	}
+
+	distribute, ok := findSpan(spans, "manager.distribute")
+	if !ok {
+		t.Fatalf("expected a manager.distribute span, but got none")
+	}
+
+	load, ok := findSpan(spans, "worker.Load")
+	if !ok {
+		t.Fatalf("expected a worker.Load span, but got none")
+	}
+
+	if exp, recv := distribute.SpanContext.TraceID(), load.Parent.TraceID(); exp != recv {
+		t.Errorf("expected the load call to be part of the distribution trace")
+	}
+
+	if exp, recv := trace.SpanKindClient, load.SpanKind; exp != recv {
+		t.Errorf("expected span kind '%s', but got: '%s'", exp, recv)
+	}
+
+	if len(load.Links) != 1 || load.Links[0].SpanContext.SpanID() != added.SpanContext.SpanID() {
+		t.Errorf("expected the load call to be linked to the span the workload was added in, but got: %+v", load.Links)
+	}
+
+	if exp, recv := load.SpanContext.SpanID(), w.got.SpanID(); exp != recv {
+		t.Errorf("expected the span context to be propagated to the worker")
+	}
+}
+
+func TestTracingDisabled(t *testing.T) {
+	mgr, err := New(context.TODO())
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	if _, ok := mgr.state.(*tracedState); ok {
+		t.Errorf("expected state calls not to be traced without a tracer provider")
+	}
+
+	if err := mgr.AddWorkload(context.TODO(), &mockWorkload{id: "workload0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+}
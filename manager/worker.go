@@ -3,16 +3,139 @@ package manager
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 )
 
+// A worker which workloads can be loaded on to. Calls carry a context
+// which is cancelled when the manager gives up on the call, and an
+// idempotency key which stays the same when a call is retried.
 type Worker interface {
+	GetID() string
+	Load(context.Context, LoadRequest) error
+	Unload(context.Context, UnloadRequest) error
+}
+
+// The original worker contract, without contexts or request metadata.
+// Use AdaptLegacyWorker to add such a worker to a manager.
+type LegacyWorker interface {
 	GetID() string
 	Unload(Workload) error
 	Load(Workload) error
 }
 
+type LoadRequest struct {
+	Workload       Workload
+	IdempotencyKey string
+	ManagerID      string
+	Reason         string
+}
+
+type UnloadRequest struct {
+	Workload       Workload
+	IdempotencyKey string
+	ManagerID      string
+	Reason         string
+}
+
 var ErrWorkerExists = errors.New("worker already exists")
 
+type legacyWorker struct {
+	LegacyWorker
+}
+
+// Wraps a worker implementing the original contract. As the legacy calls
+// aren't context aware, a call which is cancelled is abandoned, not stopped.
+func AdaptLegacyWorker(w LegacyWorker) Worker {
+	return &legacyWorker{w}
+}
+
+func (w *legacyWorker) Load(ctx context.Context, req LoadRequest) error {
+	return abandon(ctx, func() error { return w.LegacyWorker.Load(req.Workload) })
+}
+
+func (w *legacyWorker) Unload(ctx context.Context, req UnloadRequest) error {
+	return abandon(ctx, func() error { return w.LegacyWorker.Unload(req.Workload) })
+}
+
+// Runs fn until it returns or the context is done, whichever comes first
+func abandon(ctx context.Context, fn func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Runs a worker call with a timeout
+func (m *Manager) call(ctx context.Context, stats *callStats, timeout time.Duration, fn func(context.Context) error) (err error) {
+	defer func() { stats.observe(err) }()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return fn(ctx)
+}
+
+// Returns the key of the pending operation on a workload. The key is
+// created once per placement or unload decision, and kept for every
+// retry of it until the operation is done.
+func (m *Manager) idempotencyKey(op string, wl Workload) string {
+	m.operationsMu.Lock()
+	defer m.operationsMu.Unlock()
+
+	if m.operations == nil {
+		m.operations = map[string]string{}
+	}
+
+	k := op + "/" + wl.GetID()
+	key, ok := m.operations[k]
+	if !ok {
+		key = uuid.NewString()
+		m.operations[k] = key
+	}
+
+	return key
+}
+
+// Ends the pending operation on a workload, the next decision
+// gets a new key
+func (m *Manager) operationDone(op string, id string) {
+	m.operationsMu.Lock()
+	defer m.operationsMu.Unlock()
+
+	delete(m.operations, op+"/"+id)
+}
+
+func (m *Manager) loadRequest(wl Workload, reason string) LoadRequest {
+	return LoadRequest{
+		Workload:       wl,
+		IdempotencyKey: m.idempotencyKey("load", wl),
+		ManagerID:      m.id,
+		Reason:         reason,
+	}
+}
+
+func (m *Manager) unloadRequest(wl Workload, reason string) UnloadRequest {
+	return UnloadRequest{
+		Workload:       wl,
+		IdempotencyKey: m.idempotencyKey("unload", wl),
+		ManagerID:      m.id,
+		Reason:         reason,
+	}
+}
+
 func (m *Manager) GetAssociation(ctx context.Context, wl Workload) (Worker, error) {
 	m.state.Lock()
 	defer m.state.Unlock()
@@ -48,15 +171,28 @@ func (m *Manager) GetWorker(ctx context.Context, id string) (Worker, error) {
 	return m.state.GetWorker(ctx, id)
 }
 
+// Adds a worker, returning ErrWorkerExists if a worker with
+// the same id has already been added. Unless the backfill is
+// disabled, the worker is assigned the workloads it reports running.
 func (m *Manager) AddWorker(ctx context.Context, w Worker) error {
+	running := m.running(ctx, w)
+
 	m.state.Lock()
 	defer m.state.Unlock()
 
+	if _, err := m.state.GetWorker(ctx, w.GetID()); err == nil {
+		return fmt.Errorf("%w: '%s'", ErrWorkerExists, w.GetID())
+	} else if !errors.Is(err, ErrWorkerNotFound) {
+		return err
+	}
+
 	if err := m.state.AddWorker(ctx, w); err != nil {
 		return err
 	}
 
-	m.signal.Event(NewWorkerAddedEvent(m.id, w))
+	m.emit(ctx, NewWorkerAddedEvent(m.id, w))
+	m.adopt(ctx, w, running)
+
 	return nil
 }
 
@@ -73,7 +209,19 @@ func (m *Manager) DeleteWorker(ctx context.Context, w Worker) error {
 		if err := m.state.Disassociate(ctx, wl, w); err != nil {
 			return err
 		}
-		wl.SetStatus(StatusInit)
+		// running workloads stay down until the distributor requeues
+		// them, calls in flight to the worker are given up on
+		switch s := wl.GetStatus(); {
+		case s.CanTransition(StatusDown):
+			if err := m.transition(ctx, wl, StatusDown, "worker "+w.GetID()+" deleted"); err != nil {
+				return err
+			}
+		case s == StatusDistributing, s == StatusUnloading:
+			if err := m.transition(ctx, wl, StatusInit, "requeued after worker deletion"); err != nil {
+				m.emitError(ctx, err)
+			}
+		}
+
 		if err := m.state.UpdateWorkload(ctx, wl); err != nil {
 			return err
 		}
@@ -83,6 +231,20 @@ func (m *Manager) DeleteWorker(ctx context.Context, w Worker) error {
 		return err
 	}
 
-	m.signal.Event(NewWorkerDeletedEvent(m.id, w))
+	m.breakersMu.Lock()
+	delete(m.breakers, w.GetID())
+	m.breakersMu.Unlock()
+
+	m.registrationsMu.Lock()
+	delete(m.registrations, w.GetID())
+	m.registrationsMu.Unlock()
+
+	m.cordonMu.Lock()
+	delete(m.cordoned, w.GetID())
+	m.cordonMu.Unlock()
+
+	m.calls.forget(w.GetID())
+
+	m.emit(ctx, NewWorkerDeletedEvent(m.id, w))
 	return nil
 }
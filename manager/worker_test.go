@@ -2,24 +2,58 @@ package manager
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type mockWorker struct {
 	id  string
 	mgr *Manager
+
+	delay     time.Duration
+	loadErr   error
+	unloadErr error
+	onLoad    func(Workload)
+	unloads   atomic.Int32
+
+	inflight atomic.Int32
+	peak     atomic.Int32
 }
 
 func (w *mockWorker) GetID() string {
 	return w.id
 }
 
-func (w *mockWorker) Unload(wl Workload) error {
-	return nil
+func (w *mockWorker) Unload(ctx context.Context, req UnloadRequest) error {
+	w.unloads.Add(1)
+	return w.unloadErr
 }
 
-func (w *mockWorker) Load(wl Workload) error {
-	return nil
+func (w *mockWorker) Load(ctx context.Context, req LoadRequest) error {
+	if w.onLoad != nil {
+		w.onLoad(req.Workload)
+	}
+
+	n := w.inflight.Add(1)
+	defer w.inflight.Add(-1)
+
+	for {
+		p := w.peak.Load()
+		if n <= p || w.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(w.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return w.loadErr
 }
 
 func TestAddWorker(t *testing.T) {
@@ -70,7 +104,6 @@ func TestGetWorker(t *testing.T) {
 	}
 }
 
-/*
 func TestAddDuplicateWorker(t *testing.T) {
 	state := &MemoryStore{
 		workers: map[string]Worker{
@@ -78,16 +111,18 @@ func TestAddDuplicateWorker(t *testing.T) {
 		},
 	}
 	manager := Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
 	}
 	worker := mockWorker{id: "test"}
 
-	manager.AddWorker(&worker)
+	err := manager.AddWorker(context.TODO(), &worker)
 
 	if !errors.Is(err, ErrWorkerExists) {
 		t.Fatalf("expected to get '%v' error, but got: %v", ErrWorkerExists, err)
 	}
 }
-*/
 
 func TestDeleteWorker(t *testing.T) {
 	state := &MemoryStore{
@@ -105,12 +140,23 @@ func TestDeleteWorker(t *testing.T) {
 		state:  state,
 		ctx:    context.TODO(),
 		signal: &mockSignaller{},
+		calls:  newLimiter(0, 1),
 	}
 
+	release, err := manager.calls.acquire(context.TODO(), "test", nil)
+	if err != nil {
+		t.Fatalf("unexpected error when acquiring a slot: %v", err)
+	}
+	release()
+
 	if err := manager.DeleteWorker(context.TODO(), &mockWorker{id: "test"}); err != nil {
 		t.Fatalf("unexpected error when deleting worker: %v", err)
 	}
 
+	if len(manager.calls.perWorker) > 0 {
+		t.Errorf("expected the call slots of the worker to be dropped")
+	}
+
 	if len(state.workers) > 0 {
 		t.Fatalf("expected worker count to be exactly 0, but got: %d", len(state.workers))
 	}
@@ -120,6 +166,52 @@ func TestDeleteWorker(t *testing.T) {
 	}
 }
 
+func TestDeleteWorkerDown(t *testing.T) {
+	state := NewMemoryStore()
+	state.workers["worker0"] = &mockWorker{id: "worker0"}
+	state.workloads["workload0"] = &mockWorkload{id: "workload0", status: StatusRunning}
+	state.associations["workload0"] = "worker0"
+
+	// lost its association, e.g. in a store which was restored
+	state.workloads["workload1"] = &mockWorkload{id: "workload1", status: StatusRunning}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+		calls:  newLimiter(0, 0),
+	}
+
+	if err := mgr.DeleteWorker(context.TODO(), &mockWorker{id: "worker0"}); err != nil {
+		t.Fatalf("unexpected error when deleting worker: %v", err)
+	}
+
+	if exp, recv := StatusDown, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Errorf("expected the workload to be %s until it's requeued, but got: %s", exp, recv)
+	}
+
+	if err := mgr.AddWorker(context.TODO(), &mockWorker{id: "worker1"}); err != nil {
+		t.Fatalf("unexpected error when adding worker: %v", err)
+	}
+
+	mgr.Distribute()
+
+	for _, id := range []string{"workload0", "workload1"} {
+		if exp, recv := StatusRunning, state.workloads[id].GetStatus(); exp != recv {
+			t.Errorf("expected '%s' to be %s, but got: %s", id, exp, recv)
+		}
+
+		if exp, recv := "worker1", state.associations[id]; exp != recv {
+			t.Errorf("expected '%s' on '%s', but got: '%s'", id, exp, recv)
+		}
+	}
+
+	history, _ := mgr.WorkloadHistory(context.TODO(), "workload0")
+	if len(history) < 3 || history[0].To != StatusDown || history[1].To != StatusInit {
+		t.Errorf("expected the workload to go down and be requeued, but got: %+v", history)
+	}
+}
+
 func TestGetWorkers(t *testing.T) {
 	state := &MemoryStore{
 		workers: map[string]Worker{
@@ -149,3 +241,221 @@ func TestGetWorkers(t *testing.T) {
 		t.Fatalf("expected the one worker to be 'test', but got: %s", workers[0].GetID())
 	}
 }
+
+type mockLegacyWorker struct {
+	id    string
+	delay time.Duration
+
+	mu     sync.Mutex
+	loaded []string
+}
+
+func (w *mockLegacyWorker) GetID() string {
+	return w.id
+}
+
+func (w *mockLegacyWorker) Unload(wl Workload) error {
+	return nil
+}
+
+func (w *mockLegacyWorker) Load(wl Workload) error {
+	time.Sleep(w.delay)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.loaded = append(w.loaded, wl.GetID())
+	return nil
+}
+
+func TestAdaptLegacyWorker(t *testing.T) {
+	legacy := &mockLegacyWorker{id: "worker0"}
+	w := AdaptLegacyWorker(legacy)
+
+	if exp, recv := legacy.GetID(), w.GetID(); exp != recv {
+		t.Fatalf("expected adapted worker to have id '%s', but got: %s", exp, recv)
+	}
+
+	if err := w.Load(context.TODO(), LoadRequest{Workload: &mockWorkload{id: "workload0"}}); err != nil {
+		t.Fatalf("unexpected error when loading through the adapter: %v", err)
+	}
+
+	if exp, recv := 1, len(legacy.loaded); exp != recv {
+		t.Fatalf("expected %d workload(s) to be loaded, but got: %d", exp, recv)
+	}
+
+	legacy.delay = time.Second
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	err := w.Load(ctx, LoadRequest{Workload: &mockWorkload{id: "workload1"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a hung legacy call to be abandoned, but got: %v", err)
+	}
+}
+
+func TestDistributeLegacyWorker(t *testing.T) {
+	legacy := &mockLegacyWorker{id: "worker0"}
+	state := &MemoryStore{
+		workers: map[string]Worker{
+			legacy.GetID(): AdaptLegacyWorker(legacy),
+		},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	mgr := &Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+	mgr.distributor()
+
+	if exp, recv := StatusRunning, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Errorf("expected workload to be '%s' on a legacy worker, but got: %s", exp, recv)
+	}
+}
+
+type capturingWorker struct {
+	mockWorker
+
+	mu       sync.Mutex
+	fail     int // amount of loads failing before they succeed
+	requests []LoadRequest
+	unloads  []UnloadRequest
+}
+
+func (w *capturingWorker) Load(ctx context.Context, req LoadRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.requests = append(w.requests, req)
+	if len(w.requests) <= w.fail {
+		return errors.New("load failed")
+	}
+
+	return nil
+}
+
+func (w *capturingWorker) Unload(ctx context.Context, req UnloadRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.unloads = append(w.unloads, req)
+	return nil
+}
+
+func TestLoadRequest(t *testing.T) {
+	w := &capturingWorker{mockWorker: mockWorker{id: "worker0"}}
+	state := &MemoryStore{
+		workers: map[string]Worker{w.GetID(): w},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	mgr := &Manager{
+		id:     "manager0",
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+	mgr.distributor()
+
+	if exp, recv := 1, len(w.requests); exp != recv {
+		t.Fatalf("expected %d load request(s), but got: %d", exp, recv)
+	}
+
+	req := w.requests[0]
+	if exp, recv := "manager0", req.ManagerID; exp != recv {
+		t.Errorf("expected request from manager '%s', but got: %s", exp, recv)
+	}
+
+	if req.IdempotencyKey == "" || req.Reason == "" {
+		t.Errorf("expected request to carry an idempotency key and reason, but got: %+v", req)
+	}
+}
+
+func TestIdempotencyKeyRetriedLoad(t *testing.T) {
+	w := &capturingWorker{mockWorker: mockWorker{id: "worker0"}, fail: 1}
+	state := &MemoryStore{
+		workers: map[string]Worker{w.GetID(): w},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0"},
+		},
+		associations: map[string]string{},
+	}
+
+	mgr := &Manager{
+		id:     "manager0",
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+	}
+
+	// the first attempt fails, and is retried right away on the next pass
+	mgr.distributor()
+	mgr.distributor()
+
+	if exp, recv := 2, len(w.requests); exp != recv {
+		t.Fatalf("expected %d load request(s), but got: %d", exp, recv)
+	}
+
+	if w.requests[0].IdempotencyKey != w.requests[1].IdempotencyKey {
+		t.Errorf("expected a retried load to keep its key, but got: %s and %s", w.requests[0].IdempotencyKey, w.requests[1].IdempotencyKey)
+	}
+
+	if exp, recv := StatusRunning, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Fatalf("expected workload to be '%s', but got: %s", exp, recv)
+	}
+
+	// a new placement decision gets a new key
+	if key := mgr.loadRequest(state.workloads["workload0"], "distribution").IdempotencyKey; key == w.requests[0].IdempotencyKey {
+		t.Errorf("expected a new load to get a new key")
+	}
+}
+
+func TestIdempotencyKeyUnload(t *testing.T) {
+	w := &capturingWorker{mockWorker: mockWorker{id: "worker0"}}
+	wl := &mockWorkload{id: "workload0", status: StatusRunning}
+	state := &MemoryStore{
+		workers:      map[string]Worker{w.GetID(): w},
+		workloads:    map[string]Workload{wl.GetID(): wl},
+		associations: map[string]string{wl.GetID(): w.GetID()},
+	}
+
+	mgr := &Manager{
+		id:     "manager0",
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+		calls:  newLimiter(0, 0),
+	}
+
+	for range 2 {
+		wl.SetStatus(StatusRunning)
+		if err := mgr.unload(context.TODO(), w, wl, "rebalance"); err != nil {
+			t.Fatalf("unexpected error when unloading: %v", err)
+		}
+	}
+
+	if exp, recv := 2, len(w.unloads); exp != recv {
+		t.Fatalf("expected %d unload request(s), but got: %d", exp, recv)
+	}
+
+	if w.unloads[0].IdempotencyKey == w.unloads[1].IdempotencyKey {
+		t.Errorf("expected separate unloads to get different keys, but both got: %s", w.unloads[0].IdempotencyKey)
+	}
+
+	// workloads unloaded as unwanted have no status change to go by
+	unwanted := &workload{id: "workload1"}
+	key := mgr.unloadRequest(unwanted, "unwanted").IdempotencyKey
+	mgr.operationDone("unload", unwanted.GetID())
+
+	if key == mgr.unloadRequest(unwanted, "unwanted").IdempotencyKey {
+		t.Errorf("expected separate unwanted unloads to get different keys")
+	}
+}
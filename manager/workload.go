@@ -3,7 +3,10 @@ package manager
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Workload interface {
@@ -13,12 +16,28 @@ type Workload interface {
 	LastStatusChange() time.Time
 }
 
+// Implemented by workloads which carry the info their workload reports
+// on the worker, targets match on it like the worker does
+type InfoWorkload interface {
+	Info() map[string]any
+}
+
 type workload struct {
 	id     string
 	status Status
 	change time.Time
 }
 
+// Creates a workload waiting for distribution, for callers which
+// don't bring their own Workload implementation
+func NewWorkload(id string) Workload {
+	return &workload{
+		id:     id,
+		status: StatusInit,
+		change: time.Now(),
+	}
+}
+
 func (wl *workload) GetID() string {
 	return wl.id
 }
@@ -38,6 +57,29 @@ func (wl *workload) LastStatusChange() time.Time {
 
 var ErrWorkloadExists = errors.New("workload already exists")
 
+// Unloads a workload being deleted from its worker. A running workload
+// goes through unloading, a workload in any other status is let go of
+// without touching its status, as it's deleted right after.
+func (m *Manager) unloadDeleted(ctx context.Context, w Worker, wl Workload) error {
+	if wl.GetStatus() == StatusRunning {
+		return m.unload(ctx, w, wl, "delete")
+	}
+
+	release, err := m.calls.acquire(ctx, w.GetID(), nil)
+	if err != nil {
+		return fmt.Errorf("%w '%s' from '%s': %w", ErrUnloadFailed, wl.GetID(), w.GetID(), err)
+	}
+
+	_, err = m.unloadCall(ctx, w, wl, "delete")
+	release()
+
+	if err != nil {
+		return fmt.Errorf("%w '%s' from '%s': %w", ErrUnloadFailed, wl.GetID(), w.GetID(), err)
+	}
+
+	return m.state.Disassociate(ctx, wl, w)
+}
+
 func (m *Manager) Workloads(ctx context.Context) ([]Workload, error) {
 	m.state.Lock()
 	defer m.state.Unlock()
@@ -52,7 +94,13 @@ func (m *Manager) GetWorkload(ctx context.Context, id string) (Workload, error)
 	return m.state.GetWorkload(ctx, id)
 }
 
-func (m *Manager) AddWorkload(ctx context.Context, wl Workload) error {
+func (m *Manager) AddWorkload(ctx context.Context, wl Workload) (err error) {
+	ctx, span := m.tracer().Start(ctx, "manager.AddWorkload", trace.WithAttributes(
+		attrManagerID.String(m.id),
+		attrWorkloadID.String(wl.GetID()),
+	))
+	defer func() { endSpan(span, err) }()
+
 	m.state.Lock()
 	defer m.state.Unlock()
 
@@ -60,27 +108,49 @@ func (m *Manager) AddWorkload(ctx context.Context, wl Workload) error {
 		return err
 	}
 
-	m.signal.Event(NewWorkloadAddedEvent(m.id, wl))
+	m.remember(ctx, wl.GetID())
+
+	m.emit(ctx, NewWorkloadAddedEvent(m.id, wl))
 	return nil
 }
 
-func (m *Manager) DeleteWorkload(ctx context.Context, wl Workload) error {
+// Returned when a worker fails to let go of a workload
+var ErrUnloadFailed = errors.New("failed to unload workload")
+
+// Deletes a workload, unloading it from its worker first. A workload
+// the worker fails to unload is kept, and ErrUnloadFailed returned.
+func (m *Manager) DeleteWorkload(ctx context.Context, wl Workload) (err error) {
+	ctx, span := m.tracer().Start(ctx, "manager.DeleteWorkload", trace.WithAttributes(
+		attrManagerID.String(m.id),
+		attrWorkloadID.String(wl.GetID()),
+	))
+	defer func() { endSpan(span, err) }()
+
 	m.state.Lock()
 	defer m.state.Unlock()
 
+	// workloads waiting for distribution have no association
 	w, err := m.state.GetAssociation(ctx, wl)
-	if err != nil {
-		return err
-	}
-
-	if err := m.state.Disassociate(ctx, wl, w); err != nil {
+	switch {
+	case errors.Is(err, ErrMissingAssociation):
+	case err != nil:
 		return err
+	default:
+		if err := m.unloadDeleted(ctx, w, wl); err != nil {
+			return err
+		}
 	}
 
 	if err := m.state.DeleteWorkload(ctx, wl); err != nil {
 		return err
 	}
 
-	m.signal.Event(NewWorkloadDeletedEvent(m.id, wl))
+	m.forget(wl.GetID())
+	m.succeed(wl.GetID())
+	m.operationDone("load", wl.GetID())
+	m.operationDone("unload", wl.GetID())
+	m.unremember(wl.GetID())
+
+	m.emit(ctx, NewWorkloadDeletedEvent(m.id, wl))
 	return nil
 }
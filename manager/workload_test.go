@@ -2,6 +2,7 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -111,9 +112,63 @@ func TestDeleteWorkload(t *testing.T) {
 		signal: &mockSignaller{},
 	}
 
-	manager.DeleteWorkload(context.TODO(), &mockWorkload{id: "test"})
-	if len(state.workers) > 0 {
-		t.Fatalf("expected workload count to be exactly 0, but got: %d", len(state.workers))
+	if err := manager.DeleteWorkload(context.TODO(), &mockWorkload{id: "test"}); err != nil {
+		t.Fatalf("unexpected error when deleting an unassociated workload: %v", err)
+	}
+
+	if len(state.workloads) > 0 {
+		t.Fatalf("expected workload count to be exactly 0, but got: %d", len(state.workloads))
+	}
+}
+
+func TestDeleteWorkloadUnloads(t *testing.T) {
+	w := &mockWorker{id: "worker0", unloadErr: errors.New("connection refused")}
+	state := &MemoryStore{
+		workers: map[string]Worker{"worker0": w},
+		workloads: map[string]Workload{
+			"workload0": &mockWorkload{id: "workload0", status: StatusRunning},
+			"workload1": &mockWorkload{id: "workload1", status: StatusDown},
+		},
+		associations: map[string]string{
+			"workload0": "worker0",
+			"workload1": "worker0",
+		},
+	}
+	manager := Manager{
+		state:  state,
+		ctx:    context.TODO(),
+		signal: &mockSignaller{},
+		calls:  newLimiter(0, 0),
+	}
+
+	// a workload the worker fails to let go of is kept on it
+	err := manager.DeleteWorkload(context.TODO(), state.workloads["workload0"])
+	if !errors.Is(err, ErrUnloadFailed) {
+		t.Errorf("expected '%v', but got: %v", ErrUnloadFailed, err)
+	}
+
+	if exp, recv := StatusRunning, state.workloads["workload0"].GetStatus(); exp != recv {
+		t.Errorf("expected status '%v', but got: %v", exp, recv)
+	}
+
+	if _, ok := state.associations["workload0"]; !ok {
+		t.Errorf("expected workload0 to stay associated")
+	}
+
+	w.unloadErr = nil
+
+	for _, id := range []string{"workload0", "workload1"} {
+		if err := manager.DeleteWorkload(context.TODO(), state.workloads[id]); err != nil {
+			t.Errorf("unexpected error when deleting %s: %v", id, err)
+		}
+	}
+
+	if exp, recv := int32(3), w.unloads.Load(); exp != recv {
+		t.Errorf("expected %d unload(s), but got: %d", exp, recv)
+	}
+
+	if len(state.workloads) > 0 || len(state.associations) > 0 {
+		t.Errorf("expected no workloads or associations left, but got: %v and %v", state.workloads, state.associations)
 	}
 }
 
@@ -146,3 +201,19 @@ func TestGetWorkloads(t *testing.T) {
 		t.Fatalf("expected the one worker to be 'test', but got: %s", workloads[0].GetID())
 	}
 }
+
+func TestNewWorkload(t *testing.T) {
+	wl := NewWorkload("test")
+
+	if exp, recv := "test", wl.GetID(); exp != recv {
+		t.Errorf("expected id '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := StatusInit, wl.GetStatus(); exp != recv {
+		t.Errorf("expected status '%s', but got: '%s'", exp, recv)
+	}
+
+	if wl.LastStatusChange().IsZero() {
+		t.Errorf("expected the creation to count as a status change")
+	}
+}
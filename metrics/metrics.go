@@ -0,0 +1,232 @@
+// Package metrics exports Prometheus metrics for a manager, both from
+// the events it emits and from its state at scrape time, and for the
+// task queues of a worker.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+// The parts of a manager queried when the metrics are scraped,
+// satisfied by *manager.Manager
+type Inventory interface {
+	Workers(context.Context) ([]manager.Worker, error)
+	Workloads(context.Context) ([]manager.Workload, error)
+	GetAssosiactions(context.Context, manager.Worker) ([]manager.Workload, error)
+}
+
+// A manager.Signals implementation recording metrics from events,
+// optionally passing every event and error on to another signaller
+type Metrics struct {
+	next      manager.Signals
+	namespace string
+	timeout   time.Duration
+	buckets   []float64
+
+	registry *prometheus.Registry
+	state    *stateCollector
+
+	events        *prometheus.CounterVec
+	errors        prometheus.Counter
+	distribution  prometheus.Histogram
+	rebalance     prometheus.Histogram
+	calls         *prometheus.HistogramVec
+	callErrors    *prometheus.CounterVec
+	cleanupResets *prometheus.CounterVec
+	taskWait      prometheus.Histogram
+}
+
+type Option func(*Metrics)
+
+// Pass events and errors on to another signaller, default: none
+func WithSignaller(next manager.Signals) Option {
+	return func(m *Metrics) {
+		m.next = next
+	}
+}
+
+// Query a manager for its state when scraped, default: none
+func WithInventory(inv Inventory) Option {
+	return func(m *Metrics) {
+		m.state.inv = inv
+	}
+}
+
+// Set the namespace prefixed to every metric, default: ottomato
+func WithNamespace(ns string) Option {
+	return func(m *Metrics) {
+		m.namespace = ns
+	}
+}
+
+// Set the timeout for querying the inventory, default: 5 seconds
+func WithScrapeTimeout(t time.Duration) Option {
+	return func(m *Metrics) {
+		m.timeout = t
+	}
+}
+
+// Set the buckets of the duration histograms, default: prometheus.DefBuckets
+func WithBuckets(buckets []float64) Option {
+	return func(m *Metrics) {
+		m.buckets = buckets
+	}
+}
+
+func New(opts ...Option) *Metrics {
+	m := &Metrics{
+		namespace: "ottomato",
+		timeout:   5 * time.Second,
+		buckets:   prometheus.DefBuckets,
+		registry:  prometheus.NewRegistry(),
+		state:     &stateCollector{},
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.state.timeout = m.timeout
+	m.state.describe(m.namespace)
+
+	m.events = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "events_total",
+		Help:      "Events emitted by the manager.",
+	}, []string{"type"})
+
+	m.errors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "errors_total",
+		Help:      "Errors signalled by the manager.",
+	})
+
+	m.distribution = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "distribution_duration_seconds",
+		Help:      "Duration of distribution passes.",
+		Buckets:   m.buckets,
+	})
+
+	m.rebalance = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "rebalance_duration_seconds",
+		Help:      "Duration of rebalance passes.",
+		Buckets:   m.buckets,
+	})
+
+	m.calls = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "worker_call_duration_seconds",
+		Help:      "Duration of Load and Unload calls towards workers.",
+		Buckets:   m.buckets,
+	}, []string{"op"})
+
+	m.callErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "worker_call_errors_total",
+		Help:      "Failed Load and Unload calls towards workers.",
+	}, []string{"op"})
+
+	m.cleanupResets = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Name:      "cleanup_resets_total",
+		Help:      "Workloads reset by the cleanup job.",
+	}, []string{"from", "to"})
+
+	m.taskWait = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: m.namespace,
+		Name:      "task_queue_wait_seconds",
+		Help:      "Time tasks waited in a workload's task queue on a worker.",
+		Buckets:   m.buckets,
+	})
+
+	m.registry.MustRegister(
+		m.state,
+		m.events,
+		m.errors,
+		m.distribution,
+		m.rebalance,
+		m.calls,
+		m.callErrors,
+		m.cleanupResets,
+		m.taskWait,
+	)
+
+	return m
+}
+
+// Query a manager for its state when scraped, for managers created
+// after the metrics they signal to
+func (m *Metrics) SetInventory(inv Inventory) {
+	m.state.mu.Lock()
+	defer m.state.mu.Unlock()
+
+	m.state.inv = inv
+}
+
+// Serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Register additional collectors to be served by the handler
+func (m *Metrics) Registerer() prometheus.Registerer {
+	return m.registry
+}
+
+func (m *Metrics) Event(e manager.Event) {
+	m.events.WithLabelValues(e.Type.String()).Inc()
+
+	switch p := e.Payload.(type) {
+	case manager.DistributionFinishedPayload:
+		m.distribution.Observe(p.Duration.Seconds())
+	case manager.RebalanceFinishedPayload:
+		m.rebalance.Observe(p.Duration.Seconds())
+	case manager.DistributedPayload:
+		m.calls.WithLabelValues("load").Observe(p.Duration.Seconds())
+	case manager.DistributionErrorPayload:
+		m.calls.WithLabelValues("load").Observe(p.Duration.Seconds())
+		m.callErrors.WithLabelValues("load").Inc()
+	case manager.UnloadedPayload:
+		m.calls.WithLabelValues("unload").Observe(p.Duration.Seconds())
+	case manager.UnloadErrorPayload:
+		m.calls.WithLabelValues("unload").Observe(p.Duration.Seconds())
+		m.callErrors.WithLabelValues("unload").Inc()
+	case manager.CleanupResetPayload:
+		m.cleanupResets.WithLabelValues(p.From.String(), p.To.String()).Inc()
+	}
+
+	if m.next != nil {
+		m.next.Event(e)
+	}
+}
+
+// Records the time a task waited in its workload's task queue, see
+// worker.WithTaskWaitCallback
+func (m *Metrics) TaskWait(_ string, wait time.Duration) {
+	m.taskWait.Observe(wait.Seconds())
+}
+
+func (m *Metrics) Error(err error) {
+	m.errors.Inc()
+
+	if m.next != nil {
+		m.next.Error(err)
+	}
+}
+
+// Passes envelopes on to the wrapped signaller, if it takes them
+func (m *Metrics) Envelope(e event.Envelope) {
+	if s, ok := m.next.(manager.EnvelopeSignals); ok {
+		s.Envelope(e)
+	}
+}
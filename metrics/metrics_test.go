@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+type mockWorker struct {
+	id string
+}
+
+func (w *mockWorker) GetID() string {
+	return w.id
+}
+
+func (w *mockWorker) Load(context.Context, manager.LoadRequest) error {
+	return nil
+}
+
+func (w *mockWorker) Unload(context.Context, manager.UnloadRequest) error {
+	return nil
+}
+
+type mockWorkload struct {
+	mu     sync.Mutex
+	id     string
+	status manager.Status
+	change time.Time
+}
+
+func (wl *mockWorkload) GetID() string {
+	return wl.id
+}
+
+func (wl *mockWorkload) GetStatus() manager.Status {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	return wl.status
+}
+
+func (wl *mockWorkload) SetStatus(s manager.Status) {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	wl.status = s
+	wl.change = time.Now()
+}
+
+func (wl *mockWorkload) LastStatusChange() time.Time {
+	wl.mu.Lock()
+	defer wl.mu.Unlock()
+
+	return wl.change
+}
+
+type countingSignaller struct {
+	mu     sync.Mutex
+	events int
+	errors int
+}
+
+func (s *countingSignaller) Event(manager.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events++
+}
+
+func (s *countingSignaller) Error(error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors++
+}
+
+func TestEvent(t *testing.T) {
+	next := &countingSignaller{}
+	m := New(WithSignaller(next))
+
+	wl := &mockWorkload{id: "workload0"}
+	m.Event(manager.NewWorkloadDistributedErrorEvent("manager0", "worker0", wl, errors.New("unreachable"), time.Second))
+	m.Event(manager.NewWorkloadUnloadedEvent("manager0", "worker0", wl, "rebalance", time.Second))
+	m.Event(manager.NewWorkloadCleanupResetEvent("manager0", wl, manager.StatusErr, manager.StatusInit, time.Minute))
+	m.Event(manager.NewRebalanceFinishedEvent("manager0", 1, time.Second))
+	m.Error(errors.New("failed"))
+
+	if exp, recv := 1.0, testutil.ToFloat64(m.callErrors.WithLabelValues("load")); exp != recv {
+		t.Errorf("expected %v failed load call(s), but got: %v", exp, recv)
+	}
+
+	if exp, recv := 0.0, testutil.ToFloat64(m.callErrors.WithLabelValues("unload")); exp != recv {
+		t.Errorf("expected %v failed unload call(s), but got: %v", exp, recv)
+	}
+
+	if exp, recv := 1.0, testutil.ToFloat64(m.cleanupResets.WithLabelValues("error", "initializing")); exp != recv {
+		t.Errorf("expected %v cleanup reset(s), but got: %v", exp, recv)
+	}
+
+	if exp, recv := 1, testutil.CollectAndCount(m.rebalance); exp != recv {
+		t.Errorf("expected %d rebalance histogram, but got: %d", exp, recv)
+	}
+
+	if exp, recv := 1.0, testutil.ToFloat64(m.errors); exp != recv {
+		t.Errorf("expected %v error(s), but got: %v", exp, recv)
+	}
+
+	if next.events != 4 || next.errors != 1 {
+		t.Errorf("expected events and errors to be passed on, but got: %d events and %d errors", next.events, next.errors)
+	}
+}
+
+func TestScrape(t *testing.T) {
+	m := New(WithSignaller(&countingSignaller{}))
+
+	mgr, err := manager.New(
+		context.Background(),
+		manager.WithSignaller(m),
+		manager.WithDistributorInterval(10*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	m.SetInventory(mgr)
+
+	ctx := context.Background()
+	if err := mgr.AddWorker(ctx, &mockWorker{id: "worker0"}); err != nil {
+		t.Fatalf("failed to add worker: %v", err)
+	}
+
+	for _, id := range []string{"workload0", "workload1", "workload2"} {
+		if err := mgr.AddWorkload(ctx, &mockWorkload{id: id, change: time.Now()}); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+
+	exp := []string{
+		`ottomato_workloads{status="running"} 3`,
+		`ottomato_worker_workloads{worker="worker0"} 3`,
+		`ottomato_worker_call_duration_seconds_count{op="load"} 3`,
+		`ottomato_distribution_duration_seconds_count`,
+		`ottomato_state_up 1`,
+	}
+
+	var body string
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		body = scrape(t, srv.URL)
+		if containsAll(body, exp) {
+			return
+		}
+	}
+
+	for _, line := range exp {
+		if !strings.Contains(body, line) {
+			t.Errorf("expected scrape to contain '%s'", line)
+		}
+	}
+}
+
+func scrape(t *testing.T, url string) string {
+	t.Helper()
+
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("failed to scrape metrics: %v", err)
+	}
+	defer res.Body.Close()
+
+	if exp, recv := http.StatusOK, res.StatusCode; exp != recv {
+		t.Fatalf("expected status %d, but got: %d", exp, recv)
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %v", err)
+	}
+
+	return string(b)
+}
+
+func containsAll(s string, subs []string) bool {
+	for _, sub := range subs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestTaskWait(t *testing.T) {
+	m := New()
+
+	m.TaskWait("workload0", 0)
+	m.TaskWait("workload0", 2*time.Second)
+
+	if exp, recv := 1, testutil.CollectAndCount(m.taskWait); exp != recv {
+		t.Errorf("expected %d histogram, but got: %d", exp, recv)
+	}
+
+	srv := httptest.NewServer(m.Handler())
+	defer srv.Close()
+
+	if body := scrape(t, srv.URL); !strings.Contains(body, "ottomato_task_queue_wait_seconds_count 2") {
+		t.Errorf("expected 2 observed waits, but got:\n%s", body)
+	}
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+// Reports workloads per status and per worker, queried from the
+// inventory on every scrape
+type stateCollector struct {
+	mu      sync.Mutex
+	inv     Inventory
+	timeout time.Duration
+
+	workloads       *prometheus.Desc
+	workerWorkloads *prometheus.Desc
+	up              *prometheus.Desc
+}
+
+func (c *stateCollector) describe(namespace string) {
+	c.workloads = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "workloads"),
+		"Workloads per status.",
+		[]string{"status"}, nil,
+	)
+
+	c.workerWorkloads = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "worker_workloads"),
+		"Workloads associated with each worker.",
+		[]string{"worker"}, nil,
+	)
+
+	c.up = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "state_up"),
+		"Whether the last query of the manager state succeeded.",
+		nil, nil,
+	)
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.workloads
+	ch <- c.workerWorkloads
+	ch <- c.up
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	inv := c.inv
+	c.mu.Unlock()
+
+	if inv == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	up := 1.0
+	if err := c.collect(ctx, inv, ch); err != nil {
+		up = 0
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, up)
+}
+
+func (c *stateCollector) collect(ctx context.Context, inv Inventory, ch chan<- prometheus.Metric) error {
+	workloads, err := inv.Workloads(ctx)
+	if err != nil {
+		return err
+	}
+
+	// every status is reported, so a drained status drops to zero
+	statuses := map[manager.Status]int{}
+	for s := manager.StatusInit; s <= manager.StatusQuarantined; s++ {
+		statuses[s] = 0
+	}
+
+	for _, wl := range workloads {
+		statuses[wl.GetStatus()]++
+	}
+
+	for s, n := range statuses {
+		ch <- prometheus.MustNewConstMetric(c.workloads, prometheus.GaugeValue, float64(n), s.String())
+	}
+
+	workers, err := inv.Workers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range workers {
+		wls, err := inv.GetAssosiactions(ctx, w)
+		if err != nil {
+			return err
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.workerWorkloads, prometheus.GaugeValue, float64(len(wls)), w.GetID())
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package target
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidTarget = errors.New("invalid target")
+	ErrNoMatch       = errors.New("no workloads match the target")
+)
+
+// Parses a target expression. Terms are
+//
+//	glob:PATTERN   names matching a shell pattern, also a bare PATTERN
+//	re:REGEX       names matching a regular expression
+//	list:A,B,C     the given names
+//	label:KEY=VAL  a worker label or workload info value
+//	*              every workload
+//
+// combined with and, or, not and parentheses, where not binds tighter
+// than and, which binds tighter than or. Values containing spaces or
+// parentheses are quoted, e.g. re:"^(core|edge)-".
+func Parse(expr string) (Target, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	t, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("%w: unexpected '%s'", ErrInvalidTarget, tok)
+	}
+
+	return t, nil
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *parser) peek() (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) next() (string, bool) {
+	tok, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+
+	return tok, ok
+}
+
+func (p *parser) or() (Target, error) {
+	return p.binary("or", p.and, Or)
+}
+
+func (p *parser) and() (Target, error) {
+	return p.binary("and", p.unary, And)
+}
+
+func (p *parser) binary(op string, operand func() (Target, error), combine func(...Target) Target) (Target, error) {
+	t, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []Target{t}
+	for {
+		if tok, ok := p.peek(); !ok || !strings.EqualFold(tok, op) {
+			break
+		}
+
+		p.pos++
+
+		t, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		targets = append(targets, t)
+	}
+
+	if len(targets) == 1 {
+		return targets[0], nil
+	}
+
+	return combine(targets...), nil
+}
+
+func (p *parser) unary() (Target, error) {
+	tok, ok := p.next()
+	if !ok {
+		return nil, fmt.Errorf("%w: unexpected end of expression", ErrInvalidTarget)
+	}
+
+	switch {
+	case strings.EqualFold(tok, "not"):
+		t, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return Not(t), nil
+	case tok == "(":
+		t, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok, _ := p.next(); tok != ")" {
+			return nil, fmt.Errorf("%w: missing ')'", ErrInvalidTarget)
+		}
+
+		return t, nil
+	case tok == ")", strings.EqualFold(tok, "and"), strings.EqualFold(tok, "or"):
+		return nil, fmt.Errorf("%w: unexpected '%s'", ErrInvalidTarget, tok)
+	}
+
+	return term(tok)
+}
+
+func term(tok string) (Target, error) {
+	if tok == "*" {
+		return All(), nil
+	}
+
+	kind, value, ok := strings.Cut(tok, ":")
+	if !ok {
+		return Glob(unquote(tok))
+	}
+
+	value = unquote(value)
+
+	switch kind {
+	case "glob":
+		return Glob(value)
+	case "re":
+		return Regex(value)
+	case "list":
+		return List(strings.Split(value, ",")...), nil
+	case "label":
+		k, v, ok := strings.Cut(value, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("%w: label '%s' isn't KEY=VALUE", ErrInvalidTarget, value)
+		}
+
+		return Label(k, unquote(v)), nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown term '%s'", ErrInvalidTarget, kind)
+}
+
+func unquote(s string) string {
+	if v, err := strconv.Unquote(s); err == nil {
+		return v
+	}
+
+	return s
+}
+
+// Splits an expression into parentheses and words, keeping quoted
+// parts of words intact
+func tokenize(expr string) ([]string, error) {
+	var (
+		tokens []string
+		word   strings.Builder
+	)
+
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case ' ', '\t', '\n':
+			flush()
+		case '(', ')':
+			flush()
+			tokens = append(tokens, string(c))
+		case '"':
+			end := i + 1
+			for ; end < len(expr) && expr[end] != '"'; end++ {
+				if expr[end] == '\\' {
+					end++
+				}
+			}
+
+			if end >= len(expr) {
+				return nil, fmt.Errorf("%w: unterminated quote", ErrInvalidTarget)
+			}
+
+			word.WriteString(expr[i : end+1])
+			i = end
+		default:
+			word.WriteByte(c)
+		}
+	}
+
+	flush()
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidTarget)
+	}
+
+	return tokens, nil
+}
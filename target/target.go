@@ -0,0 +1,216 @@
+// Package target selects workloads to run a task on, by name, labels or
+// the info a workload reports, e.g. "all cisco devices in region north":
+//
+//	target.And(target.Label("vendor", "cisco"), target.Label("region", "north"))
+//
+// or parsed from an expression
+//
+//	target.Parse("label:vendor=cisco and label:region=north")
+package target
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A workload matched against a target
+type Candidate struct {
+	Name string
+	// Labels of the worker the workload runs on
+	Labels map[string]string
+	// Info reported by the workload
+	Info map[string]any
+}
+
+type Target interface {
+	Match(Candidate) bool
+	String() string
+}
+
+type glob string
+
+// Matches names against a shell pattern, see path.Match
+func Glob(pattern string) (Target, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTarget, err)
+	}
+
+	return glob(pattern), nil
+}
+
+func (t glob) Match(c Candidate) bool {
+	ok, _ := path.Match(string(t), c.Name)
+	return ok
+}
+
+func (t glob) String() string {
+	return "glob:" + quote(string(t))
+}
+
+type regex struct {
+	re *regexp.Regexp
+}
+
+// Matches names against a regular expression
+func Regex(expr string) (Target, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTarget, err)
+	}
+
+	return regex{re}, nil
+}
+
+func (t regex) Match(c Candidate) bool {
+	return t.re.MatchString(c.Name)
+}
+
+func (t regex) String() string {
+	return "re:" + quote(t.re.String())
+}
+
+type list []string
+
+// Matches the given names
+func List(names ...string) Target {
+	return list(slices.Clone(names))
+}
+
+func (t list) Match(c Candidate) bool {
+	return slices.Contains(t, c.Name)
+}
+
+func (t list) String() string {
+	return "list:" + quote(strings.Join(t, ","))
+}
+
+type label struct {
+	key, value string
+}
+
+// Matches a worker label, or else a workload info value, by key and value
+func Label(key, value string) Target {
+	return label{key, value}
+}
+
+func (t label) Match(c Candidate) bool {
+	if v, ok := c.Labels[t.key]; ok {
+		return v == t.value
+	}
+
+	if v, ok := c.Info[t.key]; ok {
+		return fmt.Sprint(v) == t.value
+	}
+
+	return false
+}
+
+func (t label) String() string {
+	return "label:" + quote(t.key+"="+t.value)
+}
+
+type and []Target
+
+// Matches when all targets match
+func And(targets ...Target) Target {
+	return and(targets)
+}
+
+func (t and) Match(c Candidate) bool {
+	for _, tt := range t {
+		if !tt.Match(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t and) String() string {
+	return join(t, " and ")
+}
+
+type or []Target
+
+// Matches when any of the targets match
+func Or(targets ...Target) Target {
+	return or(targets)
+}
+
+func (t or) Match(c Candidate) bool {
+	for _, tt := range t {
+		if tt.Match(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t or) String() string {
+	return join(t, " or ")
+}
+
+type not struct {
+	t Target
+}
+
+// Matches when the target doesn't
+func Not(t Target) Target {
+	return not{t}
+}
+
+func (t not) Match(c Candidate) bool {
+	return !t.t.Match(c)
+}
+
+func (t not) String() string {
+	return "not " + group(t.t)
+}
+
+type all struct{}
+
+// Matches every workload
+func All() Target {
+	return all{}
+}
+
+func (all) Match(Candidate) bool {
+	return true
+}
+
+func (all) String() string {
+	return "*"
+}
+
+func join(targets []Target, sep string) string {
+	parts := make([]string, 0, len(targets))
+	for _, t := range targets {
+		parts = append(parts, group(t))
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// Wraps compound targets in parentheses
+func group(t Target) string {
+	switch t.(type) {
+	case and, or:
+		return "(" + t.String() + ")"
+	}
+
+	return t.String()
+}
+
+// Quotes values which wouldn't parse as a single word
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n()\"") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,109 @@
+package target
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	core := Candidate{
+		Name:   "core-1",
+		Labels: map[string]string{"region": "north"},
+		Info:   map[string]any{"vendor": "cisco", "ports": 48},
+	}
+	edge := Candidate{
+		Name:   "edge-1",
+		Labels: map[string]string{"region": "south"},
+		Info:   map[string]any{"vendor": "juniper"},
+	}
+
+	re, err := Regex("^(core|edge)-[0-9]+$")
+	if err != nil {
+		t.Fatalf("unexpected error when compiling regex: %v", err)
+	}
+
+	glob, err := Glob("core-*")
+	if err != nil {
+		t.Fatalf("unexpected error when compiling glob: %v", err)
+	}
+
+	cases := []struct {
+		target     Target
+		core, edge bool
+	}{
+		{All(), true, true},
+		{glob, true, false},
+		{re, true, true},
+		{List("edge-1", "edge-2"), false, true},
+		{Label("region", "north"), true, false},
+		{Label("vendor", "juniper"), false, true},
+		{Label("ports", "48"), true, false},
+		{And(Label("vendor", "cisco"), Label("region", "north")), true, false},
+		{Or(glob, List("edge-1")), true, true},
+		{Not(glob), false, true},
+	}
+
+	for _, c := range cases {
+		if exp, recv := c.core, c.target.Match(core); exp != recv {
+			t.Errorf("expected '%s' to match core: %t, but got: %t", c.target, exp, recv)
+		}
+
+		if exp, recv := c.edge, c.target.Match(edge); exp != recv {
+			t.Errorf("expected '%s' to match edge: %t, but got: %t", c.target, exp, recv)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	c := Candidate{
+		Name:   "core-1",
+		Labels: map[string]string{"region": "north", "site": "oslo dc"},
+		Info:   map[string]any{"vendor": "cisco"},
+	}
+
+	cases := []struct {
+		expr  string
+		match bool
+	}{
+		{"*", true},
+		{"core-*", true},
+		{"glob:edge-*", false},
+		{`re:"^(core|edge)-"`, true},
+		{"list:edge-1,core-1", true},
+		{"label:vendor=cisco and label:region=north", true},
+		{"label:vendor=cisco and not label:region=north", false},
+		{"label:region=south or label:vendor=cisco and core-*", true},
+		{"(label:region=south or label:vendor=cisco) and edge-*", false},
+		{`label:site="oslo dc"`, true},
+		{"NOT (edge-* OR list:core-2)", true},
+	}
+
+	for _, tc := range cases {
+		tgt, err := Parse(tc.expr)
+		if err != nil {
+			t.Errorf("unexpected error when parsing '%s': %v", tc.expr, err)
+			continue
+		}
+
+		if exp, recv := tc.match, tgt.Match(c); exp != recv {
+			t.Errorf("expected '%s' to match: %t, but got: %t", tc.expr, exp, recv)
+		}
+
+		// the string form parses back into the same target
+		again, err := Parse(tgt.String())
+		if err != nil {
+			t.Errorf("unexpected error when parsing '%s' back: %v", tgt, err)
+			continue
+		}
+
+		if exp, recv := tgt.String(), again.String(); exp != recv {
+			t.Errorf("expected '%s' after a round trip, but got: '%s'", exp, recv)
+		}
+	}
+
+	for _, expr := range []string{"", "and", "core-* and", "(core-*", "core-*)", "re:(", "label:vendor", "foo:bar", `re:"unterminated`, "glob:[a"} {
+		if _, err := Parse(expr); !errors.Is(err, ErrInvalidTarget) {
+			t.Errorf("expected '%v' for '%s', but got: %v", ErrInvalidTarget, expr, err)
+		}
+	}
+}
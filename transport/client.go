@@ -0,0 +1,222 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+var errRetryable = errors.New("retryable")
+
+// A remote worker reached over HTTP/JSON, implementing manager.Worker.
+// Failed calls are retried with the same idempotency key, so a retried
+// call is only carried out once by the worker.
+type Client struct {
+	id  string
+	url string
+
+	http    *http.Client
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+}
+
+type ClientOption func(*Client)
+
+// Set the HTTP client used for calls, default: a client with its own transport
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(cl *Client) {
+		cl.http = c
+	}
+}
+
+// Set the TLS config of calls, e.g. from ClientTLSConfig for mTLS
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(cl *Client) {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.TLSClientConfig = cfg
+
+		cl.http = &http.Client{Transport: t}
+	}
+}
+
+// Set the max time of a single attempt, default: 30 seconds
+func WithTimeout(t time.Duration) ClientOption {
+	return func(cl *Client) {
+		cl.timeout = t
+	}
+}
+
+// Set the retries of a failed call and the backoff before the
+// first retry, doubling for every retry, default: 3 and 200ms
+func WithRetries(n int, backoff time.Duration) ClientOption {
+	return func(cl *Client) {
+		cl.retries = n
+		cl.backoff = backoff
+	}
+}
+
+func NewClient(id, baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		id:      id,
+		url:     strings.TrimSuffix(baseURL, "/"),
+		http:    &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()},
+		timeout: 30 * time.Second,
+		retries: 3,
+		backoff: 200 * time.Millisecond,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) GetID() string {
+	return c.id
+}
+
+func (c *Client) Load(ctx context.Context, req manager.LoadRequest) error {
+	return c.call(ctx, http.MethodPost, PathLoad, LoadRequest{
+		WorkloadID:     req.Workload.GetID(),
+		IdempotencyKey: key(req.IdempotencyKey),
+		ManagerID:      req.ManagerID,
+		Reason:         req.Reason,
+	}, nil, retryable)
+}
+
+func (c *Client) Unload(ctx context.Context, req manager.UnloadRequest) error {
+	return c.call(ctx, http.MethodPost, PathUnload, UnloadRequest{
+		WorkloadID:     req.Workload.GetID(),
+		IdempotencyKey: key(req.IdempotencyKey),
+		ManagerID:      req.ManagerID,
+		Reason:         req.Reason,
+	}, nil, retryable)
+}
+
+// Runs a task on a workload of the worker. A task which ran and
+// failed is returned along with its error. Only calls which couldn't
+// reach the worker are retried, a timed out call may leave the task
+// running on the worker.
+func (c *Client) RunTask(ctx context.Context, workloadID string, task *worker.Task) (worker.Result, error) {
+	var res worker.Result
+	err := c.call(ctx, http.MethodPost, PathTasks, TaskRequest{
+		WorkloadID:     workloadID,
+		IdempotencyKey: key(""),
+		Task:           task,
+	}, &res, unreachable)
+	if err != nil {
+		return res, err
+	}
+
+	return res, res.Error
+}
+
+// Lists the workloads on the worker
+func (c *Client) Workloads(ctx context.Context) ([]string, error) {
+	var res WorkloadsResponse
+	return res.Workloads, c.call(ctx, http.MethodGet, PathWorkloads, nil, &res, retryable)
+}
+
+func key(k string) string {
+	if k == "" {
+		return uuid.NewString()
+	}
+
+	return k
+}
+
+// Reports whether a failed call may be retried, on network errors
+// and server errors
+func retryable(err error) bool {
+	return errors.Is(err, errRetryable)
+}
+
+// Reports whether a failed call never reached the worker, i.e. it
+// failed on the network without timing out
+func unreachable(err error) bool {
+	return errors.Is(err, manager.ErrUnreachable) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// Makes a call, retrying failures the retry func accepts
+func (c *Client) call(ctx context.Context, method, path string, body, v any, retry func(error) bool) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	backoff := c.backoff
+	for attempt := 0; ; attempt++ {
+		err := c.attempt(ctx, method, path, b, v)
+		if err == nil || !retry(err) || attempt >= c.retries || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, method, path string, body []byte, v any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	InjectTraceContext(ctx, req.Header)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w: %w", errRetryable, manager.ErrUnreachable, err)
+	}
+	defer res.Body.Close()
+
+	// a task which timed out isn't run again
+	if res.StatusCode >= 500 {
+		err := readError(res)
+		if errors.Is(err, worker.ErrTaskTimeout) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %w", errRetryable, err)
+	}
+
+	if res.StatusCode >= 300 {
+		return readError(res)
+	}
+
+	if v == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,319 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+type nopSignaller struct{}
+
+func (nopSignaller) Event(manager.Event) {}
+func (nopSignaller) Error(error)         {}
+
+// Runs a manager and two workers in-process, over loopback HTTP
+func TestEndToEnd(t *testing.T) {
+	mgr, err := manager.New(
+		context.TODO(),
+		manager.WithSignaller(nopSignaller{}),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	services := map[string]*Service{}
+	clients := map[string]*Client{}
+	for _, id := range []string{"worker0", "worker1"} {
+		s, _ := newService(t, id)
+
+		srv := httptest.NewServer(NewHandler(s))
+		defer srv.Close()
+
+		services[id] = s
+		clients[id] = NewClient(id, srv.URL)
+
+		if err := mgr.AddWorker(context.TODO(), clients[id]); err != nil {
+			t.Fatalf("failed to add worker: %v", err)
+		}
+	}
+
+	for _, id := range []string{"workload0", "workload1", "workload2", "workload3"} {
+		if err := mgr.AddWorkload(context.TODO(), manager.NewWorkload(id)); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	mgr.Distribute()
+
+	for id, s := range services {
+		if exp, recv := 2, len(s.Workloads()); exp != recv {
+			t.Errorf("expected %d workload(s) on '%s', but got: %d", exp, id, recv)
+		}
+	}
+
+	wl, err := mgr.GetWorkload(context.TODO(), "workload0")
+	if err != nil {
+		t.Fatalf("failed to get workload: %v", err)
+	}
+
+	w, err := mgr.GetAssociation(context.TODO(), wl)
+	if err != nil {
+		t.Fatalf("failed to get association: %v", err)
+	}
+
+	res, err := clients[w.GetID()].RunTask(context.TODO(), "workload0", &worker.Task{Command: "show"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := "workload0", res.Return; exp != recv {
+		t.Errorf("expected the task to run on '%s', but got: %v", exp, recv)
+	}
+
+	if exp, recv := w.GetID(), res.WorkerID; exp != recv {
+		t.Errorf("expected the task to run on '%s', but got: '%s'", exp, recv)
+	}
+
+	if _, err := clients[w.GetID()].RunTask(context.TODO(), "workload0", &worker.Task{Command: "fail"}); err == nil || err.Error() != "device refused" {
+		t.Errorf("expected the task error, but got: %v", err)
+	}
+
+	if _, err := clients[w.GetID()].RunTask(context.TODO(), "workload9", &worker.Task{Command: "show"}); !errors.Is(err, worker.ErrWorkloadNotFound) {
+		t.Errorf("expected '%v', but got: %v", worker.ErrWorkloadNotFound, err)
+	}
+
+	if err := mgr.Drain(context.TODO(), w.GetID()); err != nil {
+		t.Fatalf("unexpected error when draining: %v", err)
+	}
+
+	remote, err := clients[w.GetID()].Workloads(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error when listing workloads: %v", err)
+	}
+
+	if len(remote) != 0 {
+		t.Errorf("expected no workloads on a drained worker, but got: %v", remote)
+	}
+}
+
+// Holds every task until released or its context is done
+type blockingDevice struct {
+	mockDevice
+	release chan struct{}
+}
+
+func (d *blockingDevice) RunTask(ctx context.Context, task *worker.Task) (worker.Result, error) {
+	select {
+	case <-d.release:
+		return worker.Result{Command: task.Command, Success: true}, nil
+	case <-ctx.Done():
+		return worker.Result{}, ctx.Err()
+	}
+}
+
+// Runs tasks from a manager on a worker with a full queue, over loopback HTTP
+func TestEndToEndTaskErrors(t *testing.T) {
+	w, err := worker.New(context.TODO(), worker.WithWorkerID("worker0"), worker.WithTaskQueueDepth(1))
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	defer w.Stop()
+
+	release := make(chan struct{})
+	s := NewService(w, func(_ context.Context, id string) (worker.Workload, error) {
+		return &blockingDevice{mockDevice: mockDevice{name: id, tasks: &atomic.Int32{}}, release: release}, nil
+	})
+
+	srv := httptest.NewServer(NewHandler(s))
+	defer srv.Close()
+
+	mgr, err := manager.New(
+		context.TODO(),
+		manager.WithSignaller(nopSignaller{}),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	if err := mgr.AddWorker(context.TODO(), NewClient("worker0", srv.URL, WithRetries(3, time.Millisecond))); err != nil {
+		t.Fatalf("failed to add worker: %v", err)
+	}
+
+	if err := mgr.AddWorkload(context.TODO(), manager.NewWorkload("workload0")); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	mgr.Distribute()
+
+	if _, err := mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "show", Timeout: 50 * time.Millisecond}); !errors.Is(err, manager.ErrTaskTimeout) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrTaskTimeout, err)
+	}
+
+	// one task running and one waiting fill the queue
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer close(release)
+
+	for range 2 {
+		wg.Go(func() {
+			_, _ = mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "show"})
+		})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		if running, waiting, _ := w.TaskQueueLen("workload0"); running == 1 && waiting == 1 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the task queue to fill up")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	_, err = mgr.RunTask(context.TODO(), "workload0", &worker.Task{Command: "show"})
+	if !errors.Is(err, manager.ErrWorkerBusy) || !errors.Is(err, worker.ErrQueueFull) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrWorkerBusy, err)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	s, _ := newService(t, "worker0")
+
+	var attempts atomic.Int32
+	handler := NewHandler(s)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c := NewClient("worker0", srv.URL, WithRetries(3, time.Millisecond))
+	if err := c.Load(context.TODO(), manager.LoadRequest{Workload: manager.NewWorkload("workload0")}); err != nil {
+		t.Fatalf("unexpected error when loading: %v", err)
+	}
+
+	if exp, recv := 3, int(attempts.Load()); exp != recv {
+		t.Errorf("expected %d attempt(s), but got: %d", exp, recv)
+	}
+
+	if exp, recv := []string{"workload0"}, s.Workloads(); !slices.Equal(exp, recv) {
+		t.Errorf("expected workloads %v, but got: %v", exp, recv)
+	}
+
+	// client errors are not retried
+	attempts.Store(10)
+	if _, err := c.RunTask(context.TODO(), "workload0", &worker.Task{}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected '%v', but got: %v", ErrBadRequest, err)
+	}
+
+	if exp, recv := 11, int(attempts.Load()); exp != recv {
+		t.Errorf("expected %d attempt(s), but got: %d", exp, recv)
+	}
+
+	c = NewClient("worker0", "http://127.0.0.1:1", WithRetries(1, time.Millisecond), WithTimeout(time.Second))
+	if err := c.Unload(context.TODO(), manager.UnloadRequest{Workload: manager.NewWorkload("workload0")}); !errors.Is(err, manager.ErrUnreachable) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrUnreachable, err)
+	}
+}
+
+func TestClientTaskTimeout(t *testing.T) {
+	w, err := worker.New(context.TODO(), worker.WithWorkerID("worker0"))
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	defer w.Stop()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	s := NewService(w, func(_ context.Context, id string) (worker.Workload, error) {
+		return &blockingDevice{mockDevice: mockDevice{name: id, tasks: &atomic.Int32{}}, release: release}, nil
+	})
+
+	if err := s.Load(context.TODO(), LoadRequest{WorkloadID: "workload0"}); err != nil {
+		t.Fatalf("unexpected error when loading: %v", err)
+	}
+
+	var attempts atomic.Int32
+	handler := NewHandler(s)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	// a task outliving the attempt timeout isn't run again
+	c := NewClient("worker0", srv.URL, WithRetries(3, time.Millisecond), WithTimeout(50*time.Millisecond))
+	if _, err := c.RunTask(context.TODO(), "workload0", &worker.Task{Command: "show"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected '%v', but got: %v", context.DeadlineExceeded, err)
+	}
+
+	if exp, recv := 1, int(attempts.Load()); exp != recv {
+		t.Errorf("expected %d attempt(s), but got: %d", exp, recv)
+	}
+}
+
+func TestTracePropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	w, err := worker.New(context.TODO(), worker.WithWorkerID("worker0"), worker.WithTracerProvider(tp))
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Stop() })
+
+	f := &deviceFactory{}
+	srv := httptest.NewServer(NewHandler(NewService(w, f.create)))
+	defer srv.Close()
+
+	c := NewClient("worker0", srv.URL)
+
+	ctx, span := tp.Tracer("manager").Start(context.TODO(), "worker.Load")
+	err = c.Load(ctx, manager.LoadRequest{Workload: manager.NewWorkload("workload0")})
+	span.End()
+
+	if err != nil {
+		t.Fatalf("unexpected error when loading: %v", err)
+	}
+
+	var remote tracetest.SpanStub
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "worker.AddWorkload" {
+			remote = s
+		}
+	}
+
+	if exp, recv := span.SpanContext().TraceID(), remote.SpanContext.TraceID(); exp != recv {
+		t.Errorf("expected the worker span in trace '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := span.SpanContext().SpanID(), remote.Parent.SpanID(); exp != recv {
+		t.Errorf("expected the worker span to be a child of '%s', but got: '%s'", exp, recv)
+	}
+}
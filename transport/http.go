@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	PathLoad      = "/v1/load"
+	PathUnload    = "/v1/unload"
+	PathTasks     = "/v1/tasks"
+	PathWorkloads = "/v1/workloads"
+)
+
+// Serves a Service over HTTP/JSON, continuing the trace of the caller
+func NewHandler(s *Service) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST "+PathLoad, func(w http.ResponseWriter, r *http.Request) {
+		var req LoadRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		if err := s.Load(r.Context(), req); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST "+PathUnload, func(w http.ResponseWriter, r *http.Request) {
+		var req UnloadRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		if err := s.Unload(r.Context(), req); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST "+PathTasks, func(w http.ResponseWriter, r *http.Request) {
+		var req TaskRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		res, err := s.RunTask(r.Context(), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	})
+
+	mux.HandleFunc("GET "+PathWorkloads, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, WorkloadsResponse{Workloads: s.Workloads()})
+	})
+
+	return traced(mux)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, fmt.Errorf("%w: %w", ErrBadRequest, err))
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	res := NewErrorResponse(err)
+
+	status := http.StatusInternalServerError
+	switch res.Code {
+	case CodeNotFound:
+		status = http.StatusNotFound
+	case CodeBadRequest:
+		status = http.StatusBadRequest
+	case CodeExists, CodeStale:
+		status = http.StatusConflict
+	case CodeBusy:
+		status = http.StatusTooManyRequests
+	case CodeTimeout:
+		status = http.StatusGatewayTimeout
+	case CodeUnauthorized:
+		status = http.StatusUnauthorized
+	}
+
+	writeJSON(w, status, res)
+}
+
+// Reads the error of a failed response
+func readError(res *http.Response) error {
+	var body ErrorResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil || body.Code == "" {
+		return &RemoteError{Code: CodeInternal, Message: res.Status}
+	}
+
+	return body.Err()
+}
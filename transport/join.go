@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+const PathJoin = "/v1/join"
+
+// A worker registration, with the address the manager dials it back on
+type JoinRequest struct {
+	manager.Registration
+	Address string `json:"address"`
+}
+
+// Serves worker registrations on a manager, at POST /v1/join and
+// DELETE /v1/join/{id}?generation=N. Every worker is reached with
+// a Client created with the given options.
+func NewJoinHandler(mgr *manager.Manager, opts ...ClientOption) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST "+PathJoin, func(w http.ResponseWriter, r *http.Request) {
+		var req JoinRequest
+		if !decode(w, r, &req) {
+			return
+		}
+
+		if req.Address == "" {
+			writeError(w, fmt.Errorf("%w: missing address", ErrBadRequest))
+			return
+		}
+
+		s, err := mgr.Register(r.Context(), NewClient(req.ID, req.Address, opts...), req.Registration)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, s)
+	})
+
+	mux.HandleFunc("DELETE "+PathJoin+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		gen, err := strconv.ParseUint(r.URL.Query().Get("generation"), 10, 64)
+		if err != nil {
+			writeError(w, fmt.Errorf("%w: invalid generation", ErrBadRequest))
+			return
+		}
+
+		if err := mgr.Deregister(r.Context(), r.PathValue("id"), gen); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return mux
+}
+
+// Registers a worker with the manager at managerURL. A worker which
+// restarts presents the generation of its last session, to take over
+// its id and reclaim the workloads listed in the new session.
+func Join(ctx context.Context, client *http.Client, managerURL string, req JoinRequest) (manager.Session, error) {
+	var s manager.Session
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return s, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(managerURL, "/")+PathJoin, bytes.NewReader(b))
+	if err != nil {
+		return s, err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(r)
+	if err != nil {
+		return s, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return s, readError(res)
+	}
+
+	return s, json.NewDecoder(res.Body).Decode(&s)
+}
+
+// Deregisters a worker from the manager at managerURL
+func Leave(ctx context.Context, client *http.Client, managerURL, id string, generation uint64) error {
+	u := fmt.Sprintf("%s%s/%s?generation=%d", strings.TrimSuffix(managerURL, "/"), PathJoin, url.PathEscape(id), generation)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		return readError(res)
+	}
+
+	return nil
+}
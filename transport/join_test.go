@@ -0,0 +1,93 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+func TestJoin(t *testing.T) {
+	mgr, err := manager.New(
+		context.TODO(),
+		manager.WithSignaller(nopSignaller{}),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	msrv := httptest.NewServer(NewJoinHandler(mgr))
+	defer msrv.Close()
+
+	s, _ := newService(t, "worker0")
+	wsrv := httptest.NewServer(NewHandler(s))
+	defer wsrv.Close()
+
+	req := JoinRequest{
+		Registration: manager.Registration{ID: "worker0", Labels: map[string]string{"region": "north"}, Capacity: 2},
+		Address:      wsrv.URL,
+	}
+
+	session, err := Join(context.TODO(), http.DefaultClient, msrv.URL, req)
+	if err != nil {
+		t.Fatalf("unexpected error when joining: %v", err)
+	}
+
+	if _, err := Join(context.TODO(), http.DefaultClient, msrv.URL, req); !errors.Is(err, manager.ErrWorkerExists) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrWorkerExists, err)
+	}
+
+	for _, id := range []string{"workload0", "workload1", "workload2"} {
+		if err := mgr.AddWorkload(context.TODO(), manager.NewWorkload(id)); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	mgr.Distribute()
+
+	if exp, recv := 2, len(s.Workloads()); exp != recv {
+		t.Errorf("expected %d workload(s) within capacity, but got: %d", exp, recv)
+	}
+
+	// tasks are routed through the manager to the joined worker
+	res, err := mgr.RunTask(context.TODO(), s.Workloads()[0], &worker.Task{Command: "ping"})
+	if err != nil || !res.Success {
+		t.Errorf("expected the task to run on the worker, but got: %+v, %v", res, err)
+	}
+
+	// the worker restarts and rejoins with its last session
+	req.Generation = session.Generation
+	rejoined, err := Join(context.TODO(), http.DefaultClient, msrv.URL, req)
+	if err != nil {
+		t.Fatalf("unexpected error when rejoining: %v", err)
+	}
+
+	if !slices.Equal(s.Workloads(), rejoined.Workloads) {
+		t.Errorf("expected to reclaim %v, but got: %v", s.Workloads(), rejoined.Workloads)
+	}
+
+	if err := Leave(context.TODO(), http.DefaultClient, msrv.URL, "worker0", session.Generation); !errors.Is(err, manager.ErrStaleSession) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrStaleSession, err)
+	}
+
+	if err := Leave(context.TODO(), http.DefaultClient, msrv.URL, "worker0", rejoined.Generation); err != nil {
+		t.Errorf("unexpected error when leaving: %v", err)
+	}
+
+	if _, err := mgr.GetWorker(context.TODO(), "worker0"); !errors.Is(err, manager.ErrWorkerNotFound) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrWorkerNotFound, err)
+	}
+
+	if _, err := Join(context.TODO(), http.DefaultClient, msrv.URL, JoinRequest{Registration: manager.Registration{ID: "worker1"}}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected '%v', but got: %v", ErrBadRequest, err)
+	}
+}
@@ -0,0 +1,163 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	gonats "github.com/nats-io/nats.go"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// A remote worker reached over NATS, implementing manager.Worker.
+// Requests without any reply are retried with the same idempotency
+// key, so a retried request is only carried out once by the worker.
+type Client struct {
+	id string
+	nc *gonats.Conn
+	o  options
+}
+
+func NewClient(nc *gonats.Conn, id string, opts ...Option) *Client {
+	return &Client{
+		id: id,
+		nc: nc,
+		o:  newOptions(opts),
+	}
+}
+
+func (c *Client) GetID() string {
+	return c.id
+}
+
+func (c *Client) Load(ctx context.Context, req manager.LoadRequest) error {
+	return c.request(ctx, opLoad, transport.LoadRequest{
+		WorkloadID:     req.Workload.GetID(),
+		IdempotencyKey: key(req.IdempotencyKey),
+		ManagerID:      req.ManagerID,
+		Reason:         req.Reason,
+	}, nil, noReply)
+}
+
+func (c *Client) Unload(ctx context.Context, req manager.UnloadRequest) error {
+	return c.request(ctx, opUnload, transport.UnloadRequest{
+		WorkloadID:     req.Workload.GetID(),
+		IdempotencyKey: key(req.IdempotencyKey),
+		ManagerID:      req.ManagerID,
+		Reason:         req.Reason,
+	}, nil, noReply)
+}
+
+// Runs a task on a workload of the worker. A task which ran and
+// failed is returned along with its error. Only requests without any
+// responder are retried, a timed out request may leave the task running
+// on the worker.
+func (c *Client) RunTask(ctx context.Context, workloadID string, task *worker.Task) (worker.Result, error) {
+	var res worker.Result
+	err := c.request(ctx, opTasks, transport.TaskRequest{
+		WorkloadID:     workloadID,
+		IdempotencyKey: key(""),
+		Task:           task,
+	}, &res, noResponders)
+	if err != nil {
+		return res, err
+	}
+
+	return res, res.Error
+}
+
+// Lists the workloads on the worker
+func (c *Client) Workloads(ctx context.Context) ([]string, error) {
+	var res transport.WorkloadsResponse
+	return res.Workloads, c.request(ctx, opWorkloads, struct{}{}, &res, noReply)
+}
+
+func key(k string) string {
+	if k == "" {
+		return uuid.NewString()
+	}
+
+	return k
+}
+
+// Reports whether a request got no reply, which means the worker may
+// be reconnecting
+func noReply(err error) bool {
+	return errors.Is(err, gonats.ErrNoResponders) || errors.Is(err, gonats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Reports whether a request found no worker subscribed to answer it
+func noResponders(err error) bool {
+	return errors.Is(err, gonats.ErrNoResponders)
+}
+
+// Makes a request, retrying failures the retry func accepts
+func (c *Client) request(ctx context.Context, op string, body, v any, retry func(error) bool) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	subject := c.o.subject(c.id, op)
+
+	backoff := c.o.backoff
+	for attempt := 0; ; attempt++ {
+		msg, err := c.attempt(ctx, subject, b)
+		if err == nil {
+			return decodeReply(msg, v)
+		}
+
+		if !noReply(err) {
+			return fmt.Errorf("failed to request '%s': %w", subject, err)
+		}
+
+		if !retry(err) || attempt >= c.o.retries || ctx.Err() != nil {
+			return fmt.Errorf("failed to request '%s': %w: %w", subject, manager.ErrUnreachable, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return fmt.Errorf("failed to request '%s': %w", subject, ctx.Err())
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, subject string, b []byte) (*gonats.Msg, error) {
+	if c.o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.o.timeout)
+		defer cancel()
+	}
+
+	msg := gonats.NewMsg(subject)
+	msg.Data = b
+	transport.InjectTraceContext(ctx, http.Header(msg.Header))
+
+	return c.nc.RequestMsgWithContext(ctx, msg)
+}
+
+func decodeReply(msg *gonats.Msg, v any) error {
+	var r Reply
+	if err := json.Unmarshal(msg.Data, &r); err != nil {
+		return fmt.Errorf("invalid reply: %w", err)
+	}
+
+	if r.Error != nil {
+		return r.Error.Err()
+	}
+
+	if v == nil || len(r.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(r.Data, v)
+}
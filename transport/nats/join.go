@@ -0,0 +1,91 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	gonats "github.com/nats-io/nats.go"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+const (
+	opJoin  = "join"
+	opLeave = "leave"
+)
+
+type LeaveRequest struct {
+	ID         string `json:"id"`
+	Generation uint64 `json:"generation"`
+}
+
+// Serves worker registrations on a manager, at <prefix>.join and
+// <prefix>.leave. Every worker is reached with a Client created with
+// the given options.
+func ServeJoin(ctx context.Context, nc *gonats.Conn, mgr *manager.Manager, opts ...Option) (*Server, error) {
+	o := newOptions(opts)
+
+	join, err := nc.Subscribe(o.prefix+"."+opJoin, func(msg *gonats.Msg) {
+		var reg manager.Registration
+		if err := decode(msg, &reg); err != nil {
+			respond(msg, nil, err)
+			return
+		}
+
+		s, err := mgr.Register(ctx, NewClient(nc, reg.ID, opts...), reg)
+		respond(msg, s, err)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	leave, err := nc.Subscribe(o.prefix+"."+opLeave, func(msg *gonats.Msg) {
+		var req LeaveRequest
+		if err := decode(msg, &req); err != nil {
+			respond(msg, nil, err)
+			return
+		}
+
+		respond(msg, nil, mgr.Deregister(ctx, req.ID, req.Generation))
+	})
+	if err != nil {
+		_ = join.Unsubscribe()
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	return &Server{subs: []*gonats.Subscription{join, leave}}, nc.Flush()
+}
+
+// Registers a worker with the manager listening on the connection.
+// A worker which restarts presents the generation of its last session,
+// to take over its id and reclaim the workloads listed in the new session.
+func Join(ctx context.Context, nc *gonats.Conn, reg manager.Registration, opts ...Option) (manager.Session, error) {
+	var s manager.Session
+	return s, joinRequest(ctx, nc, newOptions(opts), opJoin, reg, &s)
+}
+
+// Deregisters a worker from the manager listening on the connection
+func Leave(ctx context.Context, nc *gonats.Conn, id string, generation uint64, opts ...Option) error {
+	return joinRequest(ctx, nc, newOptions(opts), opLeave, LeaveRequest{ID: id, Generation: generation}, nil)
+}
+
+func joinRequest(ctx context.Context, nc *gonats.Conn, o options, op string, body, v any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+
+	msg, err := nc.RequestWithContext(ctx, o.prefix+"."+op, b)
+	if err != nil {
+		return fmt.Errorf("failed to request '%s': %w", o.prefix+"."+op, err)
+	}
+
+	return decodeReply(msg, v)
+}
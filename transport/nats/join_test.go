@@ -0,0 +1,74 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+func TestJoin(t *testing.T) {
+	url := runServer(t)
+
+	mgr, err := manager.New(
+		context.TODO(),
+		manager.WithSignaller(nopSignaller{}),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	js, err := ServeJoin(context.TODO(), connect(t, url), mgr)
+	if err != nil {
+		t.Fatalf("failed to serve joins: %v", err)
+	}
+	defer js.Close()
+
+	nc := connect(t, url)
+
+	w, err := worker.New(context.TODO(), worker.WithWorkerID("worker0"))
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	defer w.Stop()
+
+	srv, err := Serve(context.TODO(), nc, transport.NewService(w, factory))
+	if err != nil {
+		t.Fatalf("failed to serve worker: %v", err)
+	}
+	defer srv.Close()
+
+	session, err := Join(context.TODO(), nc, manager.Registration{ID: "worker0", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("unexpected error when joining: %v", err)
+	}
+
+	if _, err := Join(context.TODO(), nc, manager.Registration{ID: "worker0"}); !errors.Is(err, manager.ErrWorkerExists) {
+		t.Errorf("expected '%v', but got: %v", manager.ErrWorkerExists, err)
+	}
+
+	if err := mgr.AddWorkload(context.TODO(), manager.NewWorkload("workload0")); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	mgr.Distribute()
+
+	if exp, recv := 1, len(w.Workloads()); exp != recv {
+		t.Errorf("expected %d workload(s) on the worker, but got: %d", exp, recv)
+	}
+
+	if err := Leave(context.TODO(), nc, "worker0", session.Generation); err != nil {
+		t.Errorf("unexpected error when leaving: %v", err)
+	}
+
+	if _, ok := mgr.WorkerRegistration("worker0"); ok {
+		t.Errorf("expected the registration to be removed")
+	}
+}
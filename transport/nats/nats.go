@@ -0,0 +1,148 @@
+// Package nats carries the transport over NATS request-reply, for
+// workers which can't be dialled by their manager. Workers connect
+// out and serve requests on their own subjects:
+//
+//	<prefix>.workers.<id>.load
+//	<prefix>.workers.<id>.unload
+//	<prefix>.workers.<id>.tasks
+//	<prefix>.workers.<id>.workloads
+//
+// and publish their events on <prefix>.events.<id>. Characters which
+// aren't allowed in a subject token, like '.' and '*', are escaped in
+// the id as %XX. Requests carry the trace context in their headers,
+// like the HTTP transport. Workers register themselves with Join,
+// served on the manager by ServeJoin.
+package nats
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	gonats "github.com/nats-io/nats.go"
+
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+)
+
+const DefaultPrefix = "ottomato"
+
+const (
+	opLoad      = "load"
+	opUnload    = "unload"
+	opTasks     = "tasks"
+	opWorkloads = "workloads"
+)
+
+type options struct {
+	prefix      string
+	timeout     time.Duration
+	retries     int
+	backoff     time.Duration
+	concurrency int
+}
+
+type Option func(*options)
+
+// Set the subject prefix, default: "ottomato"
+func WithPrefix(p string) Option {
+	return func(o *options) {
+		o.prefix = p
+	}
+}
+
+// Set the max time a client waits for a single reply, and a server
+// spends on a single request, default: 30 seconds
+func WithTimeout(t time.Duration) Option {
+	return func(o *options) {
+		o.timeout = t
+	}
+}
+
+// Set the retries of a request without any reply and the backoff before
+// the first retry, doubling for every retry, default: 3 and 200ms
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.retries = n
+		o.backoff = backoff
+	}
+}
+
+// Set the max amount of requests a server handles at once, further
+// requests wait for a slot, default: 64
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = max(n, 1)
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		prefix:      DefaultPrefix,
+		timeout:     30 * time.Second,
+		retries:     3,
+		backoff:     200 * time.Millisecond,
+		concurrency: 64,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Returns the subject of an operation on a worker
+func (o options) subject(workerID, op string) string {
+	return o.prefix + ".workers." + token(workerID) + "." + op
+}
+
+// Returns the subject of events published by a worker
+func (o options) events(workerID string) string {
+	return o.prefix + ".events." + token(workerID)
+}
+
+// Escapes the characters of an id which aren't allowed in a subject
+// token, along with '%' itself
+func token(id string) string {
+	if !strings.ContainsAny(id, ".*> \t\r\n%") {
+		return id
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; c {
+		case '.', '*', '>', ' ', '\t', '\r', '\n', '%':
+			fmt.Fprintf(&b, "%%%02X", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
+
+// Body of every reply, carrying either an error or the data
+type Reply struct {
+	Error *transport.ErrorResponse `json:"error,omitempty"`
+	Data  json.RawMessage          `json:"data,omitempty"`
+}
+
+func respond(msg *gonats.Msg, data any, err error) {
+	var r Reply
+	if err != nil {
+		res := transport.NewErrorResponse(err)
+		r.Error = &res
+	} else if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			res := transport.NewErrorResponse(err)
+			r.Error = &res
+		} else {
+			r.Data = b
+		}
+	}
+
+	b, _ := json.Marshal(r)
+	_ = msg.Respond(b)
+}
@@ -0,0 +1,331 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	gonats "github.com/nats-io/nats.go"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+type mockDevice struct {
+	name string
+}
+
+func (d *mockDevice) Init(context.Context) error {
+	return nil
+}
+
+func (d *mockDevice) Ping(context.Context) error {
+	return nil
+}
+
+func (d *mockDevice) RunTask(_ context.Context, task *worker.Task) (worker.Result, error) {
+	return worker.Result{Command: task.Command, Success: true, Return: d.name}, nil
+}
+
+func (d *mockDevice) Stop() error {
+	return nil
+}
+
+func (d *mockDevice) Info() map[string]any {
+	return map[string]any{}
+}
+
+func (d *mockDevice) Name() string {
+	return d.name
+}
+
+func factory(_ context.Context, id string) (worker.Workload, error) {
+	return &mockDevice{name: id}, nil
+}
+
+type nopSignaller struct{}
+
+func (nopSignaller) Event(manager.Event) {}
+func (nopSignaller) Error(error)         {}
+
+func runServer(t *testing.T) string {
+	t.Helper()
+
+	ns, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+
+	ns.Start()
+	t.Cleanup(ns.Shutdown)
+
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatalf("nats server did not start")
+	}
+
+	return ns.ClientURL()
+}
+
+func connect(t *testing.T, url string) *gonats.Conn {
+	t.Helper()
+
+	nc, err := gonats.Connect(url)
+	if err != nil {
+		t.Fatalf("failed to connect to nats: %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	return nc
+}
+
+// Runs a manager and two workers connected through an embedded NATS server
+func TestNATS(t *testing.T) {
+	url := runServer(t)
+
+	var mu sync.Mutex
+	events := map[string]int{}
+
+	if _, err := SubscribeEvents(connect(t, url), func(e worker.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events[e.Worker]++
+	}); err != nil {
+		t.Fatalf("failed to subscribe to events: %v", err)
+	}
+
+	mgr, err := manager.New(
+		context.TODO(),
+		manager.WithSignaller(nopSignaller{}),
+		manager.WithDistributorInterval(time.Hour),
+		manager.WithRebalanceInterval(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("failed to create manager: %v", err)
+	}
+	defer mgr.Stop()
+
+	mc := connect(t, url)
+	clients := map[string]*Client{}
+
+	for _, id := range []string{"worker0", "worker1"} {
+		nc := connect(t, url)
+
+		w, err := worker.New(context.TODO(), worker.WithWorkerID(id), worker.WithEventCallback(PublishEvents(nc)))
+		if err != nil {
+			t.Fatalf("failed to create worker: %v", err)
+		}
+		defer w.Stop()
+
+		srv, err := Serve(context.TODO(), nc, transport.NewService(w, factory))
+		if err != nil {
+			t.Fatalf("failed to serve worker: %v", err)
+		}
+		defer srv.Close()
+
+		clients[id] = NewClient(mc, id)
+		if err := mgr.AddWorker(context.TODO(), clients[id]); err != nil {
+			t.Fatalf("failed to add worker: %v", err)
+		}
+	}
+
+	for _, id := range []string{"workload0", "workload1", "workload2", "workload3"} {
+		if err := mgr.AddWorkload(context.TODO(), manager.NewWorkload(id)); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	mgr.Distribute()
+
+	for id, c := range clients {
+		wls, err := c.Workloads(context.TODO())
+		if err != nil {
+			t.Fatalf("unexpected error when listing workloads: %v", err)
+		}
+
+		if exp, recv := 2, len(wls); exp != recv {
+			t.Errorf("expected %d workload(s) on '%s', but got: %d", exp, id, recv)
+		}
+	}
+
+	wl, _ := mgr.GetWorkload(context.TODO(), "workload0")
+	w, err := mgr.GetAssociation(context.TODO(), wl)
+	if err != nil {
+		t.Fatalf("failed to get association: %v", err)
+	}
+
+	res, err := clients[w.GetID()].RunTask(context.TODO(), "workload0", &worker.Task{Command: "show"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := "workload0", res.Return; exp != recv {
+		t.Errorf("expected the task to run on '%s', but got: %v", exp, recv)
+	}
+
+	if _, err := clients[w.GetID()].RunTask(context.TODO(), "workload9", &worker.Task{Command: "show"}); !errors.Is(err, worker.ErrWorkloadNotFound) {
+		t.Errorf("expected '%v', but got: %v", worker.ErrWorkloadNotFound, err)
+	}
+
+	// every worker published an added event per workload
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		n := events["worker0"] + events["worker1"]
+		mu.Unlock()
+
+		if n >= 4 {
+			break
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if events["worker0"] < 2 || events["worker1"] < 2 {
+		t.Errorf("expected events from both workers, but got: %v", events)
+	}
+}
+
+func TestNoResponders(t *testing.T) {
+	c := NewClient(connect(t, runServer(t)), "worker9", WithRetries(1, time.Millisecond))
+
+	err := c.Load(context.TODO(), manager.LoadRequest{Workload: manager.NewWorkload("workload0")})
+	if !errors.Is(err, gonats.ErrNoResponders) || !errors.Is(err, manager.ErrUnreachable) {
+		t.Errorf("expected '%v', but got: %v", gonats.ErrNoResponders, err)
+	}
+}
+
+func TestSubjectToken(t *testing.T) {
+	cases := map[string]string{
+		"worker0":          "worker0",
+		"edge.router-1":    "edge%2Erouter-1",
+		"worker*":          "worker%2A",
+		"a>b c":            "a%3Eb%20c",
+		"100%":             "100%25",
+		"edge%2Erouter-1":  "edge%252Erouter-1",
+		"core-router_01:x": "core-router_01:x",
+	}
+
+	for id, exp := range cases {
+		if recv := token(id); exp != recv {
+			t.Errorf("expected '%s' for '%s', but got: '%s'", exp, id, recv)
+		}
+	}
+}
+
+// Factory blocking until the load is done, counting the loads at once
+type blockingFactory struct {
+	inflight atomic.Int32
+	peak     atomic.Int32
+	errs     chan error
+}
+
+func (f *blockingFactory) create(ctx context.Context, id string) (worker.Workload, error) {
+	n := f.inflight.Add(1)
+	defer f.inflight.Add(-1)
+
+	for {
+		p := f.peak.Load()
+		if n <= p || f.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+
+	<-ctx.Done()
+	f.errs <- ctx.Err()
+
+	return nil, ctx.Err()
+}
+
+func TestServeBounded(t *testing.T) {
+	url := runServer(t)
+
+	// ids containing subject separators are escaped
+	w, err := worker.New(context.TODO(), worker.WithWorkerID("edge.router-1"))
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Stop() })
+
+	f := &blockingFactory{errs: make(chan error, 10)}
+	srv, err := Serve(context.TODO(), connect(t, url), transport.NewService(w, f.create), WithConcurrency(2), WithTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("failed to serve: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+
+	c := NewClient(connect(t, url), "edge.router-1", WithRetries(0, 0))
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			err := c.Load(context.TODO(), manager.LoadRequest{Workload: manager.NewWorkload(fmt.Sprintf("workload%d", i))})
+			if err == nil {
+				t.Errorf("expected the load to run out of time")
+			}
+		})
+	}
+	wg.Wait()
+
+	if exp, recv := int32(2), f.peak.Load(); recv > exp {
+		t.Errorf("expected at most %d request(s) at once, but got: %d", exp, recv)
+	}
+
+	for range 4 {
+		if err := <-f.errs; !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected every request to get a deadline, but got: %v", err)
+		}
+	}
+}
+
+func TestTracePropagation(t *testing.T) {
+	url := runServer(t)
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	w, err := worker.New(context.TODO(), worker.WithWorkerID("worker0"), worker.WithTracerProvider(tp))
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Stop() })
+
+	srv, err := Serve(context.TODO(), connect(t, url), transport.NewService(w, factory))
+	if err != nil {
+		t.Fatalf("failed to serve: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+
+	c := NewClient(connect(t, url), "worker0")
+
+	ctx, span := tp.Tracer("manager").Start(context.TODO(), "worker.Load")
+	err = c.Load(ctx, manager.LoadRequest{Workload: manager.NewWorkload("workload0")})
+	span.End()
+
+	if err != nil {
+		t.Fatalf("unexpected error when loading: %v", err)
+	}
+
+	var remote tracetest.SpanStub
+	for _, s := range exporter.GetSpans() {
+		if s.Name == "worker.AddWorkload" {
+			remote = s
+		}
+	}
+
+	if exp, recv := span.SpanContext().TraceID(), remote.SpanContext.TraceID(); exp != recv {
+		t.Errorf("expected the worker span in trace '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := span.SpanContext().SpanID(), remote.Parent.SpanID(); exp != recv {
+		t.Errorf("expected the worker span to be a child of '%s', but got: '%s'", exp, recv)
+	}
+}
@@ -0,0 +1,156 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	gonats "github.com/nats-io/nats.go"
+
+	"github.com/Telenor-NMS-SE/ottomato/transport"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// A transport.Service served over NATS
+type Server struct {
+	subs []*gonats.Subscription
+}
+
+// Serves a transport.Service on the subjects of its worker. Requests
+// are handled concurrently, up to the server's concurrency, each with
+// a context derived from ctx which is done after the timeout, and
+// carries the trace context of the message headers.
+func Serve(ctx context.Context, nc *gonats.Conn, s *transport.Service, opts ...Option) (*Server, error) {
+	o := newOptions(opts)
+	id := s.WorkerID()
+
+	if id == "" {
+		return nil, fmt.Errorf("%w: missing worker id", transport.ErrBadRequest)
+	}
+
+	handlers := map[string]func(context.Context, *gonats.Msg){
+		opLoad: func(ctx context.Context, msg *gonats.Msg) {
+			var req transport.LoadRequest
+			if err := decode(msg, &req); err != nil {
+				respond(msg, nil, err)
+				return
+			}
+
+			respond(msg, nil, s.Load(ctx, req))
+		},
+		opUnload: func(ctx context.Context, msg *gonats.Msg) {
+			var req transport.UnloadRequest
+			if err := decode(msg, &req); err != nil {
+				respond(msg, nil, err)
+				return
+			}
+
+			respond(msg, nil, s.Unload(ctx, req))
+		},
+		opTasks: func(ctx context.Context, msg *gonats.Msg) {
+			var req transport.TaskRequest
+			if err := decode(msg, &req); err != nil {
+				respond(msg, nil, err)
+				return
+			}
+
+			res, err := s.RunTask(ctx, req)
+			if err != nil {
+				respond(msg, nil, err)
+				return
+			}
+
+			respond(msg, res, nil)
+		},
+		opWorkloads: func(_ context.Context, msg *gonats.Msg) {
+			respond(msg, transport.WorkloadsResponse{Workloads: s.Workloads()}, nil)
+		},
+	}
+
+	// shared by every subject, a full server holds up the subscriptions,
+	// leaving further requests pending in the connection
+	sem := make(chan struct{}, o.concurrency)
+
+	srv := &Server{}
+	for op, h := range handlers {
+		// every request is handled on its own goroutine, as
+		// a slow load would otherwise hold up the others
+		sub, err := nc.Subscribe(o.subject(id, op), func(msg *gonats.Msg) {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			go func() {
+				defer func() { <-sem }()
+
+				ctx := transport.ExtractTraceContext(ctx, http.Header(msg.Header))
+
+				cancel := context.CancelFunc(func() {})
+				if o.timeout > 0 {
+					ctx, cancel = context.WithTimeout(ctx, o.timeout)
+				}
+				defer cancel()
+
+				h(ctx, msg)
+			}()
+		})
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to subscribe: %w", err), srv.Close())
+		}
+
+		srv.subs = append(srv.subs, sub)
+	}
+
+	return srv, nc.Flush()
+}
+
+// Stops serving requests
+func (s *Server) Close() error {
+	var errs []error
+	for _, sub := range s.subs {
+		errs = append(errs, sub.Unsubscribe())
+	}
+
+	return errors.Join(errs...)
+}
+
+func decode(msg *gonats.Msg, v any) error {
+	if err := json.Unmarshal(msg.Data, v); err != nil {
+		return fmt.Errorf("%w: %w", transport.ErrBadRequest, err)
+	}
+
+	return nil
+}
+
+// Returns a worker event callback publishing every event
+// on the events subject of its worker
+func PublishEvents(nc *gonats.Conn, opts ...Option) func(context.Context, worker.Event) {
+	o := newOptions(opts)
+
+	return func(_ context.Context, e worker.Event) {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return
+		}
+
+		_ = nc.Publish(o.events(e.Worker), b)
+	}
+}
+
+// Calls fn for every event published by any worker
+func SubscribeEvents(nc *gonats.Conn, fn func(worker.Event), opts ...Option) (*gonats.Subscription, error) {
+	o := newOptions(opts)
+
+	return nc.Subscribe(o.prefix+".events.*", func(msg *gonats.Msg) {
+		var e worker.Event
+		if err := json.Unmarshal(msg.Data, &e); err != nil {
+			return
+		}
+
+		fn(e)
+	})
+}
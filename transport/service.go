@@ -0,0 +1,198 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// Answers transport requests on behalf of a worker. Loading a loaded
+// workload and unloading an unknown workload both succeed, and requests
+// carrying an idempotency key are answered once within the dedupe TTL.
+type Service struct {
+	worker  *worker.Worker
+	factory Factory
+
+	ttl   time.Duration
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done    chan struct{}
+	res     worker.Result
+	err     error
+	expires time.Time
+}
+
+type ServiceOption func(*Service)
+
+// Set how long answers are remembered by idempotency key, default: 10 minutes
+func WithDedupeTTL(t time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.ttl = t
+	}
+}
+
+func NewService(w *worker.Worker, factory Factory, opts ...ServiceOption) *Service {
+	s := &Service{
+		worker:  w,
+		factory: factory,
+		ttl:     10 * time.Minute,
+		calls:   map[string]*call{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Returns the id of the wrapped worker
+func (s *Service) WorkerID() string {
+	return s.worker.GetWorkerID()
+}
+
+// Runs fn once per key. Concurrent calls with the same key wait for the
+// first one, and successful answers are reused until they expire.
+// Failures are forgotten, so a retry runs fn again. So are answers of
+// calls cut short by a cancelled or expired context, a call waiting on
+// one runs fn itself.
+func (s *Service) once(key string, fn func() (worker.Result, error)) (worker.Result, error) {
+	if key == "" {
+		return fn()
+	}
+
+	for {
+		s.mu.Lock()
+		now := time.Now()
+		for k, c := range s.calls {
+			if !c.expires.IsZero() && now.After(c.expires) {
+				delete(s.calls, k)
+			}
+		}
+
+		if c, ok := s.calls[key]; ok {
+			s.mu.Unlock()
+			<-c.done
+
+			if interrupted(c.res, c.err) {
+				continue
+			}
+
+			return c.res, c.err
+		}
+
+		c := &call{done: make(chan struct{})}
+		s.calls[key] = c
+		s.mu.Unlock()
+
+		c.res, c.err = fn()
+
+		s.mu.Lock()
+		if c.err != nil || interrupted(c.res, c.err) {
+			delete(s.calls, key)
+		} else {
+			c.expires = time.Now().Add(s.ttl)
+		}
+		s.mu.Unlock()
+
+		close(c.done)
+
+		return c.res, c.err
+	}
+}
+
+// Reports whether a call was cut short by a cancelled or expired
+// context, either failing or answering with the task's error
+func interrupted(res worker.Result, err error) bool {
+	for _, err := range []error{err, res.Error} {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Service) Load(ctx context.Context, req LoadRequest) error {
+	if req.WorkloadID == "" {
+		return fmt.Errorf("%w: missing workload id", ErrBadRequest)
+	}
+
+	_, err := s.once(req.IdempotencyKey, func() (worker.Result, error) {
+		wl, err := s.factory(ctx, req.WorkloadID)
+		if err != nil {
+			return worker.Result{}, fmt.Errorf("failed to create workload '%s': %w", req.WorkloadID, err)
+		}
+
+		if _, err := s.worker.AddWorkload(ctx, wl); err != nil && !errors.Is(err, worker.ErrWorkloadExists) {
+			return worker.Result{}, err
+		}
+
+		return worker.Result{}, nil
+	})
+
+	return err
+}
+
+func (s *Service) Unload(ctx context.Context, req UnloadRequest) error {
+	if req.WorkloadID == "" {
+		return fmt.Errorf("%w: missing workload id", ErrBadRequest)
+	}
+
+	_, err := s.once(req.IdempotencyKey, func() (worker.Result, error) {
+		if err := s.worker.DeleteWorkload(req.WorkloadID); err != nil && !errors.Is(err, worker.ErrWorkloadNotFound) {
+			return worker.Result{}, err
+		}
+
+		return worker.Result{}, nil
+	})
+
+	return err
+}
+
+// Runs a task on a workload. A task which ran and failed is answered
+// with its error set on the result. A full task queue, a timed out task
+// and a missing workload are returned as errors, so they reach the
+// caller with their codes.
+func (s *Service) RunTask(ctx context.Context, req TaskRequest) (worker.Result, error) {
+	if req.WorkloadID == "" || req.Task == nil || req.Task.Command == "" {
+		return worker.Result{}, fmt.Errorf("%w: missing workload id or task command", ErrBadRequest)
+	}
+
+	return s.once(req.IdempotencyKey, func() (worker.Result, error) {
+		res, err := s.worker.RunTask(ctx, req.WorkloadID, req.Task)
+		if errors.Is(err, worker.ErrWorkloadNotFound) || errors.Is(err, worker.ErrQueueFull) || errors.Is(err, worker.ErrTaskTimeout) {
+			return res, err
+		}
+
+		if err != nil && res.Error == nil {
+			res.Error = err
+		}
+
+		return res, nil
+	})
+}
+
+// Lists the workloads on the worker
+func (s *Service) Workloads() []string {
+	wls := s.worker.Workloads()
+
+	ids := make([]string, 0, len(wls))
+	for _, wl := range wls {
+		if id, ok := wl["name"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
@@ -0,0 +1,159 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+)
+
+// Writes a certificate and key signed by the parent, or self signed
+// without one, and returns their paths
+func writeCert(t *testing.T, dir, name string, tmpl *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (string, string, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, _ := x509.ParseCertificate(der)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+
+	certPath, keyPath := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+
+	return certPath, keyPath, cert, key
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	caPath, _, ca, caKey := writeCert(t, dir, "ca", &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ottomato ca"},
+		NotBefore:             now,
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+
+	serverCert, serverKey, _, _ := writeCert(t, dir, "server", &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "worker0"},
+		NotBefore:    now,
+		NotAfter:     now.Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca, caKey)
+
+	clientCert, clientKey, _, _ := writeCert(t, dir, "client", &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "manager0"},
+		NotBefore:    now,
+		NotAfter:     now.Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, caKey)
+
+	serverTLS, err := ServerTLSConfig(serverCert, serverKey, caPath)
+	if err != nil {
+		t.Fatalf("failed to load server TLS config: %v", err)
+	}
+
+	clientTLS, err := ClientTLSConfig(clientCert, clientKey, caPath)
+	if err != nil {
+		t.Fatalf("failed to load client TLS config: %v", err)
+	}
+
+	s, _ := newService(t, "worker0")
+	srv := httptest.NewUnstartedServer(NewHandler(s))
+	srv.TLS = serverTLS
+	srv.StartTLS()
+	defer srv.Close()
+
+	req := manager.LoadRequest{Workload: manager.NewWorkload("workload0")}
+
+	c := NewClient("worker0", srv.URL, WithTLSConfig(clientTLS))
+	if err := c.Load(context.TODO(), req); err != nil {
+		t.Fatalf("unexpected error when loading over mTLS: %v", err)
+	}
+
+	// a client without a certificate is turned away
+	anonymous := clientTLS.Clone()
+	anonymous.Certificates = nil
+
+	c = NewClient("worker0", srv.URL, WithTLSConfig(anonymous), WithRetries(0, 0))
+	if err := c.Load(context.TODO(), req); err == nil {
+		t.Errorf("expected an error without a client certificate")
+	}
+
+	if _, err := ServerTLSConfig(serverCert, serverKey, filepath.Join(dir, "missing.crt")); err == nil {
+		t.Errorf("expected an error for a missing CA")
+	}
+
+	// the guard lets through verified certificates only, so a server
+	// without a CA turns every request away
+	guarded := RequireClientCert(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	plainTLS, err := ServerTLSConfig(serverCert, serverKey, "")
+	if err != nil {
+		t.Fatalf("failed to load server TLS config: %v", err)
+	}
+
+	for cfg, exp := range map[*tls.Config]int{serverTLS: http.StatusNoContent, plainTLS: http.StatusUnauthorized} {
+		srv := httptest.NewUnstartedServer(guarded)
+		srv.TLS = cfg
+		srv.StartTLS()
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}
+		res, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatalf("unexpected error when calling the guard: %v", err)
+		}
+		res.Body.Close()
+		srv.Close()
+
+		if recv := res.StatusCode; exp != recv {
+			t.Errorf("expected status %d, but got: %d", exp, recv)
+		}
+	}
+}
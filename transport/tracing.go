@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Calls carry the trace context in the W3C traceparent and tracestate
+// headers, so the spans of a call join up across manager and worker
+var propagator = propagation.TraceContext{}
+
+// Adds the trace context of ctx to the headers of an outgoing call
+func InjectTraceContext(ctx context.Context, h http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(h))
+}
+
+// Returns ctx carrying the trace context in the headers of an incoming call
+func ExtractTraceContext(ctx context.Context, h http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(h))
+}
+
+// Hands requests to next with the trace context of their headers
+func traced(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(ExtractTraceContext(r.Context(), r.Header)))
+	})
+}
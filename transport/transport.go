@@ -0,0 +1,129 @@
+// Package transport connects a manager to remote workers. A Service
+// wraps a worker.Worker and answers Load, Unload and task requests,
+// which are served over HTTP/JSON by NewHandler and sent by Client,
+// an implementation of manager.Worker. Workers register themselves
+// with Join, served on the manager by NewJoinHandler.
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Telenor-NMS-SE/ottomato/manager"
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+// Creates the workload object for a workload id, when the
+// manager asks a worker to load it
+type Factory func(ctx context.Context, id string) (worker.Workload, error)
+
+type LoadRequest struct {
+	WorkloadID     string `json:"workloadId"`
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	ManagerID      string `json:"managerId,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+type UnloadRequest struct {
+	WorkloadID     string `json:"workloadId"`
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	ManagerID      string `json:"managerId,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+}
+
+type TaskRequest struct {
+	WorkloadID     string       `json:"workloadId"`
+	IdempotencyKey string       `json:"idempotencyKey,omitempty"`
+	Task           *worker.Task `json:"task"`
+}
+
+type WorkloadsResponse struct {
+	Workloads []string `json:"workloads"`
+}
+
+// Error classes carried over the wire
+type Code string
+
+const (
+	CodeNotFound     Code = "not_found"
+	CodeBadRequest   Code = "bad_request"
+	CodeExists       Code = "exists"
+	CodeStale        Code = "stale"
+	CodeBusy         Code = "busy"
+	CodeTimeout      Code = "timeout"
+	CodeUnauthorized Code = "unauthorized"
+	CodeInternal     Code = "internal"
+)
+
+type ErrorResponse struct {
+	Code  Code   `json:"code"`
+	Error string `json:"error"`
+}
+
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("no verified client certificate")
+)
+
+// Error returned by the remote end. Unwraps to worker.ErrWorkloadNotFound,
+// ErrBadRequest, ErrUnauthorized, manager.ErrWorkerExists,
+// manager.ErrStaleSession, worker.ErrQueueFull or worker.ErrTaskTimeout,
+// depending on its code.
+type RemoteError struct {
+	Code    Code
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("remote: %s", e.Message)
+}
+
+func (e *RemoteError) Unwrap() error {
+	switch e.Code {
+	case CodeNotFound:
+		return worker.ErrWorkloadNotFound
+	case CodeBadRequest:
+		return ErrBadRequest
+	case CodeExists:
+		return manager.ErrWorkerExists
+	case CodeStale:
+		return manager.ErrStaleSession
+	case CodeBusy:
+		return worker.ErrQueueFull
+	case CodeTimeout:
+		return worker.ErrTaskTimeout
+	case CodeUnauthorized:
+		return ErrUnauthorized
+	default:
+		return nil
+	}
+}
+
+// Converts an error returned by a Service to its wire form
+func NewErrorResponse(err error) ErrorResponse {
+	code := CodeInternal
+
+	switch {
+	case errors.Is(err, worker.ErrWorkloadNotFound):
+		code = CodeNotFound
+	case errors.Is(err, ErrBadRequest), errors.Is(err, manager.ErrInvalidRegistration):
+		code = CodeBadRequest
+	case errors.Is(err, manager.ErrWorkerExists):
+		code = CodeExists
+	case errors.Is(err, manager.ErrStaleSession):
+		code = CodeStale
+	case errors.Is(err, worker.ErrQueueFull):
+		code = CodeBusy
+	case errors.Is(err, worker.ErrTaskTimeout):
+		code = CodeTimeout
+	case errors.Is(err, ErrUnauthorized):
+		code = CodeUnauthorized
+	}
+
+	return ErrorResponse{Code: code, Error: err.Error()}
+}
+
+func (r ErrorResponse) Err() error {
+	return &RemoteError{Code: r.Code, Message: r.Error}
+}
@@ -0,0 +1,191 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/worker"
+)
+
+type mockDevice struct {
+	name  string
+	tasks *atomic.Int32
+}
+
+func (d *mockDevice) Init(context.Context) error {
+	return nil
+}
+
+func (d *mockDevice) Ping(context.Context) error {
+	return nil
+}
+
+func (d *mockDevice) RunTask(_ context.Context, task *worker.Task) (worker.Result, error) {
+	d.tasks.Add(1)
+
+	if task.Command == "fail" {
+		return worker.Result{Command: task.Command}, errors.New("device refused")
+	}
+
+	return worker.Result{Command: task.Command, Success: true, Return: d.name}, nil
+}
+
+func (d *mockDevice) Stop() error {
+	return nil
+}
+
+func (d *mockDevice) Info() map[string]any {
+	return map[string]any{}
+}
+
+func (d *mockDevice) Name() string {
+	return d.name
+}
+
+// Counts the tasks run on every device it creates
+type deviceFactory struct {
+	tasks atomic.Int32
+}
+
+func (f *deviceFactory) create(_ context.Context, id string) (worker.Workload, error) {
+	return &mockDevice{name: id, tasks: &f.tasks}, nil
+}
+
+func newService(t *testing.T, id string) (*Service, *deviceFactory) {
+	t.Helper()
+
+	w, err := worker.New(context.TODO(), worker.WithWorkerID(id))
+	if err != nil {
+		t.Fatalf("failed to create worker: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Stop() })
+
+	f := &deviceFactory{}
+	return NewService(w, f.create), f
+}
+
+func TestService(t *testing.T) {
+	s, f := newService(t, "worker0")
+
+	// loading twice succeeds, as does unloading twice
+	for range 2 {
+		if err := s.Load(context.TODO(), LoadRequest{WorkloadID: "workload0"}); err != nil {
+			t.Fatalf("unexpected error when loading: %v", err)
+		}
+	}
+
+	if exp, recv := []string{"workload0"}, s.Workloads(); len(recv) != 1 || recv[0] != exp[0] {
+		t.Errorf("expected workloads %v, but got: %v", exp, recv)
+	}
+
+	res, err := s.RunTask(context.TODO(), TaskRequest{WorkloadID: "workload0", Task: &worker.Task{Command: "fail"}})
+	if err != nil || res.Error == nil {
+		t.Errorf("expected the task error on the result, but got: %v and %v", err, res.Error)
+	}
+
+	if _, err := s.RunTask(context.TODO(), TaskRequest{WorkloadID: "workload1", Task: &worker.Task{Command: "show"}}); !errors.Is(err, worker.ErrWorkloadNotFound) {
+		t.Errorf("expected '%v', but got: %v", worker.ErrWorkloadNotFound, err)
+	}
+
+	if _, err := s.RunTask(context.TODO(), TaskRequest{WorkloadID: "workload0"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected '%v', but got: %v", ErrBadRequest, err)
+	}
+
+	for range 2 {
+		if err := s.Unload(context.TODO(), UnloadRequest{WorkloadID: "workload0"}); err != nil {
+			t.Fatalf("unexpected error when unloading: %v", err)
+		}
+	}
+
+	if exp, recv := 1, int(f.tasks.Load()); exp != recv {
+		t.Errorf("expected %d task(s) run, but got: %d", exp, recv)
+	}
+}
+
+func TestServiceDedupe(t *testing.T) {
+	s, f := newService(t, "worker0")
+
+	if err := s.Load(context.TODO(), LoadRequest{WorkloadID: "workload0"}); err != nil {
+		t.Fatalf("unexpected error when loading: %v", err)
+	}
+
+	req := TaskRequest{WorkloadID: "workload0", IdempotencyKey: "key0", Task: &worker.Task{Command: "show"}}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			if _, err := s.RunTask(context.TODO(), req); err != nil {
+				t.Errorf("unexpected error when running task: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if exp, recv := 1, int(f.tasks.Load()); exp != recv {
+		t.Errorf("expected %d task(s) run for one key, but got: %d", exp, recv)
+	}
+
+	s.mu.Lock()
+	s.calls["key0"].expires = time.Now().Add(-time.Second)
+	s.mu.Unlock()
+
+	if _, err := s.RunTask(context.TODO(), req); err != nil {
+		t.Errorf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := 2, int(f.tasks.Load()); exp != recv {
+		t.Errorf("expected %d task(s) run once the key expired, but got: %d", exp, recv)
+	}
+	// an answer cut short by a cancelled request isn't reused by its retry
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	req.IdempotencyKey = "key1"
+	if res, _ := s.RunTask(ctx, req); !errors.Is(res.Error, worker.ErrTaskCancelled) {
+		t.Errorf("expected '%v', but got: %v", worker.ErrTaskCancelled, res.Error)
+	}
+
+	res, err := s.RunTask(context.TODO(), req)
+	if err != nil || res.Error != nil || !res.Success {
+		t.Errorf("expected the retry to run the task, but got: %v and %v", err, res.Error)
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	cases := []struct {
+		err    error
+		code   Code
+		status int
+	}{
+		{fmt.Errorf("%w: 'workload0'", worker.ErrWorkloadNotFound), CodeNotFound, http.StatusNotFound},
+		{fmt.Errorf("%w: 'workload0'", worker.ErrQueueFull), CodeBusy, http.StatusTooManyRequests},
+		{fmt.Errorf("%w after 1s", worker.ErrTaskTimeout), CodeTimeout, http.StatusGatewayTimeout},
+		{fmt.Errorf("%w from 10.0.0.1", ErrUnauthorized), CodeUnauthorized, http.StatusUnauthorized},
+		{errors.New("device refused"), CodeInternal, http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		res := NewErrorResponse(c.err)
+		if exp, recv := c.code, res.Code; exp != recv {
+			t.Errorf("expected code '%s' for '%v', but got: %s", exp, c.err, recv)
+		}
+
+		if c.code != CodeInternal && !errors.Is(res.Err(), errors.Unwrap(c.err)) {
+			t.Errorf("expected '%v' to unwrap to '%v'", res.Err(), errors.Unwrap(c.err))
+		}
+
+		rec := httptest.NewRecorder()
+		writeError(rec, c.err)
+
+		if exp, recv := c.status, rec.Code; exp != recv {
+			t.Errorf("expected status %d for '%v', but got: %d", exp, c.err, recv)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+// Data carried by the envelope of a worker event, the workload is
+// carried as the subject and the worker as the source
+type EnvelopeData struct {
+	Message string `json:"message,omitempty"`
+}
+
+// Wraps the event in the envelope shared with the manager
+func (e Event) Envelope() event.Envelope {
+	env := event.Envelope{
+		ID:            e.ID,
+		Type:          event.NewType(event.NamespaceWorker, e.EventType.String()),
+		Source:        event.WorkerSource(e.Worker),
+		Subject:       e.WorkloadName,
+		Time:          e.Time,
+		CorrelationID: e.CorrelationID,
+	}
+
+	if e.Message != "" {
+		env.Data = EnvelopeData{Message: e.Message}
+	}
+
+	return env
+}
+
+// Unwraps a worker event from an envelope
+func FromEnvelope(env event.Envelope) (Event, error) {
+	var e Event
+
+	if ns := env.Type.Namespace(); ns != event.NamespaceWorker {
+		return e, fmt.Errorf("invalid event type: '%s' is not a worker event", env.Type)
+	}
+
+	if err := e.EventType.UnmarshalJSON([]byte(`"` + env.Type.Name() + `"`)); err != nil {
+		return e, fmt.Errorf("%w: '%s'", err, env.Type)
+	}
+
+	ns, id, err := event.ParseSource(env.Source)
+	if err != nil {
+		return e, err
+	}
+
+	if ns != event.NamespaceWorker {
+		return e, fmt.Errorf("%w: '%s' is not a worker", event.ErrInvalidSource, env.Source)
+	}
+
+	var data EnvelopeData
+	if err := env.DecodeData(&data); err != nil {
+		return e, err
+	}
+
+	e.ID = env.ID
+	e.Worker = id
+	e.WorkloadName = env.Subject
+	e.Message = data.Message
+	e.Time = env.Time
+	e.CorrelationID = env.CorrelationID
+
+	return e, nil
+}
@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
+)
+
+func TestEventEnvelope(t *testing.T) {
+	exp := NewWorkloadUnreachableEvent("worker0", "workload0")
+	exp.ID = "event0"
+	exp.Time = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	exp.CorrelationID = "correlation0"
+
+	env := exp.Envelope()
+
+	if exp, recv := event.Type("worker.workload.unreachable"), env.Type; exp != recv {
+		t.Errorf("expected type '%s', but got: '%s'", exp, recv)
+	}
+
+	if exp, recv := "/ottomato/workers/worker0", env.Source; exp != recv {
+		t.Errorf("expected source '%s', but got: '%s'", exp, recv)
+	}
+
+	b, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("unexpected error when serializing envelope: %v", err)
+	}
+
+	var decoded event.Envelope
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error when deserializing envelope: %v", err)
+	}
+
+	recv, err := FromEnvelope(decoded)
+	if err != nil {
+		t.Fatalf("unexpected error when unwrapping envelope: %v", err)
+	}
+
+	if exp.ID != recv.ID || exp.EventType != recv.EventType || exp.Worker != recv.Worker || exp.WorkloadName != recv.WorkloadName ||
+		exp.Message != recv.Message || exp.CorrelationID != recv.CorrelationID || !exp.Time.Equal(recv.Time) {
+		t.Errorf("expected event to survive a round trip\nexp:  %+v\nrecv: %+v", exp, recv)
+	}
+
+	if _, err := FromEnvelope(event.Envelope{Type: "manager.worker.added", Source: event.ManagerSource("manager0")}); err == nil {
+		t.Errorf("expected an error when unwrapping a manager envelope")
+	}
+}
+
+func TestEventCorrelation(t *testing.T) {
+	var mu sync.Mutex
+	var recv []event.Envelope
+
+	w, err := New(context.Background(), WithEnvelopeCallback(func(ctx context.Context, e event.Envelope) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		recv = append(recv, e)
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %s", err.Error())
+	}
+
+	ctx := event.WithCorrelationID(context.Background(), "correlation0")
+	if _, err := w.AddWorkload(ctx, &MockWorkload{name: "test"}); err != nil {
+		t.Fatalf("failed to add new workload; %s", err.Error())
+	}
+
+	// wait, because events are async
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for _, e := range recv {
+		if e.Type != "worker.workload.added" {
+			continue
+		}
+
+		if e.ID == "" || e.Time.IsZero() {
+			t.Errorf("expected the event to be stamped with an id and time, but got: %+v", e)
+		}
+
+		if exp, recv := "correlation0", e.CorrelationID; exp != recv {
+			t.Errorf("expected correlation id '%s', but got: '%s'", exp, recv)
+		}
+
+		return
+	}
+
+	t.Errorf("expected a workload added envelope, but got: %+v", recv)
+}
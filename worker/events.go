@@ -3,13 +3,17 @@ package worker
 import (
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 type Event struct {
-	EventType    EventType `json:"eventType"`
-	Worker       string    `json:"manager"`
-	WorkloadName string    `json:"managedObject"`
-	Message      string    `json:"message"`
+	ID            string    `json:"id,omitempty"`
+	EventType     EventType `json:"eventType"`
+	Worker        string    `json:"manager"`
+	WorkloadName  string    `json:"managedObject"`
+	Message       string    `json:"message"`
+	Time          time.Time `json:"time,omitzero"`
+	CorrelationID string    `json:"correlationId,omitempty"`
 }
 
 type EventType int
@@ -23,6 +27,8 @@ const (
 	EventDeleted
 	EventInitErr
 	EventStopErr
+	EventTaskTimeout
+	EventTaskCancelled
 )
 
 func (e EventType) String() string {
@@ -43,6 +49,10 @@ func (e EventType) String() string {
 		return "workload.init.error"
 	case EventStopErr:
 		return "workload.stop.error"
+	case EventTaskTimeout:
+		return "task.timeout"
+	case EventTaskCancelled:
+		return "task.cancelled"
 	default:
 		return ""
 	}
@@ -59,20 +69,26 @@ func (e EventType) MarshalJSON() ([]byte, error) {
 
 func (e *EventType) UnmarshalJSON(data []byte) error {
 	switch string(data) {
-	case "`workload.added`":
+	case `"workload.added"`:
 		*e = EventAdded
-	case "`workload.initialized`":
+	case `"workload.deleted"`:
+		*e = EventDeleted
+	case `"workload.initialized"`:
 		*e = EventInitialized
-	case "`workload.unreachable`":
+	case `"workload.unreachable"`:
 		*e = EventUnreachable
-	case "`workload.reachable`":
+	case `"workload.reachable"`:
 		*e = EventReachable
-	case "`workload.dead`":
+	case `"workload.dead"`:
 		*e = EventDead
-	case "`workload.init.error`":
+	case `"workload.init.error"`:
 		*e = EventInitErr
-	case "`workload.stop.error`":
+	case `"workload.stop.error"`:
 		*e = EventStopErr
+	case `"task.timeout"`:
+		*e = EventTaskTimeout
+	case `"task.cancelled"`:
+		*e = EventTaskCancelled
 	default:
 		return errors.New("invalid event type")
 	}
@@ -155,3 +171,21 @@ func NewWorkloadStopError(workerId, workloadName string) Event {
 		WorkloadName: workloadName,
 	}
 }
+
+func NewTaskTimeoutEvent(workerId, workloadName, msg string) Event {
+	return Event{
+		EventType:    EventTaskTimeout,
+		Worker:       workerId,
+		WorkloadName: workloadName,
+		Message:      msg,
+	}
+}
+
+func NewTaskCancelledEvent(workerId, workloadName, msg string) Event {
+	return Event{
+		EventType:    EventTaskCancelled,
+		Worker:       workerId,
+		WorkloadName: workloadName,
+		Message:      msg,
+	}
+}
@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventTypeRoundTrip(t *testing.T) {
+	types := []EventType{
+		EventInitialized,
+		EventUnreachable,
+		EventReachable,
+		EventDead,
+		EventAdded,
+		EventDeleted,
+		EventInitErr,
+		EventStopErr,
+		EventTaskTimeout,
+		EventTaskCancelled,
+	}
+
+	for _, exp := range types {
+		b, err := json.Marshal(exp)
+		if err != nil {
+			t.Fatalf("unexpected error when serializing '%s': %v", exp, err)
+		}
+
+		var recv EventType
+		if err := json.Unmarshal(b, &recv); err != nil {
+			t.Fatalf("unexpected error when deserializing %s: %v", string(b), err)
+		}
+
+		if exp != recv {
+			t.Errorf("expected '%s', but got: '%s'", exp, recv)
+		}
+	}
+
+	var recv EventType
+	if err := json.Unmarshal([]byte(`"workload.unknown"`), &recv); err == nil {
+		t.Errorf("expected an error when deserializing an invalid event type")
+	}
+}
@@ -0,0 +1,174 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/target"
+)
+
+// Results of a task run on many workloads, keyed by workload
+type FanOutResult struct {
+	Results   map[string]Result `json:"results"`
+	Succeeded []string          `json:"succeeded"`
+	Failed    []string          `json:"failed"`
+}
+
+type (
+	FanOutOption func(*fanOutConfig)
+	fanOutConfig struct {
+		concurrency int
+		timeout     time.Duration
+		stream      func(string, Result)
+	}
+)
+
+// Set the max amount of workloads running the task at once, default: 10
+func WithConcurrency(n int) FanOutOption {
+	return func(c *fanOutConfig) {
+		c.concurrency = max(n, 1)
+	}
+}
+
+// Set the max time the task may run on a single workload, 0 is
+// unbounded. Default: 30 seconds
+func WithTargetTimeout(t time.Duration) FanOutOption {
+	return func(c *fanOutConfig) {
+		c.timeout = t
+	}
+}
+
+// Set a callback receiving each workload's result as soon as it's done
+func WithResultStream(fn func(workload string, res Result)) FanOutOption {
+	return func(c *fanOutConfig) {
+		c.stream = fn
+	}
+}
+
+// Runs a task on the given workloads with run, aggregating the results.
+// A workload fails when run returns an error, which is set on its result,
+// or when the task itself reports one. Workloads not started before ctx
+// is done fail with its error.
+func FanOut(ctx context.Context, workloads []string, run func(context.Context, string) (Result, error), opts ...FanOutOption) FanOutResult {
+	cfg := fanOutConfig{
+		concurrency: 10,
+		timeout:     30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		out = FanOutResult{
+			Results:   make(map[string]Result, len(workloads)),
+			Succeeded: []string{},
+			Failed:    []string{},
+		}
+		sem = make(chan struct{}, cfg.concurrency)
+	)
+
+	done := func(name string, res Result, err error) {
+		if err != nil && res.Error == nil {
+			res.Error = err
+		}
+
+		mu.Lock()
+		out.Results[name] = res
+		if res.Error != nil {
+			out.Failed = append(out.Failed, name)
+		} else {
+			out.Succeeded = append(out.Succeeded, name)
+		}
+		mu.Unlock()
+
+		if cfg.stream != nil {
+			cfg.stream(name, res)
+		}
+	}
+
+	for _, name := range workloads {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			done(name, Result{}, ctx.Err())
+			continue
+		}
+
+		wg.Go(func() {
+			defer func() { <-sem }()
+
+			tctx, cancel := ctx, context.CancelFunc(func() {})
+			if cfg.timeout > 0 {
+				tctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+			}
+			defer cancel()
+
+			// a task ignoring its context is abandoned at the timeout
+			type call struct {
+				res Result
+				err error
+			}
+
+			ch := make(chan call, 1)
+			go func() {
+				res, err := run(tctx, name)
+				ch <- call{res, err}
+			}()
+
+			select {
+			case c := <-ch:
+				done(name, c.res, c.err)
+			case <-tctx.Done():
+				done(name, Result{}, tctx.Err())
+			}
+		})
+	}
+
+	wg.Wait()
+
+	slices.Sort(out.Succeeded)
+	slices.Sort(out.Failed)
+
+	return out
+}
+
+// Runs a task on every workload matching the target, see FanOut.
+// Workloads are matched by name, the worker's labels and their info,
+// like the manager does. Returns target.ErrNoMatch when no workload
+// matches.
+func (w *Worker) RunTaskOn(ctx context.Context, t target.Target, task *Task, opts ...FanOutOption) (FanOutResult, error) {
+	if t == nil {
+		return FanOutResult{}, fmt.Errorf("%w: missing target", target.ErrInvalidTarget)
+	}
+
+	names := w.matching(t)
+	if len(names) == 0 {
+		return FanOutResult{}, fmt.Errorf("%w: '%s'", target.ErrNoMatch, t)
+	}
+
+	return FanOut(ctx, names, func(ctx context.Context, name string) (Result, error) {
+		return w.RunTask(ctx, name, task)
+	}, opts...), nil
+}
+
+// Returns the names of the workloads matching a target, sorted
+func (w *Worker) matching(t target.Target) []string {
+	w.workloadsMu.RLock()
+	names := make([]string, 0, len(w.workloads))
+	for name, wl := range w.workloads {
+		if t.Match(target.Candidate{Name: name, Labels: w.config.labels, Info: wl.object.Info()}) {
+			names = append(names, name)
+		}
+	}
+	w.workloadsMu.RUnlock()
+
+	slices.Sort(names)
+
+	return names
+}
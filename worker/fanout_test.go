@@ -0,0 +1,157 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/target"
+)
+
+type deviceWorkload struct {
+	MockWorkload
+	vendor string
+	delay  time.Duration
+}
+
+func (d *deviceWorkload) Info() map[string]any {
+	return map[string]any{"vendor": d.vendor}
+}
+
+func (d *deviceWorkload) RunTask(ctx context.Context, task *Task) (Result, error) {
+	select {
+	case <-time.After(d.delay):
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+
+	if task.Command == "fail" {
+		return Result{}, errors.New("device refused")
+	}
+
+	return Result{Hostname: d.name, Success: true}, nil
+}
+
+func TestRunTaskOn(t *testing.T) {
+	w, err := New(context.TODO())
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	for i := range 4 {
+		vendor := "cisco"
+		if i%2 == 1 {
+			vendor = "juniper"
+		}
+
+		wl := &deviceWorkload{MockWorkload: MockWorkload{name: fmt.Sprintf("device-%d", i)}, vendor: vendor}
+		if _, err := w.AddWorkload(context.TODO(), wl); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	var streamed sync.Map
+
+	res, err := w.RunTaskOn(context.TODO(), target.Label("vendor", "cisco"), &Task{Command: "ping"}, WithResultStream(func(name string, _ Result) {
+		streamed.Store(name, true)
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := []string{"device-0", "device-2"}, res.Succeeded; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to succeed, but got: %v", exp, recv)
+	}
+
+	for _, name := range res.Succeeded {
+		if _, ok := streamed.Load(name); !ok {
+			t.Errorf("expected the result of '%s' to be streamed", name)
+		}
+	}
+
+	res, _ = w.RunTaskOn(context.TODO(), target.List("device-1"), &Task{Command: "fail"})
+	if exp, recv := []string{"device-1"}, res.Failed; !slices.Equal(exp, recv) || res.Results["device-1"].Error == nil {
+		t.Errorf("expected %v to fail with an error, but got: %+v", exp, res)
+	}
+
+	if _, err := w.RunTaskOn(context.TODO(), target.List("device-9"), &Task{Command: "ping"}); !errors.Is(err, target.ErrNoMatch) {
+		t.Errorf("expected '%v', but got: %v", target.ErrNoMatch, err)
+	}
+
+	if _, err := w.RunTaskOn(context.TODO(), nil, &Task{Command: "ping"}); !errors.Is(err, target.ErrInvalidTarget) {
+		t.Errorf("expected '%v', but got: %v", target.ErrInvalidTarget, err)
+	}
+}
+
+func TestRunTaskOnLabels(t *testing.T) {
+	w, err := New(context.TODO(), WithLabels(map[string]string{"region": "north"}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	for i, vendor := range []string{"cisco", "juniper"} {
+		wl := &deviceWorkload{MockWorkload: MockWorkload{name: fmt.Sprintf("device-%d", i)}, vendor: vendor}
+		if _, err := w.AddWorkload(context.TODO(), wl); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	// the same expression the manager matches on
+	tgt, err := target.Parse("label:region=north and label:vendor=cisco")
+	if err != nil {
+		t.Fatalf("unexpected error when parsing target: %v", err)
+	}
+
+	res, err := w.RunTaskOn(context.TODO(), tgt, &Task{Command: "ping"})
+	if err != nil {
+		t.Fatalf("unexpected error when running task: %v", err)
+	}
+
+	if exp, recv := []string{"device-0"}, res.Succeeded; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to succeed, but got: %v", exp, recv)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	var inflight, peak atomic.Int32
+
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	res := FanOut(context.TODO(), names, func(ctx context.Context, name string) (Result, error) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		if name == "f" {
+			// ignores its context, and is abandoned
+			time.Sleep(time.Second)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		return Result{Hostname: name}, nil
+	}, WithConcurrency(2), WithTargetTimeout(100*time.Millisecond))
+
+	if recv := peak.Load(); recv > 2 {
+		t.Errorf("expected at most 2 tasks at once, but got: %d", recv)
+	}
+
+	if exp, recv := []string{"a", "b", "c", "d", "e"}, res.Succeeded; !slices.Equal(exp, recv) {
+		t.Errorf("expected %v to succeed, but got: %v", exp, recv)
+	}
+
+	if err := res.Results["f"].Error; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected '%v' for the hung task, but got: %v", context.DeadlineExceeded, err)
+	}
+}
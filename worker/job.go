@@ -0,0 +1,193 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrJobNotFound = errors.New("job does not exist")
+	ErrJobFinished = errors.New("job already finished")
+)
+
+type JobState string
+
+const (
+	JobQueued   JobState = "queued"
+	JobRunning  JobState = "running"
+	JobFinished JobState = "finished"
+)
+
+// A task submitted to run in the background
+type Job struct {
+	ID        string    `json:"id"`
+	Workload  string    `json:"workload"`
+	Task      Task      `json:"task"`
+	State     JobState  `json:"state"`
+	Submitted time.Time `json:"submitted"`
+	Started   time.Time `json:"started,omitzero"`
+	Finished  time.Time `json:"finished,omitzero"`
+	// Set once the job is finished
+	Result *Result `json:"result,omitempty"`
+}
+
+type job struct {
+	Job
+	done   chan struct{} // closed when the job is finished
+	cancel context.CancelFunc
+}
+
+// Submits a task to run on the given target in the background,
+// returning the id of the job right away
+func (w *Worker) SubmitTask(ctx context.Context, target string, task *Task) (string, error) {
+	w.workloadsMu.RLock()
+	_, exists := w.workloads[target]
+	w.workloadsMu.RUnlock()
+
+	if !exists {
+		return "", fmt.Errorf("%w: '%s'", ErrWorkloadNotFound, target)
+	}
+
+	// the job outlives the submitting call, but keeps its trace
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	j := &job{
+		cancel: cancel,
+		Job: Job{
+			ID:        uuid.NewString(),
+			Workload:  target,
+			Task:      *task,
+			State:     JobQueued,
+			Submitted: time.Now(),
+		},
+		done: make(chan struct{}),
+	}
+
+	w.jobsMu.Lock()
+	w.pruneJobs()
+	w.jobs[j.ID] = j
+	w.jobsMu.Unlock()
+
+	go w.runJob(ctx, j)
+
+	return j.ID, nil
+}
+
+func (w *Worker) runJob(ctx context.Context, j *job) {
+	defer j.cancel()
+
+	res, err := w.runTask(ctx, j.Workload, &j.Task, func() {
+		w.jobsMu.Lock()
+		j.State = JobRunning
+		j.Started = time.Now()
+		w.jobsMu.Unlock()
+	})
+	if err != nil && res.Error == nil {
+		res.Error = err
+	}
+
+	res.JobID = j.ID
+
+	w.jobsMu.Lock()
+	j.State = JobFinished
+	j.Finished = time.Now()
+	j.Result = &res
+	w.jobsMu.Unlock()
+
+	close(j.done)
+}
+
+// Cancels the context of a queued or running job, which finishes with
+// a cancelled result
+func (w *Worker) CancelJob(id string) error {
+	w.jobsMu.Lock()
+	defer w.jobsMu.Unlock()
+
+	j, ok := w.jobs[id]
+	switch {
+	case !ok:
+		return fmt.Errorf("%w: '%s'", ErrJobNotFound, id)
+	case j.State == JobFinished:
+		return fmt.Errorf("%w: '%s'", ErrJobFinished, id)
+	}
+
+	j.cancel()
+
+	return nil
+}
+
+// Returns the current state of a job
+func (w *Worker) JobStatus(id string) (Job, error) {
+	w.jobsMu.Lock()
+	defer w.jobsMu.Unlock()
+
+	w.pruneJobs()
+
+	j, ok := w.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("%w: '%s'", ErrJobNotFound, id)
+	}
+
+	return j.Job, nil
+}
+
+// Waits for a job to finish and returns its result, the error of a
+// failed task is set on the result
+func (w *Worker) JobResult(ctx context.Context, id string) (Result, error) {
+	w.jobsMu.Lock()
+	j, ok := w.jobs[id]
+	w.jobsMu.Unlock()
+
+	if !ok {
+		return Result{}, fmt.Errorf("%w: '%s'", ErrJobNotFound, id)
+	}
+
+	select {
+	case <-j.done:
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+
+	w.jobsMu.Lock()
+	defer w.jobsMu.Unlock()
+
+	return *j.Result, nil
+}
+
+// Returns all jobs still retained, oldest first
+func (w *Worker) ListJobs() []Job {
+	w.jobsMu.Lock()
+	defer w.jobsMu.Unlock()
+
+	w.pruneJobs()
+
+	jobs := make([]Job, 0, len(w.jobs))
+	for _, j := range w.jobs {
+		jobs = append(jobs, j.Job)
+	}
+
+	slices.SortFunc(jobs, func(a, b Job) int {
+		if c := a.Submitted.Compare(b.Submitted); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return jobs
+}
+
+// Drops finished jobs older than the job TTL, expects jobsMu to be held
+func (w *Worker) pruneJobs() {
+	for id, j := range w.jobs {
+		if j.State == JobFinished && time.Since(j.Finished) > w.jobTTL {
+			delete(w.jobs, id)
+		}
+	}
+}
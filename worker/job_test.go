@@ -0,0 +1,261 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSubmitTask(t *testing.T) {
+	w, err := New(context.TODO(), WithJobTTL(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	release := make(chan struct{})
+	wl := &blockingWorkload{MockWorkload: MockWorkload{name: "device-0"}, release: release}
+	if _, err := w.AddWorkload(context.TODO(), wl); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	id, err := w.SubmitTask(context.TODO(), "device-0", &Task{Command: "ping"})
+	if err != nil {
+		t.Fatalf("unexpected error when submitting task: %v", err)
+	}
+
+	job, err := w.JobStatus(id)
+	if err != nil {
+		t.Fatalf("unexpected error when getting job status: %v", err)
+	}
+
+	if job.State == JobFinished || job.Workload != "device-0" || job.Task.Command != "ping" {
+		t.Errorf("expected an unfinished 'ping' job on 'device-0', but got: %+v", job)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := w.JobResult(ctx, id); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected '%v' while the job runs, but got: %v", context.DeadlineExceeded, err)
+	}
+
+	close(release)
+
+	res, err := w.JobResult(context.TODO(), id)
+	if err != nil {
+		t.Fatalf("unexpected error when waiting for the result: %v", err)
+	}
+
+	if exp, recv := id, res.JobID; exp != recv {
+		t.Errorf("expected the result of job '%s', but got: '%s'", exp, recv)
+	}
+
+	job, _ = w.JobStatus(id)
+	if exp, recv := JobFinished, job.State; exp != recv || job.Result == nil {
+		t.Errorf("expected the job to be '%s' with a result, but got: %+v", exp, job)
+	}
+
+	if exp, recv := 1, len(w.ListJobs()); exp != recv {
+		t.Errorf("expected %d job(s), but got: %d", exp, recv)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	if _, err := w.JobStatus(id); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected '%v' after the TTL, but got: %v", ErrJobNotFound, err)
+	}
+
+	if _, err := w.SubmitTask(context.TODO(), "device-1", &Task{Command: "ping"}); !errors.Is(err, ErrWorkloadNotFound) {
+		t.Errorf("expected '%v', but got: %v", ErrWorkloadNotFound, err)
+	}
+}
+
+type blockingWorkload struct {
+	MockWorkload
+	release chan struct{}
+}
+
+func (b *blockingWorkload) RunTask(ctx context.Context, task *Task) (Result, error) {
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+
+	return Result{JobID: "overwritten", Command: task.Command, Success: true}, nil
+}
+
+type hangingWorkload struct {
+	MockWorkload
+}
+
+// Ignores its context, like a device which stopped answering
+func (h *hangingWorkload) RunTask(context.Context, *Task) (Result, error) {
+	time.Sleep(time.Hour)
+	return Result{}, nil
+}
+
+func TestTaskTimeout(t *testing.T) {
+	events := make(chan Event, 10)
+	w, err := New(context.TODO(), WithEventCallback(func(_ context.Context, e Event) {
+		events <- e
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &hangingWorkload{MockWorkload{name: "device-0"}}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	res, err := w.RunTask(context.TODO(), "device-0", &Task{Command: "show version", Timeout: 50 * time.Millisecond})
+	if !errors.Is(err, ErrTaskTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected '%v', but got: %v", ErrTaskTimeout, err)
+	}
+
+	if exp, recv := ResultTimeout, res.Status; exp != recv {
+		t.Errorf("expected a '%s' result, but got: '%s'", exp, recv)
+	}
+
+	waitEvent(t, events, EventTaskTimeout)
+}
+
+func TestCancelJob(t *testing.T) {
+	events := make(chan Event, 10)
+	w, err := New(context.TODO(), WithEventCallback(func(_ context.Context, e Event) {
+		events <- e
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &hangingWorkload{MockWorkload{name: "device-0"}}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	id, err := w.SubmitTask(context.TODO(), "device-0", &Task{Command: "show version"})
+	if err != nil {
+		t.Fatalf("unexpected error when submitting task: %v", err)
+	}
+
+	if err := w.CancelJob(id); err != nil {
+		t.Fatalf("unexpected error when cancelling job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+
+	res, err := w.JobResult(ctx, id)
+	if err != nil {
+		t.Fatalf("unexpected error when waiting for the result: %v", err)
+	}
+
+	if exp, recv := ResultCancelled, res.Status; exp != recv || !errors.Is(res.Error, ErrTaskCancelled) {
+		t.Errorf("expected a '%s' result, but got: %+v", exp, res)
+	}
+
+	if err := w.CancelJob(id); !errors.Is(err, ErrJobFinished) {
+		t.Errorf("expected '%v', but got: %v", ErrJobFinished, err)
+	}
+
+	if err := w.CancelJob("job0"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected '%v', but got: %v", ErrJobNotFound, err)
+	}
+
+	waitEvent(t, events, EventTaskCancelled)
+}
+
+func TestTaskTimeoutStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	w, err := New(ctx)
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &hangingWorkload{MockWorkload{name: "device-0"}}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	// nothing reads the events once the worker's context is done
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := w.RunTask(context.TODO(), "device-0", &Task{Command: "show version", Timeout: 50 * time.Millisecond})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrTaskTimeout) {
+			t.Errorf("expected '%v', but got: %v", ErrTaskTimeout, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected the task to return without anyone reading its events")
+	}
+}
+
+func waitEvent(t *testing.T, events <-chan Event, exp EventType) {
+	t.Helper()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case e := <-events:
+			if e.EventType == exp {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("expected a '%s' event", exp)
+		}
+	}
+}
+
+func TestTaskJSON(t *testing.T) {
+	task := Task{Command: "show version", Args: []string{"brief"}, Timeout: 30 * time.Second, Priority: 1}
+
+	b, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("unexpected error when marshalling: %v", err)
+	}
+
+	if exp, recv := `"timeout":"30s"`, string(b); !strings.Contains(recv, exp) {
+		t.Errorf("expected '%s' in %s", exp, recv)
+	}
+
+	var recv Task
+	if err := json.Unmarshal(b, &recv); err != nil {
+		t.Fatalf("unexpected error when unmarshalling: %v", err)
+	}
+
+	if !reflect.DeepEqual(task, recv) {
+		t.Errorf("expected '%+v', but got: %+v", task, recv)
+	}
+
+	if b, _ := json.Marshal(Task{Command: "show version"}); strings.Contains(string(b), "timeout") {
+		t.Errorf("expected no timeout in %s", b)
+	}
+
+	// integer nanoseconds are still taken from earlier versions
+	if err := json.Unmarshal([]byte(`{"command":"show version","timeout":1000000000}`), &recv); err != nil {
+		t.Fatalf("unexpected error when unmarshalling: %v", err)
+	}
+
+	if exp, recv := time.Second, recv.Timeout; exp != recv {
+		t.Errorf("expected '%v', but got: %v", exp, recv)
+	}
+
+	for _, input := range []string{`{"timeout":"soon"}`, `{"timeout":true}`} {
+		if err := json.Unmarshal([]byte(input), &recv); err == nil {
+			t.Errorf("expected an error for %s", input)
+		}
+	}
+}
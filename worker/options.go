@@ -5,6 +5,9 @@ import (
 	"time"
 
 	"github.com/go-co-op/gocron/v2"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
 )
 
 type Option func(*Worker)
@@ -52,6 +55,22 @@ func WithEventCallback(fn func(context.Context, Event)) Option {
 	}
 }
 
+// Add a callback that is executed with the envelope of every event,
+// the envelope is shared with the manager's events
+func WithEnvelopeCallback(fn func(context.Context, event.Envelope)) Option {
+	return WithEventCallback(func(ctx context.Context, e Event) {
+		fn(ctx, e.Envelope())
+	})
+}
+
+// Set the labels targets match the worker's workloads on, the same
+// labels the worker registers with its manager, default: none
+func WithLabels(labels map[string]string) Option {
+	return func(w *Worker) {
+		w.config.labels = labels
+	}
+}
+
 // Provide your own state storage implementation
 func WithExternalState(sr StateRepository) Option {
 	return func(w *Worker) {
@@ -66,8 +85,54 @@ func WithSchedulerOption(opt gocron.SchedulerOption) Option {
 	}
 }
 
+// Set the tracer provider used for spans around workloads and
+// task runs, default: none
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(w *Worker) {
+		w.config.tracerProvider = tp
+	}
+}
+
 func WithInitTimeout(t time.Duration) Option {
 	return func(w *Worker) {
 		w.initTimeout = t
 	}
 }
+
+// Set the time results of finished jobs are retained, default: 1 hour
+func WithJobTTL(t time.Duration) Option {
+	return func(w *Worker) {
+		w.jobTTL = t
+	}
+}
+
+// Set the max amount of tasks running on a workload at once, workloads
+// implementing TaskConcurrency set their own. Default: 1
+func WithTaskConcurrency(n int) Option {
+	return func(w *Worker) {
+		w.taskConcurrency = n
+	}
+}
+
+// Set the max amount of tasks waiting on a workload, 0 is unbounded.
+// Default: 100
+func WithTaskQueueDepth(n int) Option {
+	return func(w *Worker) {
+		w.taskQueueDepth = n
+	}
+}
+
+// Set a callback receiving the time each task waited in its workload's
+// task queue, default: none
+func WithTaskWaitCallback(fn func(workload string, wait time.Duration)) Option {
+	return func(w *Worker) {
+		w.config.waitCb = fn
+	}
+}
+
+// Set the sink receiving the results of scheduled tasks, default: none
+func WithResultSink(sink ResultSink) Option {
+	return func(w *Worker) {
+		w.resultSink = sink
+	}
+}
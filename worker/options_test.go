@@ -4,6 +4,10 @@ import (
 	"context"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/Telenor-NMS-SE/ottomato/event"
 )
 
 func TestWithWorkerID(t *testing.T) {
@@ -64,3 +68,34 @@ func TestWithEventCallback(t *testing.T) {
 		t.Errorf("expected worker to %d event callbacks registered, but got: %d", exp, recv)
 	}
 }
+
+func TestWithEnvelopeCallback(t *testing.T) {
+	w := Worker{}
+
+	var recv event.Envelope
+	opt := WithEnvelopeCallback(func(ctx context.Context, e event.Envelope) {
+		recv = e
+	})
+	opt(&w)
+
+	if exp, recv := 1, len(w.config.eventCbs); exp != recv {
+		t.Fatalf("expected worker to %d event callbacks registered, but got: %d", exp, recv)
+	}
+
+	w.config.eventCbs[0](context.TODO(), NewWorkloadDeadEvent("worker0", "workload0"))
+
+	if exp := event.Type("worker.workload.dead"); exp != recv.Type {
+		t.Errorf("expected callback to receive a '%s' envelope, but got: '%s'", exp, recv.Type)
+	}
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	w := Worker{}
+
+	tp := noop.NewTracerProvider()
+	WithTracerProvider(tp)(&w)
+
+	if w.config.tracerProvider != tp {
+		t.Errorf("expected tracer provider to be set")
+	}
+}
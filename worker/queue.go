@@ -0,0 +1,143 @@
+package worker
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrQueueFull = errors.New("task queue is full")
+
+// Implemented by workloads which take more or less concurrent tasks
+// than the worker's task concurrency
+type TaskConcurrency interface {
+	MaxConcurrentTasks() int
+}
+
+// Limits the tasks running on a workload at once. Waiting tasks are
+// started by priority, then in the order they were queued.
+type taskQueue struct {
+	mu      sync.Mutex
+	limit   int // max running tasks
+	depth   int // max waiting tasks, 0 is unbounded
+	running int
+	waiting waiters
+	seq     uint64
+}
+
+func newTaskQueue(limit, depth int) *taskQueue {
+	return &taskQueue{limit: max(limit, 1), depth: depth}
+}
+
+// Waits for a slot to run a task in, returning the time spent waiting.
+// The slot is given back with release.
+func (q *taskQueue) acquire(ctx context.Context, priority int) (time.Duration, error) {
+	start := time.Now()
+
+	q.mu.Lock()
+	if q.running < q.limit && len(q.waiting) == 0 {
+		q.running++
+		q.mu.Unlock()
+
+		return 0, nil
+	}
+
+	if q.depth > 0 && len(q.waiting) >= q.depth {
+		q.mu.Unlock()
+		return 0, ErrQueueFull
+	}
+
+	q.seq++
+	w := &waiter{priority: priority, seq: q.seq, ready: make(chan struct{})}
+	heap.Push(&q.waiting, w)
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return time.Since(start), nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	if w.index >= 0 {
+		heap.Remove(&q.waiting, w.index)
+		q.mu.Unlock()
+
+		return time.Since(start), ctx.Err()
+	}
+	q.mu.Unlock()
+
+	// handed a slot while giving up
+	q.release()
+
+	return time.Since(start), ctx.Err()
+}
+
+// Gives a slot back, handing it to the next waiting task
+func (q *taskQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.waiting) == 0 {
+		q.running--
+		return
+	}
+
+	w := heap.Pop(&q.waiting).(*waiter)
+	close(w.ready)
+}
+
+// Returns the amount of running and waiting tasks
+func (q *taskQueue) len() (int, int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.running, len(q.waiting)
+}
+
+type waiter struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+	index    int // position in the heap, -1 once popped
+}
+
+// Heap of waiting tasks, highest priority first
+type waiters []*waiter
+
+func (h waiters) Len() int {
+	return len(h)
+}
+
+func (h waiters) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+
+	return h[i].seq < h[j].seq
+}
+
+func (h waiters) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *waiters) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*h)
+	*h = append(*h, w)
+}
+
+func (h *waiters) Pop() any {
+	old := *h
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	w.index = -1
+	*h = old[:n-1]
+
+	return w
+}
@@ -0,0 +1,167 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTaskQueue(t *testing.T) {
+	q := newTaskQueue(1, 2)
+
+	if _, err := q.acquire(context.TODO(), 0); err != nil {
+		t.Fatalf("unexpected error when acquiring a free slot: %v", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		order []int
+		wg    sync.WaitGroup
+	)
+
+	for i, prio := range []int{0, 5} {
+		wg.Go(func() {
+			if _, err := q.acquire(context.TODO(), prio); err != nil {
+				t.Errorf("unexpected error when waiting for a slot: %v", err)
+				return
+			}
+
+			mu.Lock()
+			order = append(order, prio)
+			mu.Unlock()
+
+			q.release()
+		})
+
+		// queue them in a known order
+		waitFor(t, func() bool { _, n := q.len(); return n == i+1 })
+	}
+
+	if _, err := q.acquire(context.TODO(), 10); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected '%v', but got: %v", ErrQueueFull, err)
+	}
+
+	q.release()
+	wg.Wait()
+
+	if exp, recv := []int{5, 0}, order; !slices.Equal(exp, recv) {
+		t.Errorf("expected the tasks to leave the queue in the order %v, but got: %v", exp, recv)
+	}
+
+	if running, waiting := q.len(); running != 0 || waiting != 0 {
+		t.Errorf("expected an empty queue, but got %d running and %d waiting", running, waiting)
+	}
+}
+
+func TestTaskQueueCancel(t *testing.T) {
+	q := newTaskQueue(1, 0)
+
+	if _, err := q.acquire(context.TODO(), 0); err != nil {
+		t.Fatalf("unexpected error when acquiring a free slot: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := q.acquire(ctx, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected '%v', but got: %v", context.DeadlineExceeded, err)
+	}
+
+	if _, waiting := q.len(); waiting != 0 {
+		t.Errorf("expected the cancelled task to leave the queue, but %d are waiting", waiting)
+	}
+
+	q.release()
+
+	if running, _ := q.len(); running != 0 {
+		t.Errorf("expected no running tasks, but got: %d", running)
+	}
+}
+
+type sessionWorkload struct {
+	MockWorkload
+	limit          int
+	inflight, peak atomic.Int32
+}
+
+func (s *sessionWorkload) MaxConcurrentTasks() int {
+	return s.limit
+}
+
+func (s *sessionWorkload) RunTask(ctx context.Context, task *Task) (Result, error) {
+	n := s.inflight.Add(1)
+	defer s.inflight.Add(-1)
+
+	for {
+		p := s.peak.Load()
+		if n <= p || s.peak.CompareAndSwap(p, n) {
+			break
+		}
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	return Result{Success: true}, nil
+}
+
+func TestRunTaskQueued(t *testing.T) {
+	var waits atomic.Int32
+
+	w, err := New(context.TODO(), WithTaskWaitCallback(func(_ string, wait time.Duration) {
+		if wait > 0 {
+			waits.Add(1)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	serial := &sessionWorkload{MockWorkload: MockWorkload{name: "device-0"}, limit: 1}
+	parallel := &sessionWorkload{MockWorkload: MockWorkload{name: "device-1"}, limit: 3}
+
+	for _, wl := range []Workload{serial, parallel} {
+		if _, err := w.AddWorkload(context.TODO(), wl); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		for _, name := range []string{"device-0", "device-1"} {
+			wg.Go(func() {
+				if _, err := w.RunTask(context.TODO(), name, &Task{Command: "show version"}); err != nil {
+					t.Errorf("unexpected error when running task: %v", err)
+				}
+			})
+		}
+	}
+	wg.Wait()
+
+	if exp, recv := int32(1), serial.peak.Load(); exp != recv {
+		t.Errorf("expected at most %d task at once, but got: %d", exp, recv)
+	}
+
+	if recv := parallel.peak.Load(); recv > 3 {
+		t.Errorf("expected at most 3 tasks at once, but got: %d", recv)
+	}
+
+	if waits.Load() == 0 {
+		t.Errorf("expected queued tasks to report their wait")
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if cond() {
+			return
+		}
+	}
+
+	t.Fatalf("condition not met in time")
+}
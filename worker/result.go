@@ -6,6 +6,16 @@ import (
 	"time"
 )
 
+// Outcome of a task, set by the worker
+type ResultStatus string
+
+const (
+	ResultSuccess   ResultStatus = "success"
+	ResultFailure   ResultStatus = "failure"
+	ResultTimeout   ResultStatus = "timeout"
+	ResultCancelled ResultStatus = "cancelled"
+)
+
 type Result struct {
 	JobID         string         `json:"jobId"`
 	WorkerID      string         `json:"workerId"`
@@ -15,10 +25,12 @@ type Result struct {
 	Args          []string       `json:"args"`
 	Kwargs        map[string]any `json:"kwargs"`
 	Success       bool           `json:"success"`
+	Status        ResultStatus   `json:"status,omitempty"`
 	Error         error          `json:"error"`
 	Return        any            `json:"return"`
 	Timestamp     time.Time      `json:"timestamp"`
 	ExecutionTime int64          `json:"executionTime"`
+	WaitTime      int64          `json:"waitTime"` // milliseconds spent in the task queue
 }
 
 // Use a custom marshal to support *string errors
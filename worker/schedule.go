@@ -0,0 +1,311 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
+
+	"github.com/Telenor-NMS-SE/ottomato/target"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("schedule does not exist")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+)
+
+// Defines when a scheduled task runs, exactly one of the fields is set
+type Schedule struct {
+	// Crontab with five fields, or six with leading seconds
+	Cron     string        `json:"cron,omitempty"`
+	Interval time.Duration `json:"interval,omitempty"`
+	At       time.Time     `json:"at,omitzero"`
+}
+
+// Runs a task on a crontab, e.g. "0 2 * * *" for every night at 02:00
+func Cron(crontab string) Schedule {
+	return Schedule{Cron: crontab}
+}
+
+// Runs a task at a fixed interval
+func Every(interval time.Duration) Schedule {
+	return Schedule{Interval: interval}
+}
+
+// Runs a task once at the given time
+func At(t time.Time) Schedule {
+	return Schedule{At: t}
+}
+
+func (s Schedule) definition() (gocron.JobDefinition, error) {
+	switch {
+	case s.Cron != "" && s.Interval == 0 && s.At.IsZero():
+		return gocron.CronJob(s.Cron, len(strings.Fields(s.Cron)) == 6), nil
+	case s.Interval > 0 && s.Cron == "" && s.At.IsZero():
+		return gocron.DurationJob(s.Interval), nil
+	case !s.At.IsZero() && s.Cron == "" && s.Interval == 0:
+		return gocron.OneTimeJob(gocron.OneTimeJobStartDateTime(s.At)), nil
+	}
+
+	return nil, fmt.Errorf("%w: expected exactly one of cron, interval or at", ErrInvalidSchedule)
+}
+
+// Reports whether the schedule runs only once
+func (s Schedule) once() bool {
+	return s.Cron == "" && s.Interval == 0
+}
+
+// A task scheduled on the workloads matching a target
+type ScheduledTask struct {
+	ID       string    `json:"id"`
+	Target   string    `json:"target"`
+	Task     Task      `json:"task"`
+	Schedule Schedule  `json:"schedule"`
+	Paused   bool      `json:"paused"`
+	Runs     int       `json:"runs"`             // runs started, kept while paused
+	LastRun  time.Time `json:"lastRun,omitzero"` // start of the last run, kept while paused
+	NextRun  time.Time `json:"nextRun,omitzero"`
+}
+
+// The result of a scheduled task run
+type ScheduledResult struct {
+	ScheduleID string `json:"scheduleId"`
+	Workload   string `json:"workload"`
+	Result     Result `json:"result"`
+}
+
+// Receives the results of scheduled tasks
+type ResultSink func(context.Context, ScheduledResult)
+
+type schedule struct {
+	ScheduledTask
+	target  target.Target
+	running atomic.Bool // a run still going skips the next
+	pending bool        // a one-shot which found no workload to run on
+}
+
+// Schedules a task on the workloads matching a target, returning the id
+// of the schedule. The target is resolved on every run, so workloads
+// added later are picked up, and a run without any matching workload is
+// skipped. Results are passed to the result sink, one per workload.
+func (w *Worker) ScheduleTask(t target.Target, task *Task, s Schedule) (string, error) {
+	if t == nil {
+		return "", fmt.Errorf("%w: missing target", target.ErrInvalidTarget)
+	}
+
+	sc := &schedule{
+		ScheduledTask: ScheduledTask{
+			ID:       uuid.NewString(),
+			Target:   t.String(),
+			Task:     *task,
+			Schedule: s,
+		},
+		target: t,
+	}
+
+	w.schedulesMu.Lock()
+	defer w.schedulesMu.Unlock()
+
+	if err := w.startSchedule(sc); err != nil {
+		return "", err
+	}
+
+	w.schedules[sc.ID] = sc
+
+	return sc.ID, nil
+}
+
+// Adds the gocron job of a schedule, expects schedulesMu to be held
+func (w *Worker) startSchedule(sc *schedule) error {
+	def, err := sc.Schedule.definition()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.sc.NewJob(
+		def,
+		gocron.NewTask(w.runSchedule, sc),
+		gocron.WithIdentifier(uuid.MustParse(sc.ID)),
+		gocron.WithName("scheduled task "+sc.ID),
+		gocron.WithContext(w.ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSchedule, err)
+	}
+
+	return nil
+}
+
+// Runs a scheduled task in the background, so long tasks don't hold up
+// the scheduler's jobs
+func (w *Worker) runSchedule(sc *schedule) {
+	names := w.matching(sc.target)
+
+	w.schedulesMu.Lock()
+	defer w.schedulesMu.Unlock()
+
+	// removed, or a one-shot which is done already
+	if _, ok := w.schedules[sc.ID]; !ok {
+		return
+	}
+
+	// waits for matching workloads to be added, a one-shot runs as soon
+	// as one is
+	if len(names) == 0 {
+		sc.pending = sc.Schedule.once()
+		return
+	}
+
+	if !sc.running.CompareAndSwap(false, true) {
+		return
+	}
+
+	// one-shot schedules are done once they're dispatched
+	if sc.Schedule.once() {
+		delete(w.schedules, sc.ID)
+	}
+
+	// kept on the schedule, as its gocron job is recreated on resume
+	sc.pending = false
+	sc.Runs++
+	sc.LastRun = time.Now()
+
+	go func() {
+		defer sc.running.Store(false)
+
+		FanOut(w.ctx, names, func(ctx context.Context, name string) (Result, error) {
+			return w.RunTask(ctx, name, &sc.Task)
+		}, WithTargetTimeout(0), WithResultStream(func(name string, res Result) {
+			if w.resultSink != nil {
+				w.resultSink(w.ctx, ScheduledResult{ScheduleID: sc.ID, Workload: name, Result: res})
+			}
+		}))
+	}()
+}
+
+// Runs the one-shot schedules waiting for a workload to run on
+func (w *Worker) runPending() {
+	w.schedulesMu.Lock()
+	var pending []*schedule
+	for _, sc := range w.schedules {
+		if sc.pending && !sc.Paused {
+			pending = append(pending, sc)
+		}
+	}
+	w.schedulesMu.Unlock()
+
+	for _, sc := range pending {
+		w.runSchedule(sc)
+	}
+}
+
+// Stops a schedule from running until it's resumed
+func (w *Worker) PauseSchedule(id string) error {
+	w.schedulesMu.Lock()
+	defer w.schedulesMu.Unlock()
+
+	sc, ok := w.schedules[id]
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrScheduleNotFound, id)
+	}
+
+	if sc.Paused {
+		return nil
+	}
+
+	if err := w.sc.RemoveJob(uuid.MustParse(id)); err != nil && !errors.Is(err, gocron.ErrJobNotFound) {
+		return err
+	}
+
+	sc.Paused = true
+
+	return nil
+}
+
+// Resumes a paused schedule
+func (w *Worker) ResumeSchedule(id string) error {
+	w.schedulesMu.Lock()
+	defer w.schedulesMu.Unlock()
+
+	sc, ok := w.schedules[id]
+	if !ok {
+		return fmt.Errorf("%w: '%s'", ErrScheduleNotFound, id)
+	}
+
+	if !sc.Paused {
+		return nil
+	}
+
+	// fired while waiting for a workload, or due while it was paused, so
+	// it runs right away instead of recreating a job in the past
+	if sc.pending || (sc.Schedule.once() && !sc.Schedule.At.After(time.Now())) {
+		sc.Paused = false
+		go w.runSchedule(sc)
+
+		return nil
+	}
+
+	if err := w.startSchedule(sc); err != nil {
+		return err
+	}
+
+	sc.Paused = false
+
+	return nil
+}
+
+// Removes a schedule, a run in progress is left to finish
+func (w *Worker) RemoveSchedule(id string) error {
+	w.schedulesMu.Lock()
+	defer w.schedulesMu.Unlock()
+
+	if _, ok := w.schedules[id]; !ok {
+		return fmt.Errorf("%w: '%s'", ErrScheduleNotFound, id)
+	}
+
+	if err := w.sc.RemoveJob(uuid.MustParse(id)); err != nil && !errors.Is(err, gocron.ErrJobNotFound) {
+		return err
+	}
+
+	delete(w.schedules, id)
+
+	return nil
+}
+
+// Returns all schedules, ordered by target
+func (w *Worker) ListSchedules() []ScheduledTask {
+	w.schedulesMu.Lock()
+	defer w.schedulesMu.Unlock()
+
+	jobs := map[uuid.UUID]gocron.Job{}
+	for _, j := range w.sc.Jobs() {
+		jobs[j.ID()] = j
+	}
+
+	list := make([]ScheduledTask, 0, len(w.schedules))
+	for _, sc := range w.schedules {
+		st := sc.ScheduledTask
+
+		if j, ok := jobs[uuid.MustParse(sc.ID)]; ok {
+			st.NextRun, _ = j.NextRun()
+		}
+
+		list = append(list, st)
+	}
+
+	slices.SortFunc(list, func(a, b ScheduledTask) int {
+		if c := strings.Compare(a.Target, b.Target); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return list
+}
@@ -0,0 +1,339 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Telenor-NMS-SE/ottomato/target"
+)
+
+func TestScheduleTask(t *testing.T) {
+	results := make(chan ScheduledResult, 100)
+	w, err := New(context.TODO(), WithResultSink(func(_ context.Context, res ScheduledResult) {
+		results <- res
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &MockWorkload{name: "device-0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	id, err := w.ScheduleTask(target.List("device-0"), &Task{Command: "backup"}, Every(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error when scheduling task: %v", err)
+	}
+
+	res := waitResult(t, results)
+	if res.ScheduleID != id || res.Workload != "device-0" || res.Result.Status != ResultSuccess {
+		t.Errorf("expected a successful result of schedule '%s', but got: %+v", id, res)
+	}
+
+	list := w.ListSchedules()
+	if exp, recv := 1, len(list); exp != recv {
+		t.Fatalf("expected %d schedule(s), but got: %d", exp, recv)
+	}
+
+	if list[0].Task.Command != "backup" || list[0].NextRun.IsZero() {
+		t.Errorf("expected a scheduled 'backup' with a next run, but got: %+v", list[0])
+	}
+
+	if err := w.PauseSchedule(id); err != nil {
+		t.Fatalf("unexpected error when pausing schedule: %v", err)
+	}
+
+	expectNoResult(t, results)
+
+	if !w.ListSchedules()[0].Paused {
+		t.Errorf("expected the schedule to be paused")
+	}
+
+	if err := w.ResumeSchedule(id); err != nil {
+		t.Fatalf("unexpected error when resuming schedule: %v", err)
+	}
+
+	waitResult(t, results)
+
+	// the schedule follows the workload when it's deleted and added back
+	if err := w.DeleteWorkload("device-0"); err != nil {
+		t.Fatalf("failed to delete workload: %v", err)
+	}
+
+	expectNoResult(t, results)
+
+	if _, err := w.AddWorkload(context.TODO(), &MockWorkload{name: "device-0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	waitResult(t, results)
+
+	if err := w.RemoveSchedule(id); err != nil {
+		t.Fatalf("unexpected error when removing schedule: %v", err)
+	}
+
+	if err := w.RemoveSchedule(id); !errors.Is(err, ErrScheduleNotFound) {
+		t.Errorf("expected '%v', but got: %v", ErrScheduleNotFound, err)
+	}
+
+	if exp, recv := 0, len(w.ListSchedules()); exp != recv {
+		t.Errorf("expected %d schedule(s), but got: %d", exp, recv)
+	}
+}
+
+func TestScheduleTaskOnce(t *testing.T) {
+	results := make(chan ScheduledResult, 10)
+	w, err := New(context.TODO(), WithResultSink(func(_ context.Context, res ScheduledResult) {
+		results <- res
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &MockWorkload{name: "device-0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	if _, err := w.ScheduleTask(target.List("device-0"), &Task{Command: "reboot"}, At(time.Now().Add(20*time.Millisecond))); err != nil {
+		t.Fatalf("unexpected error when scheduling task: %v", err)
+	}
+
+	waitResult(t, results)
+	expectNoResult(t, results)
+
+	if exp, recv := 0, len(w.ListSchedules()); exp != recv {
+		t.Errorf("expected a one-shot schedule to be done, but got %d schedule(s)", recv)
+	}
+
+	cases := []struct {
+		target   target.Target
+		schedule Schedule
+		exp      error
+	}{
+		{nil, Every(time.Minute), target.ErrInvalidTarget},
+		{target.List("device-0"), Schedule{}, ErrInvalidSchedule},
+		{target.List("device-0"), Schedule{Cron: "0 2 * * *", Interval: time.Hour}, ErrInvalidSchedule},
+		{target.List("device-0"), Cron("not a crontab"), ErrInvalidSchedule},
+		{target.List("device-0"), At(time.Now().Add(-time.Hour)), ErrInvalidSchedule},
+	}
+
+	for _, c := range cases {
+		if _, err := w.ScheduleTask(c.target, &Task{Command: "backup"}, c.schedule); !errors.Is(err, c.exp) {
+			t.Errorf("expected '%v' for %+v, but got: %v", c.exp, c.schedule, err)
+		}
+	}
+
+	if _, err := w.ScheduleTask(target.List("device-0"), &Task{Command: "backup"}, Cron("0 2 * * *")); err != nil {
+		t.Errorf("unexpected error when scheduling a nightly task: %v", err)
+	}
+}
+
+func TestScheduleTaskOnceWaits(t *testing.T) {
+	results := make(chan ScheduledResult, 10)
+	w, err := New(context.TODO(), WithResultSink(func(_ context.Context, res ScheduledResult) {
+		results <- res
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	// the workload is gone when the schedule fires
+	if _, err := w.ScheduleTask(target.List("device-0"), &Task{Command: "reboot"}, At(time.Now().Add(20*time.Millisecond))); err != nil {
+		t.Fatalf("unexpected error when scheduling task: %v", err)
+	}
+
+	expectNoResult(t, results)
+
+	if exp, recv := 1, len(w.ListSchedules()); exp != recv {
+		t.Fatalf("expected the one-shot schedule to wait for its workload, but got %d schedule(s)", recv)
+	}
+
+	if _, err := w.AddWorkload(context.TODO(), &MockWorkload{name: "device-0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	if exp, recv := "device-0", waitResult(t, results).Workload; exp != recv {
+		t.Errorf("expected a result of '%s', but got: '%s'", exp, recv)
+	}
+
+	expectNoResult(t, results)
+
+	if exp, recv := 0, len(w.ListSchedules()); exp != recv {
+		t.Errorf("expected a one-shot schedule to be done, but got %d schedule(s)", recv)
+	}
+}
+
+func TestScheduleTarget(t *testing.T) {
+	results := make(chan ScheduledResult, 100)
+	w, err := New(context.TODO(), WithResultSink(func(_ context.Context, res ScheduledResult) {
+		results <- res
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &deviceWorkload{MockWorkload: MockWorkload{name: "device-0"}, vendor: "cisco"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	// every cisco device, including the ones added later
+	if _, err := w.ScheduleTask(target.Label("vendor", "cisco"), &Task{Command: "backup"}, Every(20*time.Millisecond)); err != nil {
+		t.Fatalf("unexpected error when scheduling task: %v", err)
+	}
+
+	if exp, recv := "device-0", waitResult(t, results).Workload; exp != recv {
+		t.Errorf("expected a result of '%s', but got: '%s'", exp, recv)
+	}
+
+	for i, vendor := range []string{"cisco", "juniper"} {
+		wl := &deviceWorkload{MockWorkload: MockWorkload{name: fmt.Sprintf("device-%d", i+1)}, vendor: vendor}
+		if _, err := w.AddWorkload(context.TODO(), wl); err != nil {
+			t.Fatalf("failed to add workload: %v", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	deadline := time.After(time.Second)
+	for !seen["device-1"] {
+		select {
+		case res := <-results:
+			seen[res.Workload] = true
+		case <-deadline:
+			t.Fatalf("expected a result of the added workload, but got: %v", seen)
+		}
+	}
+
+	if seen["device-2"] {
+		t.Errorf("expected no results of workloads not matching the target")
+	}
+
+	if exp, recv := "label:vendor=cisco", w.ListSchedules()[0].Target; exp != recv {
+		t.Errorf("expected target '%s', but got: '%s'", exp, recv)
+	}
+}
+
+func TestScheduleResumeKeepsRuns(t *testing.T) {
+	results := make(chan ScheduledResult, 100)
+	w, err := New(context.TODO(), WithResultSink(func(_ context.Context, res ScheduledResult) {
+		results <- res
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &MockWorkload{name: "device-0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	id, err := w.ScheduleTask(target.List("device-0"), &Task{Command: "backup"}, Every(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error when scheduling task: %v", err)
+	}
+
+	waitResult(t, results)
+
+	if err := w.PauseSchedule(id); err != nil {
+		t.Fatalf("unexpected error when pausing schedule: %v", err)
+	}
+
+	expectNoResult(t, results)
+
+	paused := w.ListSchedules()[0]
+	if paused.LastRun.IsZero() || paused.Runs == 0 {
+		t.Fatalf("expected the paused schedule to keep its runs, but got: %+v", paused)
+	}
+
+	if err := w.ResumeSchedule(id); err != nil {
+		t.Fatalf("unexpected error when resuming schedule: %v", err)
+	}
+
+	resumed := w.ListSchedules()[0]
+	if !resumed.LastRun.Equal(paused.LastRun) || resumed.Runs != paused.Runs {
+		t.Errorf("expected last run %s and %d run(s) after resuming, but got: %+v", paused.LastRun, paused.Runs, resumed)
+	}
+
+	waitResult(t, results)
+
+	recv := w.ListSchedules()[0]
+	if recv.Runs <= paused.Runs || !recv.LastRun.After(paused.LastRun) {
+		t.Errorf("expected the resumed schedule to count on from %d run(s), but got: %+v", paused.Runs, recv)
+	}
+}
+
+func TestScheduleResumeOnceDue(t *testing.T) {
+	results := make(chan ScheduledResult, 10)
+	w, err := New(context.TODO(), WithResultSink(func(_ context.Context, res ScheduledResult) {
+		results <- res
+	}))
+	if err != nil {
+		t.Fatalf("could not create new worker: %v", err)
+	}
+	defer w.Stop()
+
+	if _, err := w.AddWorkload(context.TODO(), &MockWorkload{name: "device-0"}); err != nil {
+		t.Fatalf("failed to add workload: %v", err)
+	}
+
+	id, err := w.ScheduleTask(target.List("device-0"), &Task{Command: "reboot"}, At(time.Now().Add(50*time.Millisecond)))
+	if err != nil {
+		t.Fatalf("unexpected error when scheduling task: %v", err)
+	}
+
+	if err := w.PauseSchedule(id); err != nil {
+		t.Fatalf("unexpected error when pausing schedule: %v", err)
+	}
+
+	// due while paused
+	expectNoResult(t, results)
+
+	if err := w.ResumeSchedule(id); err != nil {
+		t.Fatalf("unexpected error when resuming schedule: %v", err)
+	}
+
+	if exp, recv := "device-0", waitResult(t, results).Workload; exp != recv {
+		t.Errorf("expected a result of '%s', but got: '%s'", exp, recv)
+	}
+
+	expectNoResult(t, results)
+
+	if exp, recv := 0, len(w.ListSchedules()); exp != recv {
+		t.Errorf("expected a one-shot schedule to be done, but got %d schedule(s)", recv)
+	}
+}
+
+func waitResult(t *testing.T, results <-chan ScheduledResult) ScheduledResult {
+	t.Helper()
+
+	select {
+	case res := <-results:
+		return res
+	case <-time.After(time.Second):
+		t.Fatalf("expected a scheduled task result")
+	}
+
+	return ScheduledResult{}
+}
+
+// Expects no results after letting in-flight runs finish
+func expectNoResult(t *testing.T, results chan ScheduledResult) {
+	t.Helper()
+
+	time.Sleep(50 * time.Millisecond)
+	for len(results) > 0 {
+		<-results
+	}
+
+	select {
+	case res := <-results:
+		t.Errorf("expected no results, but got: %+v", res)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const instrumentationName = "github.com/Telenor-NMS-SE/ottomato/worker"
+
+// Attribute keys shared by the worker's spans
+const (
+	attrWorkerID = attribute.Key("ottomato.worker.id")
+	attrWorkload = attribute.Key("ottomato.workload.name")
+	attrCommand  = attribute.Key("ottomato.task.command")
+)
+
+func (w *Worker) tracer() trace.Tracer {
+	if w.config.tracerProvider == nil {
+		return noop.NewTracerProvider().Tracer(instrumentationName)
+	}
+
+	return w.config.tracerProvider.Tracer(instrumentationName)
+}
+
+// Ends a span, recording the error if there was one
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
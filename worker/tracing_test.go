@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	w, err := New(context.Background(), WithTracerProvider(tp))
+	if err != nil {
+		t.Fatalf("could not create new worker: %s", err.Error())
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "manager.RunTask")
+
+	if _, err := w.AddWorkload(ctx, &MockWorkload{name: "test"}); err != nil {
+		t.Fatalf("failed to add new workload; %s", err.Error())
+	}
+
+	if _, err := w.RunTask(ctx, "test", &Task{Command: "hello"}); err != nil {
+		t.Fatalf("failed to run task: %s", err.Error())
+	}
+
+	if _, err := w.RunTask(ctx, "missing", &Task{Command: "hello"}); err == nil {
+		t.Fatalf("expected an error when running a task on a missing workload")
+	}
+
+	parent.End()
+
+	var runs int
+	for _, s := range exporter.GetSpans() {
+		if s.Name != "worker.RunTask" && s.Name != "worker.AddWorkload" {
+			continue
+		}
+
+		if exp, recv := parent.SpanContext().SpanID(), s.Parent.SpanID(); exp != recv {
+			t.Errorf("expected '%s' to be a child of the caller's span", s.Name)
+		}
+
+		if s.Name == "worker.RunTask" {
+			runs++
+		}
+	}
+
+	if exp, recv := 2, runs; exp != recv {
+		t.Errorf("expected %d task run spans, but got: %d", exp, recv)
+	}
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
@@ -9,7 +10,9 @@ import (
 
 	"github.com/go-co-op/gocron/v2"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/Telenor-NMS-SE/ottomato/event"
 	"github.com/Telenor-NMS-SE/ottomato/store"
 )
 
@@ -33,6 +36,17 @@ type (
 		initQueueCh chan string
 		stopQueueCh chan Workload
 
+		jobsMu sync.Mutex
+		jobs   map[string]*job
+		jobTTL time.Duration // time results of finished jobs are retained
+
+		taskConcurrency int // max tasks running on a workload at once
+		taskQueueDepth  int // max tasks waiting on a workload, 0 is unbounded
+
+		schedulesMu sync.Mutex
+		schedules   map[string]*schedule
+		resultSink  ResultSink
+
 		config config
 	}
 	config struct {
@@ -43,6 +57,10 @@ type (
 		pingTimeout time.Duration
 		eventCbs    []func(context.Context, Event)
 		errCb       func(error)
+		labels      map[string]string
+		waitCb      func(string, time.Duration)
+
+		tracerProvider trace.TracerProvider
 	}
 )
 
@@ -57,6 +75,7 @@ type (
 	}
 	workload struct {
 		object    Workload
+		queue     *taskQueue
 		jid       uuid.UUID
 		createdAt time.Time
 		updatedAt time.Time
@@ -67,6 +86,77 @@ type Task struct {
 	Command string         `json:"command"`
 	Args    []string       `json:"args"`
 	Kwargs  map[string]any `json:"kwargs"`
+	// Max time the task may run, 0 is bound by the caller's context only.
+	// Encoded as a duration string, e.g. "30s"
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Tasks with a higher priority leave the workload's task queue first
+	Priority int `json:"priority,omitempty"`
+}
+
+// Encodes the timeout as a duration string, e.g. "30s", like the
+// timeouts taken by the admin API
+func (t Task) MarshalJSON() ([]byte, error) {
+	type task Task
+
+	tmp := struct {
+		task
+		Timeout string `json:"timeout,omitempty"`
+	}{
+		task: task(t),
+	}
+
+	if t.Timeout != 0 {
+		tmp.Timeout = t.Timeout.String()
+	}
+
+	return json.Marshal(tmp)
+}
+
+// Decodes the timeout from a duration string, or from integer
+// nanoseconds as sent by earlier versions
+func (t *Task) UnmarshalJSON(input []byte) error {
+	type task Task
+
+	tmp := struct {
+		task
+		Timeout json.RawMessage `json:"timeout,omitempty"`
+	}{
+		task: task(*t),
+	}
+
+	if err := json.Unmarshal(input, &tmp); err != nil {
+		return err
+	}
+
+	*t = Task(tmp.task)
+	t.Timeout = 0
+
+	if len(tmp.Timeout) == 0 || string(tmp.Timeout) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(tmp.Timeout, &s); err != nil {
+		var ns int64
+		if err := json.Unmarshal(tmp.Timeout, &ns); err != nil {
+			return fmt.Errorf("invalid task timeout: %s", tmp.Timeout)
+		}
+
+		t.Timeout = time.Duration(ns)
+		return nil
+	}
+
+	if s == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid task timeout: %w", err)
+	}
+
+	t.Timeout = d
+	return nil
 }
 
 type (
@@ -89,6 +179,8 @@ var (
 	ErrWorkloadNotFound = errors.New("workload does not exist")
 	ErrWorkloadExists   = errors.New("workload already exist")
 	ErrScheduleCleanup  = errors.New("failed to clean up scheduler")
+	ErrTaskTimeout      = errors.New("task timed out")
+	ErrTaskCancelled    = errors.New("task cancelled")
 )
 
 // Create a new worker instance with default options, override with []Option
@@ -114,6 +206,12 @@ func New(ctx context.Context, opts ...Option) (*Worker, error) {
 		initTimeout: 25 * time.Second,
 		initQueueCh: make(chan string, 192),
 		stopQueueCh: make(chan Workload, 192),
+		jobs:        map[string]*job{},
+		jobTTL:      time.Hour,
+
+		taskConcurrency: 1,
+		taskQueueDepth:  100,
+		schedules:       map[string]*schedule{},
 	}
 
 	worker.config.eventCbs = append(worker.config.eventCbs, worker.stateUpdateCb)
@@ -162,8 +260,24 @@ func (w *Worker) Stop() error {
 	return w.sc.Shutdown()
 }
 
-// Run a task on the given target
+// Run a task on the given target, bound by the task timeout. Tasks wait
+// in the workload's task queue for their turn, or fail with ErrQueueFull
+// when it's full. A task still running when it times out or its context
+// is cancelled is abandoned, returning ErrTaskTimeout or ErrTaskCancelled,
+// but holds on to its place in the queue until it returns.
 func (w *Worker) RunTask(ctx context.Context, target string, task *Task) (Result, error) {
+	return w.runTask(ctx, target, task, nil)
+}
+
+// Runs a task, calling started when it leaves the task queue
+func (w *Worker) runTask(ctx context.Context, target string, task *Task, started func()) (res Result, err error) {
+	ctx, span := w.tracer().Start(ctx, "worker.RunTask", trace.WithAttributes(
+		attrWorkerID.String(w.config.id),
+		attrWorkload.String(target),
+		attrCommand.String(task.Command),
+	))
+	defer func() { endSpan(span, err) }()
+
 	start := time.Now()
 
 	w.workloadsMu.RLock()
@@ -174,18 +288,114 @@ func (w *Worker) RunTask(ctx context.Context, target string, task *Task) (Result
 	}
 	w.workloadsMu.RUnlock()
 
-	job, err := wl.object.RunTask(ctx, task)
+	wait, err := wl.queue.acquire(ctx, task.Priority)
+	if w.config.waitCb != nil {
+		w.config.waitCb(target, wait)
+	}
+
+	if errors.Is(err, ErrQueueFull) {
+		return Result{WorkerID: w.config.id, Timestamp: start, Status: ResultFailure}, fmt.Errorf("%w: '%s'", ErrQueueFull, target)
+	}
+
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
+
+	var job Result
+	run := time.Now()
+	if err == nil {
+		if started != nil {
+			started()
+		}
+
+		job, err = callTask(ctx, wl.object, task, wl.queue.release)
+	}
 
 	// add a bit of metadata
 	job.Timestamp = start
-	job.ExecutionTime = time.Since(start).Milliseconds()
+	job.ExecutionTime = time.Since(run).Milliseconds()
+	job.WaitTime = wait.Milliseconds()
 	job.WorkerID = w.config.id
 
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		job.Status = ResultTimeout
+		err = fmt.Errorf("%w after %s: %w", ErrTaskTimeout, time.Since(run).Round(time.Millisecond), ctx.Err())
+		w.emit(NewTaskTimeoutEvent(w.config.id, target, fmt.Sprintf("task '%s' timed out", task.Command)))
+	case err != nil && ctx.Err() != nil:
+		job.Status = ResultCancelled
+		err = fmt.Errorf("%w: %w", ErrTaskCancelled, ctx.Err())
+		w.emit(NewTaskCancelledEvent(w.config.id, target, fmt.Sprintf("task '%s' cancelled", task.Command)))
+	case err != nil, job.Error != nil:
+		job.Status = ResultFailure
+	default:
+		job.Status = ResultSuccess
+	}
+
 	return job, err
 }
 
+// Sends an event to the event loop, dropping it once the worker's
+// context is done and nothing reads the events anymore
+func (w *Worker) emit(e Event) {
+	select {
+	case w.EventCh <- e:
+	case <-w.ctx.Done():
+	}
+}
+
+// Runs a task on a workload, abandoning it when ctx is done first.
+// Calls done once the workload has returned.
+func callTask(ctx context.Context, wl Workload, task *Task, done func()) (Result, error) {
+	if err := ctx.Err(); err != nil {
+		done()
+		return Result{}, err
+	}
+
+	type call struct {
+		res Result
+		err error
+	}
+
+	ch := make(chan call, 1)
+	go func() {
+		res, err := wl.RunTask(ctx, task)
+		done()
+		ch <- call{res, err}
+	}()
+
+	select {
+	case c := <-ch:
+		return c.res, c.err
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+}
+
+// Returns the amount of tasks running and waiting on a workload
+func (w *Worker) TaskQueueLen(target string) (running, waiting int, err error) {
+	w.workloadsMu.RLock()
+	wl, exists := w.workloads[target]
+	w.workloadsMu.RUnlock()
+
+	if !exists {
+		return 0, 0, ErrWorkloadNotFound
+	}
+
+	running, waiting = wl.queue.len()
+	return running, waiting, nil
+}
+
 // Adds a new workload to the worker
-func (w *Worker) AddWorkload(ctx context.Context, wl Workload) (map[string]any, error) {
+func (w *Worker) AddWorkload(ctx context.Context, wl Workload) (_ map[string]any, err error) {
+	ctx, span := w.tracer().Start(ctx, "worker.AddWorkload", trace.WithAttributes(
+		attrWorkerID.String(w.config.id),
+		attrWorkload.String(wl.Name()),
+	))
+	defer func() { endSpan(span, err) }()
+
 	w.workloadsMu.Lock()
 	defer w.workloadsMu.Unlock()
 
@@ -200,8 +410,14 @@ func (w *Worker) AddWorkload(ctx context.Context, wl Workload) (map[string]any,
 		return meta, ErrWorkloadExists
 	}
 
+	limit := w.taskConcurrency
+	if tc, ok := wl.(TaskConcurrency); ok {
+		limit = tc.MaxConcurrentTasks()
+	}
+
 	w.workloads[wl.Name()] = workload{
 		object:    wl,
+		queue:     newTaskQueue(limit, w.taskQueueDepth),
 		createdAt: now,
 		updatedAt: now,
 	}
@@ -209,7 +425,12 @@ func (w *Worker) AddWorkload(ctx context.Context, wl Workload) (map[string]any,
 	w.enqueueInit(wl.Name())
 
 	w.sr.RegisterWorkload(wl.Name(), w.GetWorkerID())
-	w.EventCh <- NewWorkloadAddedEvent(w.config.id, wl.Name())
+	e := NewWorkloadAddedEvent(w.config.id, wl.Name())
+	e.CorrelationID = event.CorrelationID(ctx)
+	w.EventCh <- e
+
+	// once the workload is in place, after the lock is released
+	go w.runPending()
 
 	return meta, nil
 
@@ -355,8 +576,21 @@ func (w *Worker) eventLoop() {
 	for {
 		select {
 		case e := <-w.EventCh:
+			if e.ID == "" {
+				e.ID = event.NewID()
+			}
+
+			if e.Time.IsZero() {
+				e.Time = time.Now().UTC()
+			}
+
+			ctx := w.ctx
+			if e.CorrelationID != "" {
+				ctx = event.WithCorrelationID(ctx, e.CorrelationID)
+			}
+
 			for _, fn := range w.config.eventCbs {
-				go fn(w.ctx, e)
+				go fn(ctx, e)
 			}
 		case <-w.ctx.Done():
 			return